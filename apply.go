@@ -0,0 +1,118 @@
+package wiremock
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ApplyResult is the ids of stub mappings touched by Client.Apply.
+type ApplyResult struct {
+	Created   []string
+	Updated   []string
+	Deleted   []string
+	Unchanged []string
+}
+
+// MetadataApplyOwner is the metadata key of the owner of the stubs registered by Client.Apply.
+const MetadataApplyOwner = "applyOwner"
+
+// Apply makes the registered stub mappings of owner equal to stubs, so several owners, e.g. tools or test suites,
+// can reconcile their stubs on one shared server. The stubs are registered with the owner in MetadataApplyOwner metadata.
+// Stubs are matched to registered mappings by id, as built with WithID or WithDeterministicID, or else by content
+// to the equal mapping of the owner (see StubRule.Equal), which is kept under its id.
+// Missing stubs are created, changed ones are updated and the mappings of the owner absent from stubs are deleted.
+// Mappings of other owners and the ones registered without Apply are left alone, unless stubs have their ids.
+func (c *Client) Apply(owner string, stubs []*StubRule) (ApplyResult, error) {
+	var result ApplyResult
+	if owner == "" {
+		return result, errors.New("apply: owner must not be empty")
+	}
+
+	registered, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return result, fmt.Errorf("apply: %s", err.Error())
+	}
+
+	registeredByID := make(map[string]*StubRule, len(registered))
+	var owned []*StubRule
+	for _, stubRule := range registered {
+		registeredByID[stubRule.UUID()] = stubRule
+		if stubRule.metadata[MetadataApplyOwner] == owner {
+			owned = append(owned, stubRule)
+		}
+	}
+
+	var errs []error
+	desired := make(map[string]bool, len(stubs))
+	var unmatched []*StubRule
+	for _, stubRule := range stubs {
+		id := stubRule.UUID()
+		if desired[id] {
+			errs = append(errs, fmt.Errorf("%s: duplicated stub id", id))
+			continue
+		}
+		desired[id] = true
+
+		ownedStub := stubRule.Clone().WithID(id).WithMetadata(MetadataApplyOwner, owner)
+		current, ok := registeredByID[id]
+		if !ok {
+			unmatched = append(unmatched, ownedStub)
+			continue
+		}
+
+		if current.Equal(ownedStub) {
+			result.Unchanged = append(result.Unchanged, id)
+			continue
+		}
+
+		if err := c.UpdateStub(id, ownedStub); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		result.Updated = append(result.Updated, id)
+	}
+
+	for _, stubRule := range unmatched {
+		if current := equalUnmatched(owned, stubRule, desired); current != nil {
+			desired[current.UUID()] = true
+			result.Unchanged = append(result.Unchanged, current.UUID())
+			continue
+		}
+
+		if err := c.StubFor(stubRule); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", stubRule.UUID(), err.Error()))
+			continue
+		}
+		result.Created = append(result.Created, stubRule.UUID())
+	}
+
+	for _, stubRule := range owned {
+		id := stubRule.UUID()
+		if desired[id] {
+			continue
+		}
+
+		if err := c.DeleteStubByID(id); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		result.Deleted = append(result.Deleted, id)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return result, fmt.Errorf("apply: %w", err)
+	}
+
+	return result, nil
+}
+
+// equalUnmatched gives the mapping of owned equal to stubRule and not matched yet, nil when there is none.
+func equalUnmatched(owned []*StubRule, stubRule *StubRule, matched map[string]bool) *StubRule {
+	for _, current := range owned {
+		if !matched[current.UUID()] && current.Equal(stubRule) {
+			return current
+		}
+	}
+
+	return nil
+}
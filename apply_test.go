@@ -0,0 +1,59 @@
+package wiremock
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient_Apply(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	kept := Get(URLPathEqualTo("/kept"))
+	changed := Get(URLPathEqualTo("/changed")).WithID("0d6a4e1e-1c1f-4b8e-9c57-000000000002")
+	removed := Get(URLPathEqualTo("/removed")).WithID("0d6a4e1e-1c1f-4b8e-9c57-000000000003")
+	if _, err := client.Apply("orders", []*StubRule{kept, changed, removed}); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	unrelated := Get(URLPathEqualTo("/unrelated")).WithID("0d6a4e1e-1c1f-4b8e-9c57-000000000005")
+	if err := client.StubFor(unrelated); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+	otherOwner := Get(URLPathEqualTo("/removed")).WithID("0d6a4e1e-1c1f-4b8e-9c57-000000000006")
+	if _, err := client.Apply("users", []*StubRule{otherOwner}); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	created := Get(URLPathEqualTo("/created")).WithID("0d6a4e1e-1c1f-4b8e-9c57-000000000004")
+	result, err := client.Apply("orders", []*StubRule{
+		// rebuilt with a new random id, matched by content
+		Get(URLPathEqualTo("/kept")),
+		Get(URLPathEqualTo("/changed")).WithID(changed.UUID()).WillReturn("new", nil, 200),
+		created,
+	})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	expected := ApplyResult{
+		Created:   []string{created.UUID()},
+		Updated:   []string{changed.UUID()},
+		Deleted:   []string{removed.UUID()},
+		Unchanged: []string{kept.UUID()},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected result %+v; got %+v", expected, result)
+	}
+	for _, id := range []string{unrelated.UUID(), otherOwner.UUID()} {
+		if _, err := client.GetStub(id); err != nil {
+			t.Errorf("expected stub %s not owned by the apply to survive; got %v", id, err)
+		}
+	}
+	if count := server.mappingCount(); count != 5 {
+		t.Errorf("expected 5 mappings; got %d", count)
+	}
+	if kept.Metadata()[MetadataApplyOwner] != nil {
+		t.Errorf("expected the applied stub not changed; got metadata %v", kept.Metadata())
+	}
+}
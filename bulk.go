@@ -0,0 +1,229 @@
+package wiremock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// defaultBulkBatchSize is the number of stubs imported by one request of ImportPrepared.
+const defaultBulkBatchSize = 1000
+
+// bulkImportOptions is the import options JSON of ImportPrepared, the same as of ImportStubs.
+const bulkImportOptions = `],"importOptions":{"deleteAllNotInImport":false,"duplicatePolicy":"OVERWRITE"}}`
+
+// bulkBuffers are the buffers of import request bodies reused between batches.
+var bulkBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// PreparedStubs are stubs validated, checked against the server and encoded once by PrepareStubs,
+// so seeding them again, e.g. before every run of a load test, costs the requests only.
+// Later changes of the stubs are not seen by PreparedStubs.
+type PreparedStubs struct {
+	ids      []string
+	mappings [][]byte
+}
+
+// Len gives the number of the prepared stubs.
+func (p *PreparedStubs) Len() int {
+	return len(p.mappings)
+}
+
+// PrepareStubs validates and encodes stubs for ImportPrepared, uploading body files the stubs carry.
+func (c *Client) PrepareStubs(stubs []*StubRule) (*PreparedStubs, error) {
+	prepared := &PreparedStubs{
+		ids:      make([]string, len(stubs)),
+		mappings: make([][]byte, len(stubs)),
+	}
+	for i, stubRule := range stubs {
+		if err := stubRule.Validate(); err != nil {
+			return nil, fmt.Errorf("prepare stubs: invalid stub %s: %s", stubRule.UUID(), err.Error())
+		}
+		if err := c.checkSupported(stubRule); err != nil {
+			return nil, fmt.Errorf("prepare stubs: stub %s: %w", stubRule.UUID(), err)
+		}
+		if err := c.uploadBodyFile(context.Background(), stubRule); err != nil {
+			return nil, fmt.Errorf("prepare stubs: %s", err.Error())
+		}
+
+		mapping, err := c.codec.Marshal(c.stubJSON(stubRule))
+		if err != nil {
+			return nil, fmt.Errorf("prepare stubs: build error of stub %s: %s", stubRule.UUID(), err.Error())
+		}
+		prepared.ids[i] = stubRule.UUID()
+		prepared.mappings[i] = mapping
+	}
+
+	return prepared, nil
+}
+
+// A BulkImport configures ImportPrepared.
+type BulkImport struct {
+	batchSize   int
+	parallelism int
+	progress    func(imported, total int)
+}
+
+// NewBulkImport returns *BulkImport importing 1000 stubs per request, one request at a time.
+func NewBulkImport() *BulkImport {
+	return &BulkImport{
+		batchSize:   defaultBulkBatchSize,
+		parallelism: 1,
+	}
+}
+
+// WithBatchSize sets the number of stubs imported by one request and returns *BulkImport
+func (b *BulkImport) WithBatchSize(batchSize int) *BulkImport {
+	b.batchSize = batchSize
+	return b
+}
+
+// WithParallelism sets the number of import requests in flight and returns *BulkImport.
+// Raise WithMaxIdleConnsPerHost to parallelism, so the connections are reused.
+func (b *BulkImport) WithParallelism(parallelism int) *BulkImport {
+	b.parallelism = parallelism
+	return b
+}
+
+// WithProgress sets the callback of imported stubs, called after every imported batch, one call at a time,
+// and returns *BulkImport
+func (b *BulkImport) WithProgress(progress func(imported, total int)) *BulkImport {
+	b.progress = progress
+	return b
+}
+
+// ImportPrepared imports prepared stubs in batches, overwriting the registered ones with the same ids,
+// for seeding tens of thousands of stubs:
+//
+//	prepared, err := client.PrepareStubs(stubs)
+//	...
+//	err = client.ImportPrepared(ctx, prepared, wiremock.NewBulkImport().WithParallelism(4).
+//		WithProgress(func(imported, total int) { log.Printf("%d/%d stubs", imported, total) }))
+//
+// The bodies of the requests are assembled of the prepared JSON in reused buffers and compressed by WithGzipRequests.
+// The first failed batch stops importing, the batches imported before it stay registered.
+func (c *Client) ImportPrepared(ctx context.Context, prepared *PreparedStubs, bulk *BulkImport) error {
+	defer c.cache.invalidate()
+
+	if bulk == nil {
+		bulk = NewBulkImport()
+	}
+	batchSize, parallelism := bulk.batchSize, bulk.parallelism
+	if batchSize < 1 {
+		batchSize = defaultBulkBatchSize
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		imported int
+	)
+	slots := make(chan struct{}, parallelism)
+	total := prepared.Len()
+
+	for start := 0; start < total; start += batchSize {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		end := start + batchSize
+		if end > total {
+			end = total
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			err := c.importBatch(ctx, prepared.mappings[start:end])
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("import prepared: stubs %d-%d of %d: %s", start+1, end, total, err.Error())
+					cancel()
+				}
+				return
+			}
+
+			for _, id := range prepared.ids[start:end] {
+				c.tracker.track(id)
+			}
+			imported += end - start
+			if bulk.progress != nil {
+				bulk.progress(imported, total)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil && imported < total {
+		return fmt.Errorf("import prepared: %d of %d stubs imported: %w", imported, total, err)
+	}
+
+	return nil
+}
+
+// importBatch imports the encoded mappings in one request.
+func (c *Client) importBatch(ctx context.Context, mappings [][]byte) error {
+	body := bulkBuffers.Get().(*bytes.Buffer)
+	body.Reset()
+
+	body.WriteString(`{"mappings":[`)
+	for i, mapping := range mappings {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(mapping)
+	}
+	body.WriteString(bulkImportOptions)
+
+	req, err := c.newBulkRequest(http.MethodPost, fmt.Sprintf("%s/%s/import", c.url, wiremockAdminMappingsURN), body.Bytes())
+	if err != nil {
+		return fmt.Errorf("build request error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		// the transport may still read the failed request body, so the buffer is not reused
+		return fmt.Errorf("request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	bulkBuffers.Put(body)
+	return nil
+}
@@ -0,0 +1,59 @@
+package wiremock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestClient_ImportPrepared(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL, WithGzipRequests(1024))
+
+	stubs := make([]*StubRule, 250)
+	for i := range stubs {
+		stubs[i] = Get(URLPathEqualTo(fmt.Sprintf("/orders/%d", i))).WillReturn("{}", nil, 200)
+	}
+	prepared, err := client.PrepareStubs(stubs)
+	if err != nil {
+		t.Fatalf("PrepareStubs error: %v", err)
+	}
+
+	var progress []int
+	bulk := NewBulkImport().WithBatchSize(100).WithParallelism(2).WithProgress(func(imported, total int) {
+		if total != len(stubs) {
+			t.Errorf("expected total %d; got %d", len(stubs), total)
+		}
+		progress = append(progress, imported)
+	})
+	if err := client.ImportPrepared(context.Background(), prepared, bulk); err != nil {
+		t.Fatalf("ImportPrepared error: %v", err)
+	}
+	if server.mappingCount() != len(stubs) || len(progress) != 3 || progress[2] != len(stubs) {
+		t.Errorf("expected all stubs imported in 3 batches; got %d mappings, progress %v", server.mappingCount(), progress)
+	}
+
+	registered, err := client.GetStub(stubs[249].UUID())
+	if err != nil || !registered.Equal(stubs[249]) {
+		t.Errorf("expected imported stub equal to %s; got %v, %v", stubs[249], registered, err)
+	}
+
+	// prepared stubs are imported again as they were prepared
+	stubs[0].WillReturn("changed", nil, 500)
+	if err := client.ImportPrepared(context.Background(), prepared, nil); err != nil {
+		t.Fatalf("ImportPrepared error: %v", err)
+	}
+	if registered, _ := client.GetStub(stubs[0].UUID()); registered == nil || registered.Response().Status() != 200 {
+		t.Errorf("expected stub as prepared; got %v", registered)
+	}
+
+	if _, err := client.PrepareStubs([]*StubRule{Get(URLPathMatching("(a"))}); err == nil {
+		t.Error("expected invalid stub error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.ImportPrepared(ctx, prepared, nil); err == nil {
+		t.Error("expected error of done context")
+	}
+}
@@ -0,0 +1,125 @@
+package wiremock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stubCache keeps the registered stubs listed by the client until they are changed through the client or ttl passes.
+type stubCache struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	stubs      []*StubRule
+	fetchedAt  time.Time
+	generation int
+}
+
+// WithStubCache makes the client cache the registered stubs for ttl, serving ListStubs and GetStub from memory.
+// Changes made through the client invalidate the cache, changes made by other clients are seen after ttl.
+func WithStubCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = &stubCache{ttl: ttl}
+	}
+}
+
+func (c *stubCache) get() ([]*StubRule, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stubs == nil || time.Since(c.fetchedAt) >= c.ttl {
+		return nil, c.generation, false
+	}
+
+	return c.stubs, c.generation, true
+}
+
+// set caches stubs fetched at generation, unless the cache was invalidated meanwhile.
+func (c *stubCache) set(stubs []*StubRule, generation int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.stubs = stubs
+	c.fetchedAt = time.Now()
+}
+
+func (c *stubCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stubs = nil
+	c.generation++
+}
+
+func (c *Client) allCachedStubs() ([]*StubRule, error) {
+	stubs, generation, ok := c.cache.get()
+	if ok {
+		return stubs, nil
+	}
+
+	stubs, _, err := c.listStubs(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if stubs == nil {
+		stubs = []*StubRule{}
+	}
+	c.cache.set(stubs, generation)
+
+	return stubs, nil
+}
+
+func (c *Client) cachedStubs(limit, offset int) ([]*StubRule, int, error) {
+	stubs, err := c.allCachedStubs()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(stubs)
+	if offset > total {
+		offset = total
+	}
+	if offset > 0 {
+		stubs = stubs[offset:]
+	}
+	if limit > 0 && limit < len(stubs) {
+		stubs = stubs[:limit]
+	}
+
+	copies := make([]*StubRule, len(stubs))
+	for i, stubRule := range stubs {
+		copies[i] = stubRule.copy()
+	}
+
+	return copies, total, nil
+}
+
+func (c *Client) cachedStub(id string) (*StubRule, error) {
+	stubs, err := c.allCachedStubs()
+	if err != nil {
+		return nil, fmt.Errorf("get stub: %s", err.Error())
+	}
+
+	for _, stubRule := range stubs {
+		if stubRule.UUID() == id {
+			return stubRule.copy(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("get stub %s: %w", id, ErrStubNotFound)
+}
+
+// copy gives a deep copy of the stub keeping its id, so callers cannot change the cached one.
+func (s *StubRule) copy() *StubRule {
+	stubRule := s.Clone()
+	stubRule.uuid = s.uuid
+	return stubRule
+}
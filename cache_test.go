@@ -0,0 +1,63 @@
+package wiremock
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithStubCache(t *testing.T) {
+	fake := newFakeServer(t)
+	var lists int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/"+wiremockAdminMappingsURN {
+			atomic.AddInt64(&lists, 1)
+		}
+		fake.serveHTTP(w, r)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, WithStubCache(time.Minute))
+
+	first := Get(URLPathEqualTo("/a"))
+	if err := client.StubFor(first); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		stubs, total, err := client.ListStubs(0, 0)
+		if err != nil || total != 1 || len(stubs) != 1 || stubs[0].UUID() != first.UUID() {
+			t.Fatalf("unexpected stubs %v, %d, %v", stubs, total, err)
+		}
+		stubs[0].AtPriority(1)
+	}
+	if _, err := client.GetStub(first.UUID()); err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if lists := atomic.LoadInt64(&lists); lists != 1 {
+		t.Errorf("expected stubs to be listed once; got %d", lists)
+	}
+
+	cached, err := client.GetStub(first.UUID())
+	if err != nil || cached.Priority() != DefaultPriority {
+		t.Errorf("expected cached stub unaffected by changes of listed copies; got %v, %v", cached, err)
+	}
+
+	second := Get(URLPathEqualTo("/b"))
+	if err := client.StubFor(second); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+	stubs, total, err := client.ListStubs(1, 1)
+	if err != nil || total != 2 || len(stubs) != 1 || stubs[0].UUID() != second.UUID() || lists != 2 {
+		t.Errorf("expected cache invalidated by StubFor; got %v, %d, %v after %d lists", stubs, total, err, lists)
+	}
+
+	if err := client.DeleteStub(first); err != nil {
+		t.Fatalf("DeleteStub error: %v", err)
+	}
+	if _, err := client.GetStub(first.UUID()); !errors.Is(err, ErrStubNotFound) {
+		t.Errorf("expected ErrStubNotFound after delete; got %v", err)
+	}
+}
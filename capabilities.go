@@ -0,0 +1,76 @@
+package wiremock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnsupportedByServer is returned when the stub uses the feature the connected WireMock version does not support.
+var ErrUnsupportedByServer = errors.New("unsupported by server")
+
+// capabilities is the server info probed once for the stubs using version specific features.
+type capabilities struct {
+	once sync.Once
+	// info is nil when the probe failed, then features are not checked
+	info *ServerInfo
+}
+
+// serverFeature is the stub feature available since the WireMock version.
+type serverFeature struct {
+	name    string
+	version string
+}
+
+// requiredFeature gives the stub feature requiring the most recent WireMock version, nil when any version serves it.
+func (s *StubRule) requiredFeature() *serverFeature {
+	matchers := append([]ParamMatcher(nil), s.request.bodyPatterns...)
+	for _, params := range []map[string]ParamMatcherInterface{s.request.headers, s.request.queryParams, s.request.cookies} {
+		for _, matcher := range params {
+			if paramMatcher, ok := matcher.(ParamMatcher); ok {
+				matchers = append(matchers, paramMatcher)
+			}
+		}
+	}
+
+	for len(matchers) > 0 {
+		matcher := matchers[0]
+		matchers = append(matchers[1:], matcher.valueMatchers...)
+		if matcher.valueMatcher != nil {
+			matchers = append(matchers, *matcher.valueMatcher)
+		}
+
+		if matcher.strategy == ParamAnd || matcher.strategy == ParamOr {
+			return &serverFeature{name: fmt.Sprintf("%s matcher", matcher.strategy), version: "3.0"}
+		}
+	}
+
+	return nil
+}
+
+// checkSupported probes the server version once and fails with ErrUnsupportedByServer
+// when the stub uses the feature the server does not support.
+// The stubs of features every version supports do not probe the server.
+func (c *Client) checkSupported(stubRule *StubRule) error {
+	feature := stubRule.requiredFeature()
+	if feature == nil {
+		return nil
+	}
+
+	c.capabilities.once.Do(func() {
+		if info, err := c.ServerInfo(); err == nil {
+			c.capabilities.info = info
+		}
+	})
+	info := c.capabilities.info
+	if info == nil || info.AtLeast(feature.version) {
+		return nil
+	}
+
+	version := info.Version
+	if version == "" {
+		version = "2"
+	}
+
+	return fmt.Errorf("%s requires WireMock %s, server is %s: %w", feature.name, feature.version, version, ErrUnsupportedByServer)
+}
@@ -0,0 +1,68 @@
+package wiremock
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const wiremockAdminCACertURN = "__admin/certs/wiremock-ca.crt"
+
+// CACertificate gives the PEM encoded certificate of the CA WireMock signs browser proxied HTTPS hosts with.
+// Browsers and clients pointed at WireMock as an HTTP proxy must trust it to intercept HTTPS traffic.
+//
+// Browser proxying itself is enabled by the --enable-browser-proxying startup flag, and untrusted upstream
+// certificates are accepted with --trust-all-proxy-targets, neither of them can be changed at runtime.
+func (c *Client) CACertificate() ([]byte, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s", c.url, wiremockAdminCACertURN))
+	if err != nil {
+		return nil, fmt.Errorf("get CA certificate: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get CA certificate: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get CA certificate: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return bodyBytes, nil
+}
+
+// BrowserProxyClient returns *http.Client using WireMock as the HTTP proxy and trusting its CA certificate,
+// the way a browser of the test rig is configured. Requests to real hosts are served by stubs,
+// or passed through to the hosts when no stub matches.
+func (c *Client) BrowserProxyClient() (*http.Client, error) {
+	proxyURL, err := url.Parse(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("browser proxy client: parse url error: %s", err.Error())
+	}
+
+	certificate, err := c.CACertificate()
+	if err != nil {
+		return nil, fmt.Errorf("browser proxy client: %s", err.Error())
+	}
+
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil, fmt.Errorf("browser proxy client: CA certificate is not PEM encoded")
+	}
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("browser proxy client: parse CA certificate error: %s", err.Error())
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	transport.TLSClientConfig = &tls.Config{RootCAs: x509.NewCertPool()}
+	transport.TLSClientConfig.RootCAs.AddCert(ca)
+
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,41 @@
+package wiremock
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_BrowserProxyClient(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	server := newFakeServer(t)
+	server.caCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: upstream.Certificate().Raw})
+	client := NewClient(server.URL)
+
+	proxyClient, err := client.BrowserProxyClient()
+	if err != nil {
+		t.Fatalf("BrowserProxyClient error: %v", err)
+	}
+
+	transport := proxyClient.Transport.(*http.Transport)
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if proxyURL, err := transport.Proxy(req); err != nil || proxyURL.String() != server.URL {
+		t.Errorf("expected proxy %s; got %v, %v", server.URL, proxyURL, err)
+	}
+
+	// the fake server is no proxy, so check the CA is trusted talking to the host directly
+	transport.Proxy = nil
+	res, err := proxyClient.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf("expected CA certificate trusted: %v", err)
+	}
+	res.Body.Close()
+
+	server.caCert = nil
+	if _, err := client.BrowserProxyClient(); err == nil {
+		t.Error("expected error without CA certificate")
+	}
+}
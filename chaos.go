@@ -0,0 +1,128 @@
+package wiremock
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+)
+
+// A Chaos builds stubs failing the given percentage of calls to the stub.
+//
+// WireMock has no random choice of responses, so failures are spread over a cycle of scenario states:
+// 30% gives the cycle of 10 calls where 3 calls, shuffled by the seed, fail.
+type Chaos struct {
+	stubRule       *StubRule
+	failurePercent int
+	failure        func(*StubRule) *StubRule
+	seed           int64
+	scenarioName   string
+}
+
+// NewChaos returns *Chaos failing failurePercent of calls to stubRule with 503 Service Unavailable.
+func NewChaos(stubRule *StubRule, failurePercent int) *Chaos {
+	return &Chaos{
+		stubRule:       stubRule,
+		failurePercent: failurePercent,
+		failure: func(s *StubRule) *StubRule {
+			s.response = Response{status: http.StatusOK}
+			return s.WillReturn("", nil, http.StatusServiceUnavailable)
+		},
+		seed:         1,
+		scenarioName: "chaos-" + stubRule.UUID(),
+	}
+}
+
+// WithFailureStatus sets failed calls response and returns *Chaos
+func (c *Chaos) WithFailureStatus(body string, headers map[string]string, status int64) *Chaos {
+	c.failure = func(s *StubRule) *StubRule {
+		s.response = Response{status: http.StatusOK}
+		return s.WillReturn(body, headers, status)
+	}
+	return c
+}
+
+// WithFailureFault sets network fault of failed calls and returns *Chaos
+func (c *Chaos) WithFailureFault(fault Fault) *Chaos {
+	c.failure = func(s *StubRule) *StubRule {
+		s.response = Response{status: http.StatusOK}
+		return s.WillReturnFault(fault)
+	}
+	return c
+}
+
+// WithSeed sets seed of failed calls positions in the cycle and returns *Chaos
+func (c *Chaos) WithSeed(seed int64) *Chaos {
+	c.seed = seed
+	return c
+}
+
+// WithScenarioName sets scenario of the cycle and returns *Chaos
+func (c *Chaos) WithScenarioName(scenarioName string) *Chaos {
+	c.scenarioName = scenarioName
+	return c
+}
+
+// Stubs returns one stub per call of the cycle.
+func (c *Chaos) Stubs() ([]*StubRule, error) {
+	if c.failurePercent < 0 || c.failurePercent > 100 {
+		return nil, fmt.Errorf("chaos: failure percent %d is out of [0, 100]", c.failurePercent)
+	}
+
+	divisor := gcd(c.failurePercent, 100)
+	cycle := 100 / divisor
+	failures := c.failurePercent / divisor
+
+	failed := make([]bool, cycle)
+	for i := 0; i < failures; i++ {
+		failed[i] = true
+	}
+	rand.New(rand.NewSource(c.seed)).Shuffle(cycle, func(i, j int) {
+		failed[i], failed[j] = failed[j], failed[i]
+	})
+
+	stubs := make([]*StubRule, cycle)
+	for i := range stubs {
+		stubRule := c.stubRule.Clone().
+			InScenario(c.scenarioName).
+			WhenScenarioStateIs(chaosState(i)).
+			WillSetStateTo(chaosState((i + 1) % cycle))
+		if failed[i] {
+			stubRule = c.failure(stubRule)
+		}
+		stubs[i] = stubRule
+	}
+
+	return stubs, nil
+}
+
+// StubChaos registers stubs of chaos.
+func (c *Client) StubChaos(chaos *Chaos) error {
+	stubs, err := chaos.Stubs()
+	if err != nil {
+		return err
+	}
+
+	for _, stubRule := range stubs {
+		if err := c.StubFor(stubRule); err != nil {
+			return fmt.Errorf("stub chaos: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func chaosState(i int) string {
+	if i == 0 {
+		return ScenarioStateStarted
+	}
+
+	return fmt.Sprintf("call %d", i+1)
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
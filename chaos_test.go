@@ -0,0 +1,65 @@
+package wiremock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestChaos_Stubs(t *testing.T) {
+	base := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, http.StatusOK)
+
+	stubs, err := NewChaos(base, 30).WithScenarioName("orders chaos").Stubs()
+	if err != nil {
+		t.Fatalf("Stubs error: %v", err)
+	}
+	if len(stubs) != 10 {
+		t.Fatalf("expected cycle of 10 calls; got %d", len(stubs))
+	}
+
+	failures := 0
+	for i, stubRule := range stubs {
+		if *stubRule.scenarioName != "orders chaos" || *stubRule.requiredScenarioState != chaosState(i) ||
+			*stubRule.newScenarioState != chaosState((i+1)%10) {
+			t.Errorf("unexpected scenario of call %d: %s", i+1, stubRule)
+		}
+		if stubRule.Response().Status() == http.StatusServiceUnavailable {
+			failures++
+		}
+	}
+	if failures != 3 {
+		t.Errorf("expected 3 failed calls; got %d", failures)
+	}
+
+	stubs, err = NewChaos(base, 50).WithFailureFault(FaultConnectionResetByPeer).Stubs()
+	if err != nil {
+		t.Fatalf("Stubs error: %v", err)
+	}
+	if len(stubs) != 2 || stubs[0].Response().Fault() == stubs[1].Response().Fault() {
+		t.Errorf("expected one of two calls to reset connection; got %v", stubs)
+	}
+
+	proxied := Get(URLPathEqualTo("/orders")).WillProxyTo("https://orders.example.com")
+	stubs, err = NewChaos(proxied, 50).WithFailureStatus("down", nil, http.StatusBadGateway).Stubs()
+	if err != nil {
+		t.Fatalf("Stubs error: %v", err)
+	}
+	for i, stubRule := range stubs {
+		if failed := stubRule.Response().Status() == http.StatusBadGateway; failed == (stubRule.Response().proxy != nil) {
+			t.Errorf("expected only passed call %d to be proxied; got %s", i+1, stubRule)
+		}
+	}
+
+	stubs, err = NewChaos(proxied, 50).Stubs()
+	if err != nil {
+		t.Fatalf("Stubs error: %v", err)
+	}
+	for i, stubRule := range stubs {
+		if stubRule.Response().Status() == http.StatusServiceUnavailable && stubRule.Response().proxy != nil {
+			t.Errorf("expected failed call %d not to be proxied; got %s", i+1, stubRule)
+		}
+	}
+
+	if _, err := NewChaos(base, 101).Stubs(); err == nil {
+		t.Errorf("expected error for failure percent out of range")
+	}
+}
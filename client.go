@@ -2,10 +2,14 @@ package wiremock
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
 )
 
 const (
@@ -13,28 +17,81 @@ const (
 	wiremockAdminMappingsURN = "__admin/mappings"
 )
 
+// ErrStubNotFound is returned when the stub mapping with requested id is not registered.
+var ErrStubNotFound = errors.New("stub not found")
+
+// ErrRequestNotFound is returned when the request with requested id is not in the request journal.
+var ErrRequestNotFound = errors.New("request not found")
+
 // A Client implements requests to the wiremock server.
 type Client struct {
-	url string
+	url        string
+	codec      JSONCodec
+	httpClient *http.Client
+	// gzipMinSize is the size of bulk request bodies compressed by gzip, zero disables compression
+	gzipMinSize int
+	cache       *stubCache
+	// responseTemplating enables response templating of every stub the client registers
+	responseTemplating bool
+	// authorization is the Authorization header of admin requests
+	authorization string
+	capabilities  *capabilities
+	// tracker keeps ids of the stubs registered by the child client of the test, nil for other clients
+	tracker *stubTracker
 }
 
 // NewClient returns *Client.
-func NewClient(url string) *Client {
-	return &Client{url: url}
+// Admin requests go over HTTP/2 when the url is https and the server supports it.
+func NewClient(url string, options ...ClientOption) *Client {
+	c := &Client{url: url, codec: standardJSON{}, httpClient: http.DefaultClient, capabilities: &capabilities{}}
+	for _, option := range options {
+		option(c)
+	}
+	if c.authorization != "" {
+		c.authorize()
+	}
+
+	return c
 }
 
 // StubFor creates a new stub mapping.
 func (c *Client) StubFor(stubRule *StubRule) error {
-	requestBody, err := stubRule.MarshalJSON()
+	return c.stubFor(context.Background(), stubRule)
+}
+
+func (c *Client) stubFor(ctx context.Context, stubRule *StubRule) error {
+	if err := stubRule.Validate(); err != nil {
+		return fmt.Errorf("invalid stub: %s", err.Error())
+	}
+	if err := c.checkSupported(stubRule); err != nil {
+		return err
+	}
+	if err := c.uploadBodyFile(ctx, stubRule); err != nil {
+		return err
+	}
+
+	return c.postStub(ctx, stubRule)
+}
+
+func (c *Client) postStub(ctx context.Context, stubRule *StubRule) error {
+	defer c.cache.invalidate()
+
+	requestBody, err := c.codec.Marshal(c.stubJSON(stubRule))
+	if err != nil {
+		return fmt.Errorf("build stub request error: %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", c.url, wiremockAdminMappingsURN), bytes.NewBuffer(requestBody))
 	if err != nil {
 		return fmt.Errorf("build stub request error: %s", err.Error())
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	res, err := http.Post(fmt.Sprintf("%s/%s", c.url, wiremockAdminMappingsURN), "application/json", bytes.NewBuffer(requestBody))
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("stub request error: %s", err.Error())
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	if res.StatusCode != http.StatusCreated {
 		bodyBytes, err := ioutil.ReadAll(res.Body)
@@ -42,24 +99,206 @@ func (c *Client) StubFor(stubRule *StubRule) error {
 			return fmt.Errorf("read response error: %s", err.Error())
 		}
 
-		return fmt.Errorf("bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+		return fmt.Errorf("bad response status: %d, response: %s, stub: %s", res.StatusCode, string(bodyBytes), stubRule)
 	}
 
+	c.tracker.track(stubRule.UUID())
 	return nil
 }
 
+// UpdateStub replaces the stub mapping registered with id by stubRule.
+// The stubRule takes over id, so it can be used to delete or update the mapping later.
+func (c *Client) UpdateStub(id string, stubRule *StubRule) error {
+	stubRule.uuid = id
+
+	found, err := c.putStub(id, stubRule)
+	if err != nil {
+		return fmt.Errorf("update stub: %s", err.Error())
+	}
+	if !found {
+		return fmt.Errorf("update stub %s: %w", id, ErrStubNotFound)
+	}
+
+	return nil
+}
+
+// GetStub gives the stub mapping registered with id.
+func (c *Client) GetStub(id string) (*StubRule, error) {
+	if c.cache != nil {
+		return c.cachedStub(id)
+	}
+
+	return c.getStub(id)
+}
+
+func (c *Client) getStub(id string) (*StubRule, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/%s", c.url, wiremockAdminMappingsURN, id))
+	if err != nil {
+		return nil, fmt.Errorf("get stub: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get stub: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("get stub %s: %w", id, ErrStubNotFound)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get stub: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var stubRule StubRule
+	if err := c.codec.Unmarshal(bodyBytes, &stubRule); err != nil {
+		return nil, fmt.Errorf("get stub: read json error: %s", err.Error())
+	}
+
+	return &stubRule, nil
+}
+
+// ListStubs gives registered stub mappings and their total count.
+// A limit of zero or less means all mappings starting from offset.
+func (c *Client) ListStubs(limit, offset int) ([]*StubRule, int, error) {
+	if c.cache != nil {
+		return c.cachedStubs(limit, offset)
+	}
+
+	return c.listStubs(limit, offset)
+}
+
+func (c *Client) listStubs(limit, offset int) ([]*StubRule, int, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+
+	listURL := fmt.Sprintf("%s/%s", c.url, wiremockAdminMappingsURN)
+	if len(query) > 0 {
+		listURL += "?" + query.Encode()
+	}
+
+	res, err := c.httpClient.Get(listURL)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list stubs: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list stubs: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("list stubs: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var listStubsResponse struct {
+		Mappings []*StubRule `json:"mappings"`
+		Meta     struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &listStubsResponse); err != nil {
+		return nil, 0, fmt.Errorf("list stubs: read json error: %s", err.Error())
+	}
+
+	return listStubsResponse.Mappings, listStubsResponse.Meta.Total, nil
+}
+
+// UpsertStub updates the stub mapping with the same id or creates it when the id is not registered yet.
+// Combined with WithID or WithDeterministicID it makes setup code safe to re-run.
+func (c *Client) UpsertStub(stubRule *StubRule) error {
+	found, err := c.putStub(stubRule.UUID(), stubRule)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return c.postStub(context.Background(), stubRule)
+	}
+
+	return nil
+}
+
+// StubForIdempotent registers the stub under the id derived from its content, see WithDeterministicID,
+// creating or updating it, so fixture setup can be re-run against a persistent shared server.
+// The copy of the stub with the derived id is registered and stubRule is not changed,
+// so the same stub can be registered from several goroutines.
+func (c *Client) StubForIdempotent(stubRule *StubRule) error {
+	id, err := stubRule.DeterministicID()
+	if err != nil {
+		return err
+	}
+
+	return c.UpsertStub(stubRule.Clone().WithID(id))
+}
+
+// putStub replaces the stub mapping with id. It reports false when the id is not registered.
+func (c *Client) putStub(id string, stubRule *StubRule) (bool, error) {
+	defer c.cache.invalidate()
+
+	if err := stubRule.Validate(); err != nil {
+		return false, fmt.Errorf("invalid stub: %s", err.Error())
+	}
+	if err := c.checkSupported(stubRule); err != nil {
+		return false, err
+	}
+	if err := c.uploadBodyFile(context.Background(), stubRule); err != nil {
+		return false, err
+	}
+
+	requestBody, err := c.codec.Marshal(c.stubJSON(stubRule))
+	if err != nil {
+		return false, fmt.Errorf("build stub request error: %s", err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s/%s", c.url, wiremockAdminMappingsURN, id), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return false, fmt.Errorf("build stub request error: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("stub request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return false, fmt.Errorf("read response error: %s", err.Error())
+		}
+
+		return false, fmt.Errorf("bad response status: %d, response: %s, stub: %s", res.StatusCode, string(bodyBytes), stubRule)
+	}
+
+	return true, nil
+}
+
 // Clear deletes all stub mappings.
 func (c *Client) Clear() error {
+	defer c.cache.invalidate()
+
 	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s", c.url, wiremockAdminMappingsURN), nil)
 	if err != nil {
 		return fmt.Errorf("build cleare Request error: %s", err.Error())
 	}
 
-	res, err := (&http.Client{}).Do(req)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("clear Request error: %s", err.Error())
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	if res.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad response status: %d", res.StatusCode)
@@ -70,11 +309,13 @@ func (c *Client) Clear() error {
 
 // Reset restores stub mappings to the defaults defined back in the backing store.
 func (c *Client) Reset() error {
-	res, err := http.Post(fmt.Sprintf("%s/%s/reset", c.url, wiremockAdminMappingsURN), "application/json", nil)
+	defer c.cache.invalidate()
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/reset", c.url, wiremockAdminMappingsURN), "application/json", nil)
 	if err != nil {
 		return fmt.Errorf("reset Request error: %s", err.Error())
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	if res.StatusCode != http.StatusOK {
 		bodyBytes, err := ioutil.ReadAll(res.Body)
@@ -90,11 +331,11 @@ func (c *Client) Reset() error {
 
 // ResetAllScenarios resets back to start of the state of all configured scenarios.
 func (c *Client) ResetAllScenarios() error {
-	res, err := http.Post(fmt.Sprintf("%s/%s/scenarios/reset", c.url, wiremockAdminURN), "application/json", nil)
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/scenarios/reset", c.url, wiremockAdminURN), "application/json", nil)
 	if err != nil {
 		return fmt.Errorf("reset all scenarios Request error: %s", err.Error())
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	if res.StatusCode != http.StatusOK {
 		bodyBytes, err := ioutil.ReadAll(res.Body)
@@ -108,18 +349,395 @@ func (c *Client) ResetAllScenarios() error {
 	return nil
 }
 
+// GetSettings gives current global settings of the server.
+func (c *Client) GetSettings() (*GlobalSettings, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s", c.url, wiremockAdminSettingsURN))
+	if err != nil {
+		return nil, fmt.Errorf("get settings: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get settings: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var settingsResponse struct {
+		Settings GlobalSettings `json:"settings"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &settingsResponse); err != nil {
+		return nil, fmt.Errorf("get settings: read json error: %s", err.Error())
+	}
+
+	return &settingsResponse.Settings, nil
+}
+
+// UpdateSettings replaces global settings of the server.
+func (c *Client) UpdateSettings(settings *GlobalSettings) error {
+	requestBody, err := c.codec.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("update settings: build error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s", c.url, wiremockAdminSettingsURN), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("update settings: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("update settings: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("update settings: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// ResetRequests deletes all requests from the request journal.
+func (c *Client) ResetRequests() error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s/requests", c.url, wiremockAdminURN), nil)
+	if err != nil {
+		return fmt.Errorf("reset requests: build request error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reset requests: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("reset requests: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("reset requests: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// GetServeEvents gives request journal entries filtered by query, most recent first.
+// A nil query gives all entries.
+func (c *Client) GetServeEvents(query *ServeEventQuery) ([]ServeEvent, error) {
+	requestsURL := fmt.Sprintf("%s/%s/requests", c.url, wiremockAdminURN)
+	if values := query.values(); len(values) > 0 {
+		requestsURL += "?" + values.Encode()
+	}
+
+	res, err := c.httpClient.Get(requestsURL)
+	if err != nil {
+		return nil, fmt.Errorf("get serve events: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get serve events: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get serve events: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var serveEventsResponse struct {
+		Requests               []ServeEvent `json:"requests"`
+		RequestJournalDisabled bool         `json:"requestJournalDisabled"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &serveEventsResponse); err != nil {
+		return nil, fmt.Errorf("get serve events: read json error: %s", err.Error())
+	}
+
+	if serveEventsResponse.RequestJournalDisabled {
+		return nil, fmt.Errorf("get serve events: %w", ErrRequestJournalDisabled)
+	}
+
+	return query.filter(serveEventsResponse.Requests), nil
+}
+
+// GetRequest gives the request journal entry with id: the logged request, the matched stub and the response.
+func (c *Client) GetRequest(id string) (*ServeEvent, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/requests/%s", c.url, wiremockAdminURN, id))
+	if err != nil {
+		return nil, fmt.Errorf("get request: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get request: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("get request %s: %w", id, ErrRequestNotFound)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get request: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var serveEvent ServeEvent
+	if err := c.codec.Unmarshal(bodyBytes, &serveEvent); err != nil {
+		return nil, fmt.Errorf("get request: read json error: %s", err.Error())
+	}
+
+	return &serveEvent, nil
+}
+
+// DeleteServeEvent deletes the request journal entry with id.
+func (c *Client) DeleteServeEvent(id string) error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s/requests/%s", c.url, wiremockAdminURN, id), nil)
+	if err != nil {
+		return fmt.Errorf("delete serve event: build request error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete serve event: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("delete serve event %s: %w", id, ErrRequestNotFound)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("delete serve event: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("delete serve event: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// DeleteServeEvents deletes request journal entries filtered by query and returns their ids,
+// so tests sharing the server can clean up their own entries, e.g.
+//
+//	ids, err := client.DeleteServeEvents(wiremock.NewServeEventQuery().WithStubID(stub.UUID()))
+//
+// A nil query deletes all entries one by one, use ResetRequests instead.
+func (c *Client) DeleteServeEvents(query *ServeEventQuery) ([]string, error) {
+	events, err := c.GetServeEvents(query)
+	if err != nil {
+		return nil, fmt.Errorf("delete serve events: %s", err.Error())
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		// entries may be removed concurrently, e.g. by the journal limit
+		if err := c.DeleteServeEvent(event.ID); err != nil && !errors.Is(err, ErrRequestNotFound) {
+			return ids, fmt.Errorf("delete serve events: %s", err.Error())
+		}
+		ids = append(ids, event.ID)
+	}
+
+	return ids, nil
+}
+
+// FindRequests gives logged requests matching criteria, in the order they were received.
+func (c *Client) FindRequests(criteria RequestCriteria) ([]LoggedRequest, error) {
+	return c.findRequests(context.Background(), criteria.Criteria())
+}
+
+// CapturedBodies decodes JSON bodies of logged requests matching criteria, in the order they were received,
+// into the slice bodies points to, e.g.
+//
+//	var payloads []OrderPayload
+//	err := client.CapturedBodies(wiremock.Post(wiremock.URLPathEqualTo("/orders")), &payloads)
+func (c *Client) CapturedBodies(criteria RequestCriteria, bodies interface{}) error {
+	slice := reflect.ValueOf(bodies)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("captured bodies: expected pointer to slice, got %T", bodies)
+	}
+
+	requests, err := c.FindRequests(criteria)
+	if err != nil {
+		return fmt.Errorf("captured bodies: %s", err.Error())
+	}
+
+	decoded := reflect.MakeSlice(slice.Elem().Type(), len(requests), len(requests))
+	for i := range requests {
+		if err := c.codec.Unmarshal(requests[i].Body, decoded.Index(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("captured bodies: decode body of %s %s: %s", requests[i].Method, requests[i].URL, err.Error())
+		}
+	}
+	slice.Elem().Set(decoded)
+
+	return nil
+}
+
+func (c *Client) findRequests(ctx context.Context, r *Request) ([]LoggedRequest, error) {
+	requestBody, err := c.codec.Marshal(r.jsonValue())
+	if err != nil {
+		return nil, fmt.Errorf("find requests: build error: %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/requests/find", c.url, wiremockAdminURN), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("find requests: build request error: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("find requests: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("find requests: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("find requests: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var findRequestsResponse struct {
+		Requests               []LoggedRequest `json:"requests"`
+		RequestJournalDisabled bool            `json:"requestJournalDisabled"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &findRequestsResponse); err != nil {
+		return nil, fmt.Errorf("find requests: read json error: %s", err.Error())
+	}
+
+	if findRequestsResponse.RequestJournalDisabled {
+		return nil, fmt.Errorf("find requests: %w", ErrRequestJournalDisabled)
+	}
+
+	return findRequestsResponse.Requests, nil
+}
+
+// FindUnmatchedRequests gives logged requests not matched by any stub.
+func (c *Client) FindUnmatchedRequests() ([]LoggedRequest, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/requests/unmatched", c.url, wiremockAdminURN))
+	if err != nil {
+		return nil, fmt.Errorf("find unmatched requests: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("find unmatched requests: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("find unmatched requests: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var unmatchedResponse struct {
+		Requests               []LoggedRequest `json:"requests"`
+		RequestJournalDisabled bool            `json:"requestJournalDisabled"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &unmatchedResponse); err != nil {
+		return nil, fmt.Errorf("find unmatched requests: read json error: %s", err.Error())
+	}
+
+	if unmatchedResponse.RequestJournalDisabled {
+		return nil, fmt.Errorf("find unmatched requests: %w", ErrRequestJournalDisabled)
+	}
+
+	return unmatchedResponse.Requests, nil
+}
+
+// FindNearMissesForUnmatched gives the closest stubs for every logged request not matched by any stub.
+func (c *Client) FindNearMissesForUnmatched() ([]NearMiss, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/requests/unmatched/near-misses", c.url, wiremockAdminURN))
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("find near misses: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var nearMissesResponse struct {
+		NearMisses             []NearMiss `json:"nearMisses"`
+		RequestJournalDisabled bool       `json:"requestJournalDisabled"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &nearMissesResponse); err != nil {
+		return nil, fmt.Errorf("find near misses: read json error: %s", err.Error())
+	}
+
+	if nearMissesResponse.RequestJournalDisabled {
+		return nil, fmt.Errorf("find near misses: %w", ErrRequestJournalDisabled)
+	}
+
+	return nearMissesResponse.NearMisses, nil
+}
+
+// FindNearMisses gives the logged requests closest to matching criteria.
+func (c *Client) FindNearMisses(criteria RequestCriteria) ([]NearMiss, error) {
+	requestBody, err := c.codec.Marshal(criteria.Criteria().jsonValue())
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: build error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/near-misses/request-pattern", c.url, wiremockAdminURN), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("find near misses: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var nearMissesResponse struct {
+		NearMisses []NearMiss `json:"nearMisses"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &nearMissesResponse); err != nil {
+		return nil, fmt.Errorf("find near misses: read json error: %s", err.Error())
+	}
+
+	return nearMissesResponse.NearMisses, nil
+}
+
 // GetCountRequests gives count requests by criteria.
 func (c *Client) GetCountRequests(r *Request) (int64, error) {
-	requestBody, err := r.MarshalJSON()
+	return c.getCountRequests(context.Background(), r)
+}
+
+func (c *Client) getCountRequests(ctx context.Context, r *Request) (int64, error) {
+	requestBody, err := c.codec.Marshal(r.jsonValue())
 	if err != nil {
 		return 0, fmt.Errorf("get count requests: build error: %s", err.Error())
 	}
 
-	res, err := http.Post(fmt.Sprintf("%s/%s/requests/count", c.url, wiremockAdminURN), "application/json", bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/requests/count", c.url, wiremockAdminURN), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return 0, fmt.Errorf("get count requests: build request error: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return 0, fmt.Errorf("get count requests: %s", err.Error())
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	bodyBytes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
@@ -131,14 +749,19 @@ func (c *Client) GetCountRequests(r *Request) (int64, error) {
 	}
 
 	var countRequestsResponse struct {
-		Count int64 `json:"count"`
+		Count                  int64 `json:"count"`
+		RequestJournalDisabled bool  `json:"requestJournalDisabled"`
 	}
 
-	err = json.Unmarshal(bodyBytes, &countRequestsResponse)
+	err = c.codec.Unmarshal(bodyBytes, &countRequestsResponse)
 	if err != nil {
 		return 0, fmt.Errorf("get count requests: read json error: %s", err.Error())
 	}
 
+	if countRequestsResponse.RequestJournalDisabled {
+		return 0, fmt.Errorf("get count requests: %w", ErrRequestJournalDisabled)
+	}
+
 	return countRequestsResponse.Count, nil
 }
 
@@ -154,17 +777,23 @@ func (c *Client) Verify(r *Request, expectedCount int64) (bool, error) {
 
 // DeleteStubByID deletes stub by id.
 func (c *Client) DeleteStubByID(id string) error {
+	defer c.cache.invalidate()
+
 	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s/%s", c.url, wiremockAdminMappingsURN, id), nil)
 	if err != nil {
 		return fmt.Errorf("delete stub by id: build request error: %s", err.Error())
 	}
 
-	res, err := (&http.Client{}).Do(req)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("delete stub by id: request error: %s", err.Error())
 	}
 
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("delete stub by id: %w", ErrStubNotFound)
+	}
 
 	if res.StatusCode != http.StatusOK {
 		bodyBytes, err := ioutil.ReadAll(res.Body)
@@ -175,9 +804,23 @@ func (c *Client) DeleteStubByID(id string) error {
 		return fmt.Errorf("bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
 	}
 
+	c.tracker.untrack(id)
 	return nil
 }
 
+// DeleteStubs deletes stub mappings by ids.
+// It tries every id and returns the joined errors of failed deletions.
+func (c *Client) DeleteStubs(ids ...string) error {
+	var errs []error
+	for _, id := range ids {
+		if err := c.DeleteStubByID(id); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // DeleteStub deletes stub mapping.
 func (c *Client) DeleteStub(s *StubRule) error {
 	return c.DeleteStubByID(s.UUID())
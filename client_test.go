@@ -2,9 +2,13 @@ package wiremock
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -72,3 +76,415 @@ func TestStubRule_ToJson(t *testing.T) {
 		t.Errorf("expected requestBody\n%v\n%v", parsedResult, expected)
 	}
 }
+
+func TestStubRule_UnmarshalJSON(t *testing.T) {
+	rawTemplate, err := os.ReadFile("expected-template.json")
+	if err != nil {
+		t.Fatalf("failed to read expected-template.json %v", err)
+	}
+	id := "a6e6be5c-0c4d-4a2f-9d7b-2f1a5b5e2a51"
+	rawStubRule := []byte(fmt.Sprintf(string(rawTemplate), id, id))
+
+	var stubRule StubRule
+	if err := json.Unmarshal(rawStubRule, &stubRule); err != nil {
+		t.Fatalf("StubRule json.Unmarshal error: %v", err)
+	}
+	if stubRule.UUID() != id {
+		t.Errorf("expected uuid %q; got %q", id, stubRule.UUID())
+	}
+
+	rawResult, err := json.Marshal(&stubRule)
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+	var expected, parsedResult map[string]interface{}
+	if err := json.Unmarshal(rawStubRule, &expected); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+	if err := json.Unmarshal(rawResult, &parsedResult); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsedResult, expected) {
+		t.Errorf("expected stub rule\n%v\n%v", parsedResult, expected)
+	}
+}
+
+func TestStubRule_MarshalJSONIsDeterministic(t *testing.T) {
+	stubRule := Post(URLPathEqualTo("/example")).
+		WithQueryParam("b", EqualTo("2")).
+		WithQueryParam("a", EqualToIgnoreCase("1")).
+		WithQueryParam("c", Absent()).
+		WithHeader("X-B", Contains("b")).
+		WithHeader("X-A", Matching("a")).
+		WithCookie("z", EqualTo("z")).
+		WithCookie("y", EqualTo("y")).
+		WithBodyPattern(EqualToJson(`{}`, IgnoreExtraElements, IgnoreArrayOrder)).
+		WithMultipartPattern(NewMultipartPattern().WithName("file").WithHeader("Content-Type", EqualTo("text/plain"))).
+		WithMetadata("team", "checkout").
+		WithMetadata("build", 42).
+		WillReturn("ok", map[string]string{"X-2": "2", "X-1": "1"}, 200)
+	stubRule.uuid = "a6e6be5c-0c4d-4a2f-9d7b-2f1a5b5e2a51"
+
+	expected := `{"uuid":"a6e6be5c-0c4d-4a2f-9d7b-2f1a5b5e2a51","id":"a6e6be5c-0c4d-4a2f-9d7b-2f1a5b5e2a51",` +
+		`"request":{"bodyPatterns":[{"equalToJson":"{}","ignoreArrayOrder":true,"ignoreExtraElements":true}],` +
+		`"cookies":{"y":{"equalTo":"y"},"z":{"equalTo":"z"}},` +
+		`"headers":{"X-A":{"matches":"a"},"X-B":{"contains":"b"}},` +
+		`"method":"POST",` +
+		`"multipartPatterns":[{"headers":{"Content-Disposition":{"contains":"name=\"file\""},"Content-Type":{"equalTo":"text/plain"}},"matchingType":"ANY"}],` +
+		`"queryParameters":{"a":{"caseInsensitive":true,"equalTo":"1"},"b":{"equalTo":"2"},"c":{"absent":true}},` +
+		`"urlPath":"/example"},` +
+		`"response":{"body":"ok","headers":{"X-1":"1","X-2":"2"},"status":200},` +
+		`"metadata":{"build":42,"team":"checkout"}}`
+
+	for i := 0; i < 20; i++ {
+		result, err := json.Marshal(stubRule)
+		if err != nil {
+			t.Fatalf("StubRule json.Marshal error: %v", err)
+		}
+		if string(result) != expected {
+			t.Fatalf("expected requestBody\n%s\ngot\n%s", expected, string(result))
+		}
+	}
+}
+
+func TestStubRule_Clone(t *testing.T) {
+	base := Post(URLPathEqualTo("/orders")).
+		WithHeader("X-Id", EqualTo("1")).
+		WithMultipartPattern(NewMultipartPattern().WithName("file")).
+		WillReturn("base", map[string]string{"Content-Type": "text/plain"}, 200).
+		AtPriority(1).
+		WithMetadata("team", "checkout")
+	before, err := json.Marshal(base)
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+
+	clone := base.Clone().
+		WithHeader("X-Id", EqualTo("2")).
+		WithMetadata("team", "payments").
+		AtPriority(2)
+	clone.Request().multipartPatterns[0].WithName("other")
+	clone.Response().headers["Content-Type"] = "application/json"
+
+	if clone.UUID() == base.UUID() {
+		t.Errorf("expected clone to get a new uuid")
+	}
+
+	after, err := json.Marshal(base)
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("expected base stub to be unchanged\n%s\n%s", before, after)
+	}
+}
+
+func TestClient_UpsertStub(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stub := func() *StubRule {
+		return Get(URLPathEqualTo("/orders")).
+			WillReturn("[]", nil, 200).
+			WithDeterministicID()
+	}
+	if stub().UUID() != stub().UUID() {
+		t.Fatalf("expected equal stubs to get equal ids")
+	}
+	if stub().UUID() == Get(URLPathEqualTo("/users")).WithDeterministicID().UUID() {
+		t.Fatalf("expected different stubs to get different ids")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.UpsertStub(stub()); err != nil {
+			t.Fatalf("UpsertStub error: %v", err)
+		}
+	}
+	if err := client.UpsertStub(Get(URLPathEqualTo("/users")).WithID("b8b879a4-3c6a-4d5c-a6c2-3c2a1a0e2f11")); err != nil {
+		t.Fatalf("UpsertStub error: %v", err)
+	}
+
+	if count := server.mappingCount(); count != 2 {
+		t.Errorf("expected 2 mappings; got %d", count)
+	}
+}
+
+func TestClient_StubForIdempotent(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubRule := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)
+	ownID := stubRule.UUID()
+	id := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200).WithDeterministicID().UUID()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.StubForIdempotent(stubRule); err != nil {
+				t.Errorf("StubForIdempotent error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stubRule.UUID() != ownID {
+		t.Errorf("expected the stub not changed; got id %s", stubRule.UUID())
+	}
+	if _, err := client.GetStub(id); err != nil {
+		t.Errorf("expected the stub registered with deterministic id %s; got %v", id, err)
+	}
+	if count := server.mappingCount(); count != 1 {
+		t.Errorf("expected 1 mapping; got %d", count)
+	}
+
+	unencodable := Get(URLPathEqualTo("/orders")).WillReturnJSON(make(chan int), nil, 200)
+	if err := client.StubForIdempotent(unencodable); err == nil || !strings.Contains(err.Error(), "stub id") {
+		t.Errorf("expected stub id error; got %v", err)
+	}
+	if err := client.StubFor(unencodable.WithDeterministicID()); err == nil || !strings.Contains(err.Error(), "invalid stub: id: stub id") {
+		t.Errorf("expected WithDeterministicID error reported by StubFor; got %v", err)
+	}
+}
+
+func TestClient_UpdateStub(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubRule := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	updated := Get(URLPathEqualTo("/orders")).WillReturn(`[{"id": 1}]`, nil, 200)
+	if err := client.UpdateStub(stubRule.UUID(), updated); err != nil {
+		t.Fatalf("UpdateStub error: %v", err)
+	}
+	if updated.UUID() != stubRule.UUID() {
+		t.Errorf("expected updated stub to take over id %s; got %s", stubRule.UUID(), updated.UUID())
+	}
+	if count := server.mappingCount(); count != 1 {
+		t.Errorf("expected 1 mapping; got %d", count)
+	}
+
+	if err := client.UpdateStub("9a1b0c33-6e4b-4d0e-8d3e-7e0c2b6a4f00", updated); err == nil {
+		t.Errorf("expected error for unknown stub id")
+	}
+}
+
+func TestClient_GetStub(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubRule := Post(URLPathEqualTo("/orders")).
+		WithHeader("X-Id", EqualTo("42")).
+		WillReturnJSON(map[string]interface{}{"id": "42"}, map[string]string{"Content-Type": "application/json"}, 201).
+		InScenario("orders").
+		WithMetadata("team", "checkout")
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	actual, err := client.GetStub(stubRule.UUID())
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if actual.String() != stubRule.String() {
+		t.Errorf("expected stub\n%s\ngot\n%s", stubRule, actual)
+	}
+
+	_, err = client.GetStub("9a1b0c33-6e4b-4d0e-8d3e-7e0c2b6a4f00")
+	if !errors.Is(err, ErrStubNotFound) {
+		t.Errorf("expected ErrStubNotFound; got %v", err)
+	}
+}
+
+func TestClient_ListStubs(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		if err := client.StubFor(Get(URLPathEqualTo(path))); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	stubs, total, err := client.ListStubs(2, 1)
+	if err != nil {
+		t.Fatalf("ListStubs error: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("expected total 3; got %d", total)
+	}
+	if len(stubs) != 2 || stubs[0].Request().urlMatcher.Value() != "/b" || stubs[1].Request().urlMatcher.Value() != "/c" {
+		t.Errorf("expected stubs /b and /c; got %v", stubs)
+	}
+
+	stubs, _, err = client.ListStubs(0, 0)
+	if err != nil {
+		t.Fatalf("ListStubs error: %v", err)
+	}
+	if len(stubs) != 3 {
+		t.Errorf("expected 3 stubs; got %d", len(stubs))
+	}
+}
+
+func TestClient_DeleteStubs(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubs := []*StubRule{Get(URLPathEqualTo("/a")), Get(URLPathEqualTo("/b")), Get(URLPathEqualTo("/c"))}
+	for _, stubRule := range stubs {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	unknownID := "9a1b0c33-6e4b-4d0e-8d3e-7e0c2b6a4f00"
+	err := client.DeleteStubs(stubs[0].UUID(), unknownID, stubs[2].UUID())
+	if err == nil || !strings.Contains(err.Error(), unknownID) || !errors.Is(err, ErrStubNotFound) {
+		t.Errorf("expected ErrStubNotFound for %s; got %v", unknownID, err)
+	}
+	if count := server.mappingCount(); count != 1 {
+		t.Errorf("expected 1 mapping left; got %d", count)
+	}
+}
+
+func TestClient_ConfigureExtension(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	if err := client.UpdateSettings(NewGlobalSettings().WithFixedDelay(time.Second)); err != nil {
+		t.Fatalf("UpdateSettings error: %v", err)
+	}
+	if err := client.ConfigureExtension("webhooks", map[string]interface{}{"retries": 3}); err != nil {
+		t.Fatalf("ConfigureExtension error: %v", err)
+	}
+
+	actual, err := client.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings error: %v", err)
+	}
+	if actual.FixedDelay() != time.Second {
+		t.Errorf("expected fixed delay kept; got %s", actual.FixedDelay())
+	}
+	if config, ok := actual.Extended()["webhooks"].(map[string]interface{}); !ok || config["retries"] != float64(3) {
+		t.Errorf("expected webhooks configuration; got %v", actual.Extended())
+	}
+}
+
+func TestClient_UpdateSettings(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	settings := NewGlobalSettings().
+		WithFixedDelay(250*time.Millisecond).
+		WithProxyPassThrough(false).
+		WithExtended("region", "eu")
+	if err := client.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings error: %v", err)
+	}
+
+	actual, err := client.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings error: %v", err)
+	}
+	if actual.FixedDelay() != 250*time.Millisecond {
+		t.Errorf("expected fixed delay 250ms; got %s", actual.FixedDelay())
+	}
+	if actual.ProxyPassThrough() == nil || *actual.ProxyPassThrough() {
+		t.Errorf("expected proxy pass through disabled; got %v", actual.ProxyPassThrough())
+	}
+	if actual.Extended()["region"] != "eu" {
+		t.Errorf("expected extended region eu; got %v", actual.Extended())
+	}
+
+	if err := client.ResetRequests(); err != nil {
+		t.Errorf("ResetRequests error: %v", err)
+	}
+}
+
+func TestStubRule_WillReturnRedirect(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/old")).WillReturnRedirect("/new", http.StatusMovedPermanently)
+
+	if stubRule.Response().Status() != http.StatusMovedPermanently || stubRule.Response().Headers()["Location"] != "/new" {
+		t.Errorf("unexpected redirect response %s", stubRule)
+	}
+
+	headers := map[string]string{"Cache-Control": "no-store"}
+	Get(URLPathEqualTo("/old")).WillReturn("", headers, http.StatusOK).WillReturnRedirect("/new", http.StatusFound)
+	if _, ok := headers["Location"]; ok {
+		t.Errorf("expected headers given to WillReturn not changed; got %v", headers)
+	}
+}
+
+func TestJournalOptions(t *testing.T) {
+	if args := NewJournalOptions().WithDisabled().WithMaxEntries(500).Args(); !reflect.DeepEqual(args, []string{"--no-request-journal", "--max-request-journal-entries=500"}) {
+		t.Errorf("unexpected journal args %v", args)
+	}
+	if args := NewJournalOptions().Args(); len(args) != 0 {
+		t.Errorf("expected no args of the default journal; got %v", args)
+	}
+
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+	if enabled, err := client.RequestJournalEnabled(); err != nil || !enabled {
+		t.Errorf("expected enabled journal; got %v, error %v", enabled, err)
+	}
+
+	server.mu.Lock()
+	server.journalDisabled = true
+	server.mu.Unlock()
+	if enabled, err := client.RequestJournalEnabled(); err != nil || enabled {
+		t.Errorf("expected disabled journal; got %v, error %v", enabled, err)
+	}
+	if _, err := client.GetServeEvents(nil); !errors.Is(err, ErrRequestJournalDisabled) {
+		t.Errorf("expected ErrRequestJournalDisabled; got %v", err)
+	}
+	orders := NewRequest(http.MethodGet, URLPathEqualTo("/orders"))
+	if err := client.VerifyThat(orders).Once(); !errors.Is(err, ErrRequestJournalDisabled) {
+		t.Errorf("expected ErrRequestJournalDisabled of verification; got %v", err)
+	}
+	if _, err := client.FindRequests(orders); !errors.Is(err, ErrRequestJournalDisabled) {
+		t.Errorf("expected ErrRequestJournalDisabled of find requests; got %v", err)
+	}
+	if _, err := client.FindUnmatchedRequests(); !errors.Is(err, ErrRequestJournalDisabled) {
+		t.Errorf("expected ErrRequestJournalDisabled of find unmatched requests; got %v", err)
+	}
+	if _, err := client.FindNearMissesForUnmatched(); !errors.Is(err, ErrRequestJournalDisabled) {
+		t.Errorf("expected ErrRequestJournalDisabled of near misses of unmatched requests; got %v", err)
+	}
+}
+
+func TestClient_CapturedBodies(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Post(URLPathEqualTo("/orders"))
+	server.setFound(t, orders,
+		map[string]interface{}{"url": "/orders", "method": "POST", "body": `{"id":"1","amount":10}`},
+		map[string]interface{}{"url": "/orders", "method": "POST", "body": `{"id":"2","amount":20}`},
+	)
+
+	type order struct {
+		ID     string `json:"id"`
+		Amount int    `json:"amount"`
+	}
+	var captured []order
+	if err := client.CapturedBodies(orders, &captured); err != nil {
+		t.Fatalf("CapturedBodies error: %v", err)
+	}
+	if !reflect.DeepEqual(captured, []order{{ID: "1", Amount: 10}, {ID: "2", Amount: 20}}) {
+		t.Errorf("unexpected captured bodies %+v", captured)
+	}
+
+	if err := client.CapturedBodies(orders, captured); err == nil {
+		t.Errorf("expected error of not pointer to slice")
+	}
+	var texts []int
+	if err := client.CapturedBodies(orders, &texts); err == nil || !strings.Contains(err.Error(), "POST /orders") {
+		t.Errorf("expected decode error naming the request; got %v", err)
+	}
+}
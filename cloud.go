@@ -0,0 +1,153 @@
+package wiremock
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// cloudDomain is the domain of WireMock Cloud mock APIs.
+const cloudDomain = "wiremockapi.cloud"
+
+// cloudAPIURL is the base url of the WireMock Cloud API managing the mock APIs of the account.
+const cloudAPIURL = "https://api.wiremock.cloud/v1"
+
+// ErrCloudMockAPINotFound is returned when the account has no mock API with requested name or id.
+var ErrCloudMockAPINotFound = errors.New("mock api not found")
+
+// CloudMockAPIURL gives the base url of the WireMock Cloud mock API with the subdomain,
+// e.g. "payments-qa" of https://payments-qa.wiremockapi.cloud.
+func CloudMockAPIURL(subdomain string) string {
+	return "https://" + subdomain + "." + cloudDomain
+}
+
+// NewCloudClient returns *Client driving the WireMock Cloud mock API with the subdomain,
+// authenticated by the API token of the account. Every mock API has its own admin API,
+// so the subdomain selects the mock API the stubs are registered to.
+// Use CloudAccount to select the mock API by its name. Creating and deleting mock APIs is not covered.
+func NewCloudClient(subdomain, apiToken string, options ...ClientOption) *Client {
+	return NewClient(CloudMockAPIURL(subdomain), append([]ClientOption{WithAPIToken(apiToken)}, options...)...)
+}
+
+// A CloudMockAPI is the mock API of the WireMock Cloud account.
+type CloudMockAPI struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+}
+
+// URL gives the base url of the mock API, served at its first domain.
+func (m CloudMockAPI) URL() string {
+	if len(m.Domains) > 0 {
+		return "https://" + m.Domains[0]
+	}
+
+	return CloudMockAPIURL(m.ID)
+}
+
+// A CloudAccount lists and selects the mock APIs of the WireMock Cloud account through the Cloud API.
+type CloudAccount struct {
+	client   *Client
+	apiToken string
+	options  []ClientOption
+}
+
+// NewCloudAccount returns *CloudAccount authenticated by the API token of the account.
+// The options apply to the Cloud API client and to the clients of selected mock APIs.
+func NewCloudAccount(apiToken string, options ...ClientOption) *CloudAccount {
+	return &CloudAccount{
+		client:   NewClient(cloudAPIURL, append([]ClientOption{WithAPIToken(apiToken)}, options...)...),
+		apiToken: apiToken,
+		options:  options,
+	}
+}
+
+// MockAPIs gives the mock APIs of the account.
+func (a *CloudAccount) MockAPIs() ([]CloudMockAPI, error) {
+	res, err := a.client.httpClient.Get(a.client.url + "/mock-apis")
+	if err != nil {
+		return nil, fmt.Errorf("list mock apis: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("list mock apis: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list mock apis: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var mockAPIsResponse struct {
+		MockAPIs []CloudMockAPI `json:"mockApis"`
+	}
+	if err := a.client.codec.Unmarshal(bodyBytes, &mockAPIsResponse); err != nil {
+		return nil, fmt.Errorf("list mock apis: read json error: %s", err.Error())
+	}
+
+	return mockAPIsResponse.MockAPIs, nil
+}
+
+// MockAPI gives the mock API of the account with the name or id.
+func (a *CloudAccount) MockAPI(nameOrID string) (CloudMockAPI, error) {
+	mockAPIs, err := a.MockAPIs()
+	if err != nil {
+		return CloudMockAPI{}, err
+	}
+
+	for _, mockAPI := range mockAPIs {
+		if mockAPI.ID == nameOrID || mockAPI.Name == nameOrID {
+			return mockAPI, nil
+		}
+	}
+
+	return CloudMockAPI{}, fmt.Errorf("select mock api %q: %w", nameOrID, ErrCloudMockAPINotFound)
+}
+
+// Client returns *Client driving the admin API of the mock API with the name or id, e.g.
+//
+//	client, err := wiremock.NewCloudAccount(os.Getenv("WIREMOCK_CLOUD_TOKEN")).Client("payments-qa")
+func (a *CloudAccount) Client(nameOrID string) (*Client, error) {
+	mockAPI, err := a.MockAPI(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(mockAPI.URL(), append([]ClientOption{WithAPIToken(a.apiToken)}, a.options...)...), nil
+}
+
+// WithAPIToken authenticates admin requests by the WireMock Cloud API token.
+// It applies to the http client of any order of options, e.g. set by WithHTTPClient.
+func WithAPIToken(token string) ClientOption {
+	return func(c *Client) {
+		c.authorization = "Token " + token
+	}
+}
+
+// authorizingTransport sets the Authorization header of requests.
+type authorizingTransport struct {
+	authorization string
+	base          http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *authorizingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", t.authorization)
+
+	return t.base.RoundTrip(r)
+}
+
+// authorize wraps the transport of the http client by authorizingTransport.
+func (c *Client) authorize() {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = &authorizingTransport{authorization: c.authorization, base: base}
+	c.httpClient = &httpClient
+}
@@ -0,0 +1,72 @@
+package wiremock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewCloudClient(t *testing.T) {
+	var requests []*http.Request
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	client := NewCloudClient("payments-qa", "secret", WithHTTPClient(httpClient), WithMaxIdleConnsPerHost(16))
+	if err := client.Reset(); err != nil {
+		t.Fatalf("Reset error: %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request through the custom http client; got %d", len(requests))
+	}
+	if url := requests[0].URL.String(); !strings.HasPrefix(url, "https://payments-qa.wiremockapi.cloud/__admin/") {
+		t.Errorf("expected request to the mock API admin; got %s", url)
+	}
+	if authorization := requests[0].Header.Get("Authorization"); authorization != "Token secret" {
+		t.Errorf("expected token authorization; got %q", authorization)
+	}
+}
+
+func TestCloudAccount_Client(t *testing.T) {
+	var requests []*http.Request
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		body := ""
+		if r.URL.Host == "api.wiremock.cloud" {
+			body = `{"mockApis":[{"id":"a1b2c3","name":"Orders","domains":["orders-qa.wiremockapi.cloud"]},{"id":"d4e5f6","name":"Payments"}]}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	account := NewCloudAccount("secret", WithHTTPClient(httpClient))
+
+	mockAPIs, err := account.MockAPIs()
+	if err != nil {
+		t.Fatalf("MockAPIs error: %v", err)
+	}
+	if len(mockAPIs) != 2 || mockAPIs[0].URL() != "https://orders-qa.wiremockapi.cloud" || mockAPIs[1].URL() != "https://d4e5f6.wiremockapi.cloud" {
+		t.Errorf("unexpected mock apis %+v", mockAPIs)
+	}
+	if url := requests[0].URL.String(); url != "https://api.wiremock.cloud/v1/mock-apis" {
+		t.Errorf("expected request to the Cloud API; got %s", url)
+	}
+
+	client, err := account.Client("Orders")
+	if err != nil {
+		t.Fatalf("Client error: %v", err)
+	}
+	if err := client.Reset(); err != nil {
+		t.Fatalf("Reset error: %v", err)
+	}
+	last := requests[len(requests)-1]
+	if !strings.HasPrefix(last.URL.String(), "https://orders-qa.wiremockapi.cloud/__admin/") || last.Header.Get("Authorization") != "Token secret" {
+		t.Errorf("expected authorized request to the selected mock API admin; got %s", last.URL)
+	}
+
+	if _, err := account.Client("Shipping"); !errors.Is(err, ErrCloudMockAPINotFound) {
+		t.Errorf("expected ErrCloudMockAPINotFound; got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package wiremock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StubAllConcurrently creates stub mappings with at most parallelism requests in flight.
+// It tries every stub and returns the joined errors of failed registrations.
+// When ctx is done, the remaining stubs are not registered and ctx.Err() is joined to the errors.
+// Raise WithMaxIdleConnsPerHost to parallelism, so the connections are reused.
+func (c *Client) StubAllConcurrently(ctx context.Context, stubs []*StubRule, parallelism int) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	slots := make(chan struct{}, parallelism)
+
+	for _, stubRule := range stubs {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(stubRule *StubRule) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if err := c.stubFor(ctx, stubRule); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %s", stubRule.UUID(), err.Error()))
+				mu.Unlock()
+			}
+		}(stubRule)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package wiremock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_StubAllConcurrently(t *testing.T) {
+	var inFlight, maxInFlight, registered int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+		for {
+			max := atomic.LoadInt64(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt64(&registered, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, WithMaxIdleConnsPerHost(4))
+
+	stubs := make([]*StubRule, 20)
+	for i := range stubs {
+		stubs[i] = Get(URLPathEqualTo(fmt.Sprintf("/orders/%d", i)))
+	}
+	invalid := Get(URLPathMatching("(a"))
+	stubs[7] = invalid
+
+	err := client.StubAllConcurrently(context.Background(), stubs, 4)
+	if err == nil || !strings.Contains(err.Error(), invalid.UUID()) || strings.Count(err.Error(), "\n") != 0 {
+		t.Errorf("expected single error of invalid stub; got %v", err)
+	}
+	if registered := atomic.LoadInt64(&registered); registered != int64(len(stubs)-1) {
+		t.Errorf("expected %d stubs registered; got %d", len(stubs)-1, registered)
+	}
+	if maxInFlight := atomic.LoadInt64(&maxInFlight); maxInFlight > 4 || maxInFlight < 2 {
+		t.Errorf("expected up to 4 concurrent registrations; got %d", maxInFlight)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.StubAllConcurrently(ctx, stubs, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled; got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package wiremock
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A StubConflict is the pair of stubs of equal priority which can both match the same request,
+// so which one serves it depends on the registration order.
+type StubConflict struct {
+	First  *StubRule
+	Second *StubRule
+	// Identical reports whether the stubs have the same request pattern, so the earlier registered one is never served.
+	Identical bool
+}
+
+// String renders the conflict as a warning.
+func (c StubConflict) String() string {
+	if c.Identical {
+		return fmt.Sprintf("stubs %s and %s of priority %d have identical request patterns", c.First.UUID(), c.Second.UUID(), c.First.Priority())
+	}
+
+	return fmt.Sprintf("stubs %s and %s of priority %d can match the same request", c.First.UUID(), c.Second.UUID(), c.First.Priority())
+}
+
+// FindConflicts inspects stubs for pairs of equal priority with overlapping request patterns.
+// Patterns overlap unless some matcher makes them provably disjoint, e.g. different methods, different URLs
+// or different equalTo values of the same header, so regexp and server side matchers lean towards reporting.
+func FindConflicts(stubs []*StubRule) []StubConflict {
+	var conflicts []StubConflict
+	for i := range stubs {
+		for j := i + 1; j < len(stubs); j++ {
+			first, second := stubs[i], stubs[j]
+			if first.Priority() != second.Priority() || disjointScenarioStates(first, second) {
+				continue
+			}
+			if disjointRequests(first.Request(), second.Request()) {
+				continue
+			}
+
+			firstJSON, firstErr := first.Request().MarshalJSON()
+			secondJSON, secondErr := second.Request().MarshalJSON()
+			conflicts = append(conflicts, StubConflict{
+				First:     first,
+				Second:    second,
+				Identical: firstErr == nil && secondErr == nil && bytes.Equal(firstJSON, secondJSON),
+			})
+		}
+	}
+
+	return conflicts
+}
+
+func disjointScenarioStates(first, second *StubRule) bool {
+	if first.scenarioName == nil || second.scenarioName == nil || *first.scenarioName != *second.scenarioName {
+		return false
+	}
+	if first.requiredScenarioState == nil || second.requiredScenarioState == nil {
+		return false
+	}
+
+	return *first.requiredScenarioState != *second.requiredScenarioState
+}
+
+func disjointRequests(first, second *Request) bool {
+	if first.method != MethodAny && second.method != MethodAny && !strings.EqualFold(first.method, second.method) {
+		return true
+	}
+	if first.URLMatcher() != nil && second.URLMatcher() != nil && disjointURLs(first.URLMatcher(), second.URLMatcher()) {
+		return true
+	}
+
+	for _, params := range [][2]map[string]ParamMatcherInterface{
+		{first.headers, second.headers},
+		{first.queryParams, second.queryParams},
+		{first.cookies, second.cookies},
+	} {
+		for key, matcher := range params[0] {
+			if other, ok := params[1][key]; ok && disjointValues(matcher, other) {
+				return true
+			}
+		}
+	}
+
+	for _, bodyPattern := range first.bodyPatterns {
+		for _, other := range second.bodyPatterns {
+			if disjointValues(bodyPattern, other) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// literalURL gives the path or the url with query the matcher accepts only, when it is not a regexp.
+func literalURL(matcher URLMatcherInterface) (string, bool) {
+	switch matcher.Strategy() {
+	case URLEqualToRule, URLPathEqualToRule:
+		return matcher.Value(), true
+	}
+
+	return "", false
+}
+
+func disjointURLs(first, second URLMatcherInterface) bool {
+	firstURL, firstLiteral := literalURL(first)
+	secondURL, secondLiteral := literalURL(second)
+
+	switch {
+	case firstLiteral && secondLiteral:
+		if first.Strategy() == second.Strategy() {
+			return firstURL != secondURL
+		}
+		// urlPath does not constrain the query, so compare paths only
+		return pathOf(firstURL) != pathOf(secondURL)
+	case firstLiteral:
+		return disjointURLRegexp(first, firstURL, second)
+	case secondLiteral:
+		return disjointURLRegexp(second, secondURL, first)
+	}
+
+	return false
+}
+
+func disjointURLRegexp(literal URLMatcherInterface, url string, regexp URLMatcherInterface) bool {
+	// urlPath leaves the query to any value the url regexp may require
+	if literal.Strategy() == URLPathEqualToRule && regexp.Strategy() == URLMatchingRule {
+		return false
+	}
+
+	return !matchURL(regexp, url)
+}
+
+func pathOf(url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		return url[:i]
+	}
+
+	return url
+}
+
+// disjointValues reports whether no value can satisfy both matchers.
+func disjointValues(first, second ParamMatcherInterface) bool {
+	firstAbsent, secondAbsent := first.Strategy() == ParamAbsent, second.Strategy() == ParamAbsent
+	if firstAbsent || secondAbsent {
+		return firstAbsent != secondAbsent && !(first.Strategy() == ParamDoesNotMatch || second.Strategy() == ParamDoesNotMatch)
+	}
+
+	if value, ok := literalValue(first); ok {
+		matched, known := matchValue(second, &value)
+		return known && !matched
+	}
+	if value, ok := literalValue(second); ok {
+		matched, known := matchValue(first, &value)
+		return known && !matched
+	}
+
+	return false
+}
+
+// literalValue gives the only value accepted by the case sensitive equalTo matcher.
+func literalValue(matcher ParamMatcherInterface) (string, bool) {
+	if matcher.Strategy() != ParamEqualTo || matcher.Flags()["caseInsensitive"] {
+		return "", false
+	}
+
+	return matcher.Value(), true
+}
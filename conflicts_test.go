@@ -0,0 +1,40 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindConflicts(t *testing.T) {
+	order := Get(URLPathEqualTo("/orders/1"))
+	orderCopy := Get(URLPathEqualTo("/orders/1"))
+	anyOrder := Get(URLPathMatching("/orders/[0-9]+"))
+	otherOrder := Get(URLPathEqualTo("/orders/2")).AtPriority(1)
+	users := Get(URLPathEqualTo("/users/me"))
+	jsonOrder := Get(URLPathEqualTo("/orders/1")).WithHeader("Accept", EqualTo("application/json")).AtPriority(2)
+	xmlOrder := Get(URLPathEqualTo("/orders/1")).WithHeader("Accept", EqualTo("application/xml")).AtPriority(2)
+	deleteOrder := Delete(URLPathEqualTo("/orders/1"))
+	paidOrder := Get(URLPathEqualTo("/orders/1")).InScenario("order").WhenScenarioStateIs("paid").AtPriority(3)
+	newOrder := Get(URLPathEqualTo("/orders/1")).InScenario("order").WhenScenarioStateIs("Started").AtPriority(3)
+
+	conflicts := FindConflicts([]*StubRule{order, orderCopy, anyOrder, otherOrder, users, jsonOrder, xmlOrder, deleteOrder, paidOrder, newOrder})
+
+	expected := map[[2]*StubRule]bool{
+		{order, orderCopy}:    true,
+		{order, anyOrder}:     false,
+		{orderCopy, anyOrder}: false,
+	}
+	if len(conflicts) != len(expected) {
+		t.Fatalf("expected %d conflicts; got %v", len(expected), conflicts)
+	}
+	for _, conflict := range conflicts {
+		identical, ok := expected[[2]*StubRule{conflict.First, conflict.Second}]
+		if !ok || conflict.Identical != identical {
+			t.Errorf("unexpected conflict %s", conflict)
+		}
+	}
+
+	if message := conflicts[0].String(); !strings.Contains(message, "identical request patterns") || !strings.Contains(message, order.UUID()) {
+		t.Errorf("unexpected conflict message %q", message)
+	}
+}
@@ -0,0 +1,78 @@
+package wiremock
+
+import "regexp"
+
+// Common content types.
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeXML         = "application/xml"
+	ContentTypeTextXML     = "text/xml"
+	ContentTypeText        = "text/plain"
+	ContentTypeHTML        = "text/html"
+	ContentTypeForm        = "application/x-www-form-urlencoded"
+	ContentTypeMultipart   = "multipart/form-data"
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+// ContentTypeMatcher returns the Content-Type header matcher of the media type, ignoring its case and parameters,
+// so ContentTypeMatcher(ContentTypeJSON) matches "application/json; charset=UTF-8".
+func ContentTypeMatcher(mediaType string) ParamMatcher {
+	return Matching(`(?i)` + regexp.QuoteMeta(mediaType) + `\s*(;.*)?`)
+}
+
+// WithContentType adds the Content-Type header matcher of the media type, see ContentTypeMatcher
+func (r *Request) WithContentType(mediaType string) *Request {
+	return r.WithHeader("Content-Type", ContentTypeMatcher(mediaType))
+}
+
+// WithJSONContentType adds the Content-Type header matcher of JSON
+func (r *Request) WithJSONContentType() *Request {
+	return r.WithContentType(ContentTypeJSON)
+}
+
+// WithXMLContentType adds the Content-Type header matcher of XML
+func (r *Request) WithXMLContentType() *Request {
+	return r.WithContentType(ContentTypeXML)
+}
+
+// WithContentType adds the Content-Type header matcher of the media type and returns *StubRule
+func (s *StubRule) WithContentType(mediaType string) *StubRule {
+	s.request.WithContentType(mediaType)
+	return s
+}
+
+// WithJSONContentType adds the Content-Type header matcher of JSON and returns *StubRule
+func (s *StubRule) WithJSONContentType() *StubRule {
+	s.request.WithJSONContentType()
+	return s
+}
+
+// WithXMLContentType adds the Content-Type header matcher of XML and returns *StubRule
+func (s *StubRule) WithXMLContentType() *StubRule {
+	s.request.WithXMLContentType()
+	return s
+}
+
+// WithContentType sets the Content-Type header of the media type with the charset parameter, omitted when empty.
+// Set it after WillReturn and its siblings, which replace the response headers.
+func (r *Response) WithContentType(mediaType, charset string) *Response {
+	if r.headers == nil {
+		r.headers = map[string]string{}
+	}
+
+	if charset != "" {
+		mediaType += "; charset=" + charset
+	}
+	r.headers["Content-Type"] = mediaType
+	return r
+}
+
+// WithJSONContentType sets the Content-Type header of JSON with the charset parameter, omitted when empty
+func (r *Response) WithJSONContentType(charset string) *Response {
+	return r.WithContentType(ContentTypeJSON, charset)
+}
+
+// WithXMLContentType sets the Content-Type header of XML with the charset parameter, omitted when empty
+func (r *Response) WithXMLContentType(charset string) *Response {
+	return r.WithContentType(ContentTypeXML, charset)
+}
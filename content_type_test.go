@@ -0,0 +1,26 @@
+package wiremock
+
+import "testing"
+
+func TestContentType(t *testing.T) {
+	stubRule := Post(URLPathEqualTo("/orders")).
+		WithJSONContentType().
+		WillReturn(`{"id":1}`, nil, 201)
+	stubRule.Response().WithJSONContentType("utf-8")
+
+	if contentType := stubRule.Response().Headers()["Content-Type"]; contentType != "application/json; charset=utf-8" {
+		t.Errorf("unexpected response content type %q", contentType)
+	}
+
+	for contentType, expected := range map[string]bool{
+		"application/json":                true,
+		"Application/JSON; charset=UTF-8": true,
+		"application/jsonp":               false,
+		"text/plain":                      false,
+	} {
+		request := &LoggedRequest{Method: "POST", URL: "/orders", Headers: map[string]string{"Content-Type": contentType}}
+		if matched, known := stubRule.Match(request); !known || matched != expected {
+			t.Errorf("%q: expected matched %v; got %v, %v", contentType, expected, matched, known)
+		}
+	}
+}
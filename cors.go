@@ -0,0 +1,146 @@
+package wiremock
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS is the configuration of Access-Control-Allow-* headers of the preflight response.
+type CORS struct {
+	allowOrigin      string
+	allowMethods     []string
+	allowHeaders     []string
+	exposeHeaders    []string
+	allowCredentials bool
+	maxAge           time.Duration
+}
+
+// NewCORS returns *CORS allowing any origin, any header and the common http methods.
+func NewCORS() *CORS {
+	return &CORS{
+		allowOrigin: "*",
+		allowMethods: []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+			http.MethodOptions,
+		},
+		allowHeaders: []string{"*"},
+	}
+}
+
+// WithAllowOrigin sets Access-Control-Allow-Origin and returns *CORS
+func (c *CORS) WithAllowOrigin(origin string) *CORS {
+	c.allowOrigin = origin
+	return c
+}
+
+// WithAllowMethods sets Access-Control-Allow-Methods and returns *CORS
+func (c *CORS) WithAllowMethods(methods ...string) *CORS {
+	c.allowMethods = methods
+	return c
+}
+
+// WithAllowHeaders sets Access-Control-Allow-Headers and returns *CORS
+func (c *CORS) WithAllowHeaders(headers ...string) *CORS {
+	c.allowHeaders = headers
+	return c
+}
+
+// WithExposeHeaders sets Access-Control-Expose-Headers and returns *CORS
+func (c *CORS) WithExposeHeaders(headers ...string) *CORS {
+	c.exposeHeaders = headers
+	return c
+}
+
+// WithAllowCredentials sets Access-Control-Allow-Credentials and returns *CORS.
+// Browsers reject the wildcards with credentials, so the preflight echoes the Origin,
+// the requested method and headers of the request instead of "*", using response templating.
+func (c *CORS) WithAllowCredentials() *CORS {
+	c.allowCredentials = true
+	return c
+}
+
+// WithMaxAge sets Access-Control-Max-Age and returns *CORS
+func (c *CORS) WithMaxAge(maxAge time.Duration) *CORS {
+	c.maxAge = maxAge
+	return c
+}
+
+// headers gives the preflight response headers.
+func (c *CORS) headers() map[string]string {
+	headers := map[string]string{
+		"Access-Control-Allow-Origin": c.allowed([]string{c.allowOrigin}, "Origin"),
+	}
+	if len(c.allowMethods) > 0 {
+		headers["Access-Control-Allow-Methods"] = c.allowed(c.allowMethods, "Access-Control-Request-Method")
+	}
+	if len(c.allowHeaders) > 0 {
+		headers["Access-Control-Allow-Headers"] = c.allowed(c.allowHeaders, "Access-Control-Request-Headers")
+	}
+	if len(c.exposeHeaders) > 0 {
+		headers["Access-Control-Expose-Headers"] = strings.Join(c.exposeHeaders, ", ")
+	}
+	if c.allowCredentials {
+		headers["Access-Control-Allow-Credentials"] = "true"
+		if c.allowOrigin == "*" {
+			headers["Vary"] = "Origin"
+		}
+	}
+	if c.maxAge > 0 {
+		headers["Access-Control-Max-Age"] = strconv.FormatInt(int64(c.maxAge/time.Second), 10)
+	}
+
+	return headers
+}
+
+// allowed gives the allowed values, or the template of the request header echoing them
+// when the values are the wildcard rejected with credentials.
+func (c *CORS) allowed(values []string, requestHeader string) string {
+	if c.allowCredentials && len(values) == 1 && values[0] == "*" {
+		return "{{request.headers.[" + requestHeader + "]}}"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// echoed reports whether the preflight echoes request headers, see WithAllowCredentials.
+func (c *CORS) echoed() bool {
+	for _, values := range [][]string{{c.allowOrigin}, c.allowMethods, c.allowHeaders} {
+		if c.allowCredentials && len(values) == 1 && values[0] == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CORSPreflight returns the OPTIONS *StubRule answering browser preflight requests
+// to pathPrefix and every path below it. A nil cors means NewCORS().
+func CORSPreflight(pathPrefix string, cors *CORS) *StubRule {
+	if cors == nil {
+		cors = NewCORS()
+	}
+
+	pattern := regexp.QuoteMeta(strings.TrimSuffix(pathPrefix, "/")) + "(/.*)?"
+
+	stubRule := Options(URLPathMatching(pattern)).
+		WithHeader("Access-Control-Request-Method", Matching(".+")).
+		WillReturn("", cors.headers(), http.StatusNoContent)
+	if cors.echoed() {
+		stubRule.WithResponseTemplating()
+	}
+
+	return stubRule
+}
+
+// StubCORSPreflight registers CORSPreflight stub for pathPrefix.
+func (c *Client) StubCORSPreflight(pathPrefix string, cors *CORS) error {
+	return c.StubFor(CORSPreflight(pathPrefix, cors))
+}
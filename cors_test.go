@@ -0,0 +1,52 @@
+package wiremock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	stubRule := CORSPreflight("/api/", NewCORS().
+		WithAllowOrigin("https://app.example").
+		WithAllowMethods(http.MethodGet, http.MethodPost).
+		WithAllowCredentials().
+		WithMaxAge(10*time.Minute))
+
+	if stubRule.Request().Method() != http.MethodOptions {
+		t.Errorf("expected OPTIONS method; got %s", stubRule.Request().Method())
+	}
+	for url, expected := range map[string]bool{"/api": true, "/api/orders/1": true, "/apix": false, "/other": false} {
+		if matchURL(stubRule.Request().URLMatcher(), url) != expected {
+			t.Errorf("expected %s match to be %v", url, expected)
+		}
+	}
+
+	expectedHeaders := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example",
+		"Access-Control-Allow-Methods":     "GET, POST",
+		"Access-Control-Allow-Headers":     "{{request.headers.[Access-Control-Request-Headers]}}",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	for name, value := range expectedHeaders {
+		if stubRule.Response().Headers()[name] != value {
+			t.Errorf("expected header %s %q; got %q", name, value, stubRule.Response().Headers()[name])
+		}
+	}
+	if stubRule.Response().Status() != http.StatusNoContent {
+		t.Errorf("expected status 204; got %d", stubRule.Response().Status())
+	}
+	if !stubRule.Response().hasTransformer(ResponseTemplateTransformer) {
+		t.Error("expected the requested headers echoed by response templating")
+	}
+
+	anyOrigin := CORSPreflight("/api", NewCORS().WithAllowCredentials()).Response().Headers()
+	if anyOrigin["Access-Control-Allow-Origin"] != "{{request.headers.[Origin]}}" || anyOrigin["Vary"] != "Origin" {
+		t.Errorf("expected the origin echoed with credentials; got %v", anyOrigin)
+	}
+	if withoutCredentials := CORSPreflight("/api", nil); withoutCredentials.Response().hasTransformer(ResponseTemplateTransformer) ||
+		withoutCredentials.Response().Headers()["Access-Control-Allow-Origin"] != "*" {
+		t.Errorf("expected wildcards without credentials; got %s", withoutCredentials)
+	}
+}
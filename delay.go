@@ -0,0 +1,201 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Types of delay distributions.
+const (
+	DelayDistributionLogNormal = "lognormal"
+	DelayDistributionUniform   = "uniform"
+)
+
+// A DelayDistribution is the random delay of responses.
+type DelayDistribution struct {
+	kind   string
+	median time.Duration
+	sigma  float64
+	lower  time.Duration
+	upper  time.Duration
+}
+
+// LogNormalDelay returns DelayDistribution with long tail around median, wider with bigger sigma.
+func LogNormalDelay(median time.Duration, sigma float64) DelayDistribution {
+	return DelayDistribution{
+		kind:   DelayDistributionLogNormal,
+		median: median,
+		sigma:  sigma,
+	}
+}
+
+// UniformDelay returns DelayDistribution evenly spread between lower and upper.
+func UniformDelay(lower, upper time.Duration) DelayDistribution {
+	return DelayDistribution{
+		kind:  DelayDistributionUniform,
+		lower: lower,
+		upper: upper,
+	}
+}
+
+// Type is getter for distribution type
+func (d DelayDistribution) Type() string {
+	return d.kind
+}
+
+// MarshalJSON gives valid JSON or error.
+func (d DelayDistribution) MarshalJSON() ([]byte, error) {
+	switch d.kind {
+	case DelayDistributionLogNormal:
+		return json.Marshal(map[string]interface{}{
+			"type":   d.kind,
+			"median": d.median.Milliseconds(),
+			"sigma":  d.sigma,
+		})
+	case DelayDistributionUniform:
+		return json.Marshal(map[string]interface{}{
+			"type":  d.kind,
+			"lower": d.lower.Milliseconds(),
+			"upper": d.upper.Milliseconds(),
+		})
+	}
+
+	return nil, fmt.Errorf("unknown delay distribution type %q", d.kind)
+}
+
+// UnmarshalJSON fills DelayDistribution from WireMock JSON.
+func (d *DelayDistribution) UnmarshalJSON(data []byte) error {
+	jsonDistribution := struct {
+		Type   string  `json:"type"`
+		Median int64   `json:"median"`
+		Sigma  float64 `json:"sigma"`
+		Lower  int64   `json:"lower"`
+		Upper  int64   `json:"upper"`
+	}{}
+	if err := json.Unmarshal(data, &jsonDistribution); err != nil {
+		return err
+	}
+
+	*d = DelayDistribution{
+		kind:   jsonDistribution.Type,
+		median: time.Duration(jsonDistribution.Median) * time.Millisecond,
+		sigma:  jsonDistribution.Sigma,
+		lower:  time.Duration(jsonDistribution.Lower) * time.Millisecond,
+		upper:  time.Duration(jsonDistribution.Upper) * time.Millisecond,
+	}
+
+	return nil
+}
+
+// A LatencyProfile is the preset of network conditions.
+type LatencyProfile struct {
+	name         string
+	distribution *DelayDistribution
+}
+
+func newLatencyProfile(name string, distribution DelayDistribution) LatencyProfile {
+	return LatencyProfile{name: name, distribution: &distribution}
+}
+
+// Network condition presets.
+var (
+	LatencyNone           = LatencyProfile{name: "none"}
+	Latency4G             = newLatencyProfile("4g", LogNormalDelay(50*time.Millisecond, 0.2))
+	LatencyFast3G         = newLatencyProfile("fast-3g", LogNormalDelay(150*time.Millisecond, 0.25))
+	LatencySlow3G         = newLatencyProfile("slow-3g", LogNormalDelay(400*time.Millisecond, 0.3))
+	LatencyFlakySatellite = newLatencyProfile("flaky-satellite", LogNormalDelay(650*time.Millisecond, 0.8))
+)
+
+// Name is getter for profile name
+func (p LatencyProfile) Name() string {
+	return p.name
+}
+
+// Distribution is getter for delay distribution, nil means no delay
+func (p LatencyProfile) Distribution() *DelayDistribution {
+	return p.distribution
+}
+
+// WithFixedDelay sets delay of response, sent to WireMock in whole milliseconds, and returns *StubRule
+func (s *StubRule) WithFixedDelay(delay time.Duration) *StubRule {
+	s.response.fixedDelay = delay
+	return s
+}
+
+// FixedDelay is getter for fixed delay
+func (r *Response) FixedDelay() time.Duration {
+	return r.fixedDelay
+}
+
+// DelayDistribution is getter for delay distribution
+func (r *Response) DelayDistribution() *DelayDistribution {
+	return r.delayDistribution
+}
+
+// WithDelayDistribution sets random delay of response and returns *StubRule
+func (s *StubRule) WithDelayDistribution(distribution DelayDistribution) *StubRule {
+	s.response.delayDistribution = &distribution
+	return s
+}
+
+// WithLatencyProfile sets response delay of the network conditions preset and returns *StubRule
+func (s *StubRule) WithLatencyProfile(profile LatencyProfile) *StubRule {
+	s.response.delayDistribution = profile.distribution
+	return s
+}
+
+// WithDelayDistribution sets random delay added to every response and returns *GlobalSettings
+func (s *GlobalSettings) WithDelayDistribution(distribution DelayDistribution) *GlobalSettings {
+	s.delayDistribution = &distribution
+	return s
+}
+
+// DelayDistribution is getter for delay distribution
+func (s *GlobalSettings) DelayDistribution() *DelayDistribution {
+	return s.delayDistribution
+}
+
+// ApplyLatencyProfile sets delays of every response to the network conditions preset, keeping other settings.
+// LatencyNone removes the delays.
+func (c *Client) ApplyLatencyProfile(profile LatencyProfile) error {
+	settings, err := c.GetSettings()
+	if err != nil {
+		return fmt.Errorf("apply latency profile: %s", err.Error())
+	}
+
+	settings.fixedDelay = 0
+	settings.delayDistribution = profile.distribution
+
+	if err := c.UpdateSettings(settings); err != nil {
+		return fmt.Errorf("apply latency profile: %s", err.Error())
+	}
+
+	return nil
+}
+
+// chunkedDribbleDelay is the WireMock JSON of the body sent in chunks, totalDuration is in milliseconds.
+type chunkedDribbleDelay struct {
+	NumberOfChunks int64 `json:"numberOfChunks"`
+	TotalDuration  int64 `json:"totalDuration"`
+}
+
+// WithChunkedDribbleDelay makes the response body sent in numberOfChunks chunks of equal size
+// evenly spread over totalDuration and returns *StubRule
+func (s *StubRule) WithChunkedDribbleDelay(numberOfChunks int64, totalDuration time.Duration) *StubRule {
+	s.response.chunkedDribbleDelay = &chunkedDribbleDelay{
+		NumberOfChunks: numberOfChunks,
+		TotalDuration:  totalDuration.Milliseconds(),
+	}
+	return s
+}
+
+// ChunkedDribbleDelay gives the number of chunks and the total duration of the body set by WithChunkedDribbleDelay,
+// zeros when it is not set.
+func (r *Response) ChunkedDribbleDelay() (int64, time.Duration) {
+	if r.chunkedDribbleDelay == nil {
+		return 0, 0
+	}
+
+	return r.chunkedDribbleDelay.NumberOfChunks, time.Duration(r.chunkedDribbleDelay.TotalDuration) * time.Millisecond
+}
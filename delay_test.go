@@ -0,0 +1,62 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ApplyLatencyProfile(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	if err := client.UpdateSettings(NewGlobalSettings().WithFixedDelay(time.Second).WithExtended("region", "eu")); err != nil {
+		t.Fatalf("UpdateSettings error: %v", err)
+	}
+	if err := client.ApplyLatencyProfile(LatencySlow3G); err != nil {
+		t.Fatalf("ApplyLatencyProfile error: %v", err)
+	}
+
+	settings, err := client.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings error: %v", err)
+	}
+	if settings.FixedDelay() != 0 || settings.Extended()["region"] != "eu" {
+		t.Errorf("expected fixed delay replaced and extended settings kept; got %+v", settings)
+	}
+	if distribution := settings.DelayDistribution(); distribution == nil || *distribution != *LatencySlow3G.Distribution() {
+		t.Errorf("expected slow 3G distribution; got %+v", distribution)
+	}
+}
+
+func TestStubRule_WithDelayDistribution(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/orders")).WithDelayDistribution(UniformDelay(10*time.Millisecond, 30*time.Millisecond))
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"delayDistribution":{"lower":10,"type":"uniform","upper":30}`) {
+		t.Errorf("expected uniform delay distribution; got %s", raw)
+	}
+}
+
+func TestStubRule_WithFixedDelay(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/orders")).WithFixedDelay(1500 * time.Millisecond)
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"fixedDelayMilliseconds":1500`) {
+		t.Errorf("expected fixed delay in milliseconds; got %s", raw)
+	}
+
+	var decoded StubRule
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("StubRule UnmarshalJSON error: %v", err)
+	}
+	if delay := decoded.Response().FixedDelay(); delay != 1500*time.Millisecond {
+		t.Errorf("expected fixed delay 1.5s; got %s", delay)
+	}
+}
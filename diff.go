@@ -0,0 +1,175 @@
+package wiremock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A FieldResult is the result of matching one matcher of the request pattern against the request.
+type FieldResult struct {
+	// Field names the matcher, e.g. "method", "urlPath" or "header Accept equalTo".
+	Field string
+	// Expected is the value of the matcher.
+	Expected string
+	// Actual is the value of the request, nil when absent.
+	Actual  *string
+	Matched bool
+	// Known is false when the matcher can be evaluated only by the server, then Matched is meaningless.
+	Known bool
+	// Suggestion is the DSL change making the failed matcher match the request, empty when none is known,
+	// e.g. "the case differs, consider EqualToIgnoreCase(\"a\")".
+	Suggestion string
+}
+
+// SortNearMisses orders near misses from the closest one, keeping the server order of equally distant ones.
+func SortNearMisses(nearMisses []NearMiss) {
+	sort.SliceStable(nearMisses, func(i, j int) bool {
+		return nearMisses[i].MatchResult.Distance < nearMisses[j].MatchResult.Distance
+	})
+}
+
+// FormatNearMisses renders diffs of near misses separated by blank lines.
+func FormatNearMisses(nearMisses []NearMiss) string {
+	diffs := make([]string, len(nearMisses))
+	for i := range nearMisses {
+		diffs[i] = strings.TrimSuffix(nearMisses[i].Diff(), "\n")
+	}
+
+	return strings.Join(diffs, "\n\n")
+}
+
+// FieldResults matches the nearly matched stub or request pattern against the request field by field.
+// WireMock reports the distance only, so the fields are evaluated locally.
+func (m *NearMiss) FieldResults() ([]FieldResult, error) {
+	request, err := m.RequestPattern()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, nil
+	}
+
+	var results []FieldResult
+	method := m.Request.Method
+	results = append(results, FieldResult{
+		Field:    "method",
+		Expected: request.Method(),
+		Actual:   &method,
+		Matched:  request.Method() == MethodAny || request.Method() == method,
+		Known:    true,
+	})
+
+	if request.URLMatcher() != nil {
+		url := m.Request.URL
+		urlMatcher := request.URLMatcher()
+		result := FieldResult{
+			Field:    string(urlMatcher.Strategy()),
+			Expected: urlMatcher.Value(),
+			Actual:   &url,
+			Matched:  matchURL(urlMatcher, url),
+			Known:    true,
+		}
+		if !result.Matched {
+			result.Suggestion = suggestURLFix(urlMatcher, url)
+		}
+		results = append(results, result)
+	}
+
+	results = appendParamResults(results, "header", request.Headers(), m.Request.headerValues)
+	results = appendParamResults(results, "query", request.QueryParams(), m.Request.queryValues)
+	results = appendParamResults(results, "cookie", request.Cookies(), m.Request.cookieValues)
+
+	body := string(m.Request.Body)
+	for _, bodyPattern := range request.BodyPatterns() {
+		matched, known := matchValue(bodyPattern, &body)
+		result := FieldResult{
+			Field:    "body " + string(bodyPattern.Strategy()),
+			Expected: bodyPattern.Value(),
+			Actual:   &body,
+			Matched:  matched,
+			Known:    known,
+		}
+		if !matched {
+			// equalToJson with flags is not evaluated locally, yet the missing flags are suggested
+			result.Suggestion = suggestParamFix(bodyPattern, body)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Diff renders the nearly matched stub or request pattern against the request, one line per matcher.
+// Lines of the stub failing to match are prefixed with "-" and followed by the actual value prefixed with "+".
+// Matchers which can be evaluated only by the server are prefixed with "?".
+// Failed matchers are followed by the hint of the DSL change making them match, when one is known.
+func (m *NearMiss) Diff() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", m.Request.Method, m.Request.URL)
+
+	results, err := m.FieldResults()
+	if err != nil || results == nil {
+		fmt.Fprintf(&b, "closest stub (distance %.2f) cannot be rendered\n", m.MatchResult.Distance)
+		return b.String()
+	}
+	if stubRule, _ := m.StubMapping(); stubRule != nil {
+		fmt.Fprintf(&b, "closest stub %s (distance %.2f):\n", stubRule.UUID(), m.MatchResult.Distance)
+	} else {
+		fmt.Fprintf(&b, "distance %.2f from the pattern:\n", m.MatchResult.Distance)
+	}
+
+	for _, result := range results {
+		writeDiffLine(&b, result)
+	}
+
+	return b.String()
+}
+
+func appendParamResults(results []FieldResult, kind string, matchers map[string]ParamMatcherInterface, values func(key string) []string) []FieldResult {
+	keys := make([]string, 0, len(matchers))
+	for key := range matchers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		matcher := matchers[key]
+		var actual *string
+		if keyValues := values(key); keyValues != nil {
+			joined := strings.Join(keyValues, ", ")
+			actual = &joined
+		}
+		matched, known := matchAnyValue(matcher, values(key))
+		result := FieldResult{
+			Field:    fmt.Sprintf("%s %s %s", kind, key, matcher.Strategy()),
+			Expected: matcher.Value(),
+			Actual:   actual,
+			Matched:  matched,
+			Known:    known,
+		}
+		if known && !matched && len(values(key)) == 1 {
+			result.Suggestion = suggestParamFix(matcher, values(key)[0])
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func writeDiffLine(b *strings.Builder, result FieldResult) {
+	switch {
+	case !result.Known:
+		fmt.Fprintf(b, "? %s: %s\n", result.Field, result.Expected)
+	case result.Matched:
+		fmt.Fprintf(b, "  %s: %s\n", result.Field, result.Expected)
+	case result.Actual == nil:
+		fmt.Fprintf(b, "- %s: %s\n+ <absent>\n", result.Field, result.Expected)
+	default:
+		fmt.Fprintf(b, "- %s: %s\n+ %s\n", result.Field, result.Expected, *result.Actual)
+	}
+
+	if result.Suggestion != "" {
+		fmt.Fprintf(b, "  hint: %s\n", result.Suggestion)
+	}
+}
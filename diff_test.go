@@ -0,0 +1,107 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNearMiss_FieldResults(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/orders")).
+		WithHeader("Accept", EqualTo("application/json")).
+		WithQueryParam("page", EqualTo("1"))
+	raw, _ := stubRule.MarshalJSON()
+
+	nearMisses := []NearMiss{
+		{MatchResult: MatchResult{Distance: 0.5}},
+		{
+			Request:        LoggedRequest{Method: "GET", URL: "/orders?page=2", Headers: map[string]string{"accept": "application/json"}},
+			RawStubMapping: json.RawMessage(raw),
+			MatchResult:    MatchResult{Distance: 0.1},
+		},
+	}
+	SortNearMisses(nearMisses)
+	if nearMisses[0].MatchResult.Distance != 0.1 {
+		t.Fatalf("expected closest near miss first; got %+v", nearMisses)
+	}
+
+	results, err := nearMisses[0].FieldResults()
+	if err != nil {
+		t.Fatalf("FieldResults error: %v", err)
+	}
+
+	expected := []struct {
+		field   string
+		matched bool
+	}{
+		{"method", true},
+		{"urlPath", true},
+		{"header Accept equalTo", true},
+		{"query page equalTo", false},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results; got %+v", len(expected), results)
+	}
+	for i, result := range results {
+		if result.Field != expected[i].field || result.Matched != expected[i].matched || !result.Known {
+			t.Errorf("result %d: expected %s matched %v; got %+v", i, expected[i].field, expected[i].matched, result)
+		}
+	}
+	if actual := results[3].Actual; actual == nil || *actual != "2" {
+		t.Errorf("expected actual query value 2; got %v", actual)
+	}
+
+	if results, err := nearMisses[1].FieldResults(); err != nil || results != nil {
+		t.Errorf("expected no results without pattern; got %v, %v", results, err)
+	}
+}
+
+func TestNearMiss_Suggestions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stubRule *StubRule
+		request  LoggedRequest
+		expected string
+	}{
+		{
+			name:     "header case",
+			stubRule: Get(URLPathEqualTo("/orders")).WithHeader("X-Mode", EqualTo("a")),
+			request:  LoggedRequest{Method: "GET", URL: "/orders", Headers: map[string]string{"X-Mode": "A"}},
+			expected: `  hint: the case differs, consider EqualToIgnoreCase("a")`,
+		},
+		{
+			name:     "url with query",
+			stubRule: Get(URLEqualTo("/orders")),
+			request:  LoggedRequest{Method: "GET", URL: "/orders?page=2"},
+			expected: `  hint: URLEqualTo matches the query too, consider URLPathEqualTo("/orders")`,
+		},
+		{
+			name:     "trailing slash",
+			stubRule: Get(URLPathEqualTo("/orders")),
+			request:  LoggedRequest{Method: "GET", URL: "/orders/"},
+			expected: `  hint: the trailing slash differs, consider URLPathEqualTo("/orders").IgnoreTrailingSlash()`,
+		},
+		{
+			name:     "extra json fields",
+			stubRule: Post(URLPathEqualTo("/orders")).WithBodyPattern(EqualToJson(`{"items":[1,2]}`)),
+			request:  LoggedRequest{Method: "POST", URL: "/orders", Body: []byte(`{"id":"1","items":[2,1]}`)},
+			expected: `  hint: consider EqualToJson(json, IgnoreExtraElements, IgnoreArrayOrder)`,
+		},
+		{
+			name:     "partial pattern",
+			stubRule: Get(URLPathEqualTo("/orders")).WithQueryParam("q", Matching("shoe")),
+			request:  LoggedRequest{Method: "GET", URL: "/orders?q=red+shoes", QueryParams: map[string][]string{"q": {"red shoes"}}},
+			expected: `  hint: the pattern matches a part of the value, but the whole value is matched, consider Matching(".*(?:shoe).*")`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, _ := tc.stubRule.MarshalJSON()
+			nearMiss := NearMiss{Request: tc.request, RawStubMapping: raw}
+			if diff := nearMiss.Diff(); !strings.Contains(diff, tc.expected+"\n") {
+				t.Errorf("expected %s in diff:\n%s", tc.expected, diff)
+			}
+		})
+	}
+}
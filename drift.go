@@ -0,0 +1,156 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A StubsDrift is the difference between the registered stubs and the expected ones, e.g. loaded from fixture files.
+// Stubs are paired by id.
+type StubsDrift struct {
+	// Added are expected stubs which are not registered.
+	Added []*StubRule
+	// Removed are registered stubs which are not expected.
+	Removed []*StubRule
+	// Changed are stubs registered with other content than expected.
+	Changed []StubChange
+}
+
+// A StubChange is the stub registered with other content than expected.
+type StubChange struct {
+	Expected   *StubRule
+	Registered *StubRule
+	// Fields are sorted JSON paths of the differing values, e.g. "response.body" or "request.headers.Accept".
+	Fields []string
+}
+
+// Empty reports whether the registered stubs are the expected ones.
+func (d *StubsDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the drift one stub per line: added prefixed with "+", removed with "-" and changed with "~".
+func (d *StubsDrift) String() string {
+	var b strings.Builder
+	for _, stubRule := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", describeStub(stubRule))
+	}
+	for _, stubRule := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", describeStub(stubRule))
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s: %s\n", describeStub(change.Expected), strings.Join(change.Fields, ", "))
+	}
+
+	return b.String()
+}
+
+// DiffStubs compares the registered stubs with the expected ones, so drift of shared mock environments can be detected:
+//
+//	stubs, err := wiremock.NewMappingLoader("testdata/mappings").Load()
+//	...
+//	drift, err := client.DiffStubs(stubs)
+//	...
+//	if !drift.Empty() {
+//		t.Errorf("stubs drifted:\n%s", drift)
+//	}
+//
+// Stubs are compared by their JSON, so key order and fields this client does not know do not matter.
+func (c *Client) DiffStubs(expected []*StubRule) (*StubsDrift, error) {
+	registered, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("diff stubs: %s", err.Error())
+	}
+
+	registeredByID := make(map[string]*StubRule, len(registered))
+	for _, stubRule := range registered {
+		registeredByID[stubRule.UUID()] = stubRule
+	}
+
+	drift := &StubsDrift{}
+	expectedIDs := make(map[string]bool, len(expected))
+	for _, stubRule := range expected {
+		expectedIDs[stubRule.UUID()] = true
+		registeredStub, ok := registeredByID[stubRule.UUID()]
+		if !ok {
+			drift.Added = append(drift.Added, stubRule)
+			continue
+		}
+
+		fields, err := stubDifference(stubRule, registeredStub)
+		if err != nil {
+			return nil, fmt.Errorf("diff stubs: %s: %s", stubRule.UUID(), err.Error())
+		}
+		if len(fields) > 0 {
+			drift.Changed = append(drift.Changed, StubChange{Expected: stubRule, Registered: registeredStub, Fields: fields})
+		}
+	}
+	for _, stubRule := range registered {
+		if !expectedIDs[stubRule.UUID()] {
+			drift.Removed = append(drift.Removed, stubRule)
+		}
+	}
+
+	return drift, nil
+}
+
+// stubDifference gives sorted JSON paths of the values differing between stubs.
+func stubDifference(a, b *StubRule) ([]string, error) {
+	var values [2]interface{}
+	for i, stubRule := range []*StubRule{a, b} {
+		raw, err := stubRule.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var fields []string
+	appendDifference(&fields, "", values[0], values[1])
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+func appendDifference(fields *[]string, path string, a, b interface{}) {
+	aObject, aIsObject := a.(map[string]interface{})
+	bObject, bIsObject := b.(map[string]interface{})
+	if !aIsObject || !bIsObject {
+		if !reflect.DeepEqual(a, b) {
+			*fields = append(*fields, path)
+		}
+		return
+	}
+
+	for key, value := range aObject {
+		appendDifference(fields, joinFieldPath(path, key), value, bObject[key])
+	}
+	for key, value := range bObject {
+		if _, ok := aObject[key]; !ok {
+			appendDifference(fields, joinFieldPath(path, key), nil, value)
+		}
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// describeStub gives the id, method and url of the stub.
+func describeStub(stubRule *StubRule) string {
+	description := stubRule.UUID() + " " + stubRule.Request().Method()
+	if urlMatcher := stubRule.Request().URLMatcher(); urlMatcher != nil {
+		description += " " + urlMatcher.Value()
+	}
+
+	return description
+}
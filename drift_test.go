@@ -0,0 +1,50 @@
+package wiremock
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_DiffStubs(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	same := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)
+	changed := Get(URLPathEqualTo("/users")).WillReturn("[]", nil, 200)
+	removed := Delete(URLPathEqualTo("/orders/1"))
+	for _, stubRule := range []*StubRule{same, changed, removed} {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	added := Post(URLPathEqualTo("/orders"))
+	expected := []*StubRule{
+		same.Clone().WithID(same.UUID()),
+		changed.Clone().WithID(changed.UUID()).WithHeader("Accept", EqualTo(ContentTypeJSON)).WillReturn("[{}]", nil, 200),
+		added,
+	}
+	drift, err := client.DiffStubs(expected)
+	if err != nil {
+		t.Fatalf("DiffStubs error: %v", err)
+	}
+
+	if drift.Empty() || len(drift.Added) != 1 || drift.Added[0] != added || len(drift.Removed) != 1 || drift.Removed[0].UUID() != removed.UUID() {
+		t.Fatalf("unexpected drift:\n%s", drift)
+	}
+	if len(drift.Changed) != 1 || !reflect.DeepEqual(drift.Changed[0].Fields, []string{"request.headers", "response.body"}) {
+		t.Errorf("unexpected changes %+v", drift.Changed)
+	}
+	if rendered := drift.String(); !strings.Contains(rendered, "~ "+changed.UUID()+" GET /users: request.headers, response.body\n") {
+		t.Errorf("unexpected rendered drift:\n%s", rendered)
+	}
+
+	drift, err = client.DiffStubs([]*StubRule{same, changed, removed})
+	if err != nil {
+		t.Fatalf("DiffStubs error: %v", err)
+	}
+	if !drift.Empty() {
+		t.Errorf("expected no drift; got:\n%s", drift)
+	}
+}
@@ -0,0 +1,81 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Equal reports whether stubs are semantically equal: they have equal JSON representations,
+// regardless of ids, map ordering and formatting of equalToJson values.
+func (s *StubRule) Equal(other *StubRule) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	return semanticallyEqual(s.withoutID(), other.withoutID())
+}
+
+// Equal reports whether request patterns are semantically equal, see StubRule.Equal.
+func (r *Request) Equal(other *Request) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+
+	return semanticallyEqual(r, other)
+}
+
+func (s *StubRule) withoutID() *StubRule {
+	stubRule := *s
+	stubRule.uuid = ""
+	return &stubRule
+}
+
+func semanticallyEqual(a, b json.Marshaler) bool {
+	aValue, err := normalizedJSON(a)
+	if err != nil {
+		return false
+	}
+	bValue, err := normalizedJSON(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func normalizedJSON(value json.Marshaler) (interface{}, error) {
+	raw, err := value.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+
+	return normalizeJSONValue(decoded), nil
+}
+
+// normalizeJSONValue replaces equalToJson strings by the JSON they hold.
+func normalizeJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if expected, ok := nested.(string); ok && key == string(ParamEqualToJson) {
+				var parsed interface{}
+				if json.Unmarshal([]byte(expected), &parsed) == nil {
+					value[key] = parsed
+					continue
+				}
+			}
+			value[key] = normalizeJSONValue(nested)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = normalizeJSONValue(value[i])
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,38 @@
+package wiremock
+
+import "testing"
+
+func TestStubRule_Equal(t *testing.T) {
+	build := func() *StubRule {
+		return Post(URLPathEqualTo("/orders")).
+			WithHeader("Accept", EqualTo("application/json")).
+			WithHeader("X-Trace", Matching("[a-z]+")).
+			WithBodyPattern(EqualToJson(`{"id": 1, "items": ["a"]}`)).
+			WillReturn("created", map[string]string{"Location": "/orders/1"}, 201)
+	}
+
+	a, b := build(), build()
+	if a.UUID() == b.UUID() || !a.Equal(b) {
+		t.Error("expected stubs differing only in generated ids to be equal")
+	}
+
+	reformatted := Post(URLPathEqualTo("/orders")).
+		WithHeader("X-Trace", Matching("[a-z]+")).
+		WithHeader("Accept", EqualTo("application/json")).
+		WithBodyPattern(EqualToJson(`{"items":["a"],"id":1}`)).
+		WillReturn("created", map[string]string{"Location": "/orders/1"}, 201)
+	if !a.Equal(reformatted) || !a.Request().Equal(reformatted.Request()) {
+		t.Error("expected stubs differing in map order and JSON formatting to be equal")
+	}
+
+	for name, other := range map[string]*StubRule{
+		"status":   build().WillReturn("created", map[string]string{"Location": "/orders/1"}, 200),
+		"priority": build().AtPriority(1),
+		"body":     Post(URLPathEqualTo("/orders")).WithBodyPattern(EqualToJson(`{"id": 2}`)),
+		"nil":      nil,
+	} {
+		if a.Equal(other) {
+			t.Errorf("%s: expected stubs to differ", name)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package wiremock
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer is in-memory imitation of the WireMock admin mappings API.
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	mappings map[string]json.RawMessage
+	order    []string
+	settings json.RawMessage
+	requests []json.RawMessage
+	queries  []url.Values
+	// counts are answers of the requests count API by request pattern JSON
+	counts map[string]int64
+	// found are answers of the find requests API by request pattern JSON
+	found map[string][]json.RawMessage
+	// nearMisses are answers of the near misses of request and of request pattern APIs
+	nearMisses []json.RawMessage
+	// gzipped is count of the gzip encoded mappings requests
+	gzipped int
+	files   map[string][]byte
+	caCert  []byte
+	// recording is the spec of the started recording, recorded are the mappings returned when it stops
+	recording json.RawMessage
+	recorded  []json.RawMessage
+	// journalDisabled imitates the server started with --no-request-journal
+	journalDisabled bool
+	// version is answered by the version API, which is missing when empty, like in WireMock 2
+	version string
+	// scenarioStates are the states set by the scenario state API
+	scenarioStates map[string]string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	f := &fakeServer{mappings: map[string]json.RawMessage{}, settings: json.RawMessage(`{}`)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+func (f *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminMappingsURN):
+		f.serveMappings(w, r)
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminFilesURN+"/"):
+		f.serveFiles(w, r)
+	case r.URL.Path == "/"+wiremockAdminCACertURN && f.caCert != nil:
+		_, _ = w.Write(f.caCert)
+	case r.URL.Path == "/"+wiremockAdminRecordingsURN+"/start" && r.Method == http.MethodPost:
+		f.recording, _ = io.ReadAll(r.Body)
+	case r.URL.Path == "/"+wiremockAdminRecordingsURN+"/stop" && r.Method == http.MethodPost && f.recording != nil:
+		f.recording = nil
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"mappings": f.recorded})
+	case r.URL.Path == "/"+wiremockAdminURN+"/version" && f.version != "":
+		_ = json.NewEncoder(w).Encode(map[string]string{"version": f.version})
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminURN+"/scenarios/") && strings.HasSuffix(r.URL.Path, "/state") && r.Method == http.MethodPut:
+		f.serveScenarioState(w, r)
+	case r.URL.Path == "/"+wiremockAdminSettingsURN:
+		f.serveSettings(w, r)
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests" && r.Method == http.MethodDelete:
+		f.requests = nil
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests" && r.Method == http.MethodGet:
+		f.queries = append(f.queries, r.URL.Query())
+		requests := f.requests
+		if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit < len(requests) {
+			requests = requests[:limit]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"requests":               requests,
+			"meta":                   map[string]int{"total": len(f.requests)},
+			"requestJournalDisabled": f.journalDisabled,
+		})
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests/count" && r.Method == http.MethodPost:
+		pattern, _ := io.ReadAll(r.Body)
+		if f.journalDisabled {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"count": -1, "requestJournalDisabled": true})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int64{"count": f.counts[string(pattern)]})
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests/find" && r.Method == http.MethodPost:
+		pattern, _ := io.ReadAll(r.Body)
+		requests := f.found[string(pattern)]
+		if requests == nil {
+			requests = []json.RawMessage{}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"requests": requests, "requestJournalDisabled": f.journalDisabled})
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminURN+"/near-misses/request") && r.Method == http.MethodPost:
+		nearMisses := f.nearMisses
+		if nearMisses == nil {
+			nearMisses = []json.RawMessage{}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"nearMisses": nearMisses})
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests/unmatched" && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"requests": []json.RawMessage{}, "requestJournalDisabled": f.journalDisabled})
+	case r.URL.Path == "/"+wiremockAdminURN+"/requests/unmatched/near-misses" && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"nearMisses": []json.RawMessage{}, "requestJournalDisabled": f.journalDisabled})
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminURN+"/requests/") && r.Method == http.MethodGet:
+		f.serveRequest(w, strings.TrimPrefix(r.URL.Path, "/"+wiremockAdminURN+"/requests/"))
+	case strings.HasPrefix(r.URL.Path, "/"+wiremockAdminURN+"/requests/") && r.Method == http.MethodDelete:
+		f.removeRequest(w, strings.TrimPrefix(r.URL.Path, "/"+wiremockAdminURN+"/requests/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeServer) serveRequest(w http.ResponseWriter, id string) {
+	for _, raw := range f.requests {
+		var event struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &event); err == nil && event.ID == id {
+			_, _ = w.Write(raw)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (f *fakeServer) removeRequest(w http.ResponseWriter, id string) {
+	for i, raw := range f.requests {
+		var event struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &event); err == nil && event.ID == id {
+			f.requests = append(f.requests[:i], f.requests[i+1:]...)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (f *fakeServer) serveFiles(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/"+wiremockAdminFilesURN+"/")
+	switch r.Method {
+	case http.MethodPut:
+		if f.files == nil {
+			f.files = map[string][]byte{}
+		}
+		f.files[name], _ = io.ReadAll(r.Body)
+	case http.MethodDelete:
+		delete(f.files, name)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeServer) serveScenarioState(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"+wiremockAdminURN+"/scenarios/"), "/state")
+	for _, raw := range f.mappings {
+		var mapping struct {
+			ScenarioName string `json:"scenarioName"`
+		}
+		if err := json.Unmarshal(raw, &mapping); err == nil && mapping.ScenarioName == name {
+			var state struct {
+				State string `json:"state"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&state)
+			if f.scenarioStates == nil {
+				f.scenarioStates = map[string]string{}
+			}
+			f.scenarioStates[name] = state.State
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (f *fakeServer) serveSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]json.RawMessage{"settings": f.settings})
+	case http.MethodPost:
+		f.settings, _ = io.ReadAll(r.Body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeServer) serveMappings(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+wiremockAdminMappingsURN), "/")
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reader = gzipReader
+		f.gzipped++
+	}
+	body, _ := io.ReadAll(reader)
+
+	switch {
+	case r.Method == http.MethodPost && id == "import":
+		var imported struct {
+			Mappings []json.RawMessage `json:"mappings"`
+		}
+		if err := json.Unmarshal(body, &imported); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		for _, raw := range imported.Mappings {
+			var mapping struct {
+				ID string `json:"id"`
+			}
+			_ = json.Unmarshal(raw, &mapping)
+			if _, ok := f.mappings[mapping.ID]; !ok {
+				f.order = append(f.order, mapping.ID)
+			}
+			f.mappings[mapping.ID] = raw
+		}
+	case r.Method == http.MethodPost && id == "":
+		var mapping struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &mapping); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if _, ok := f.mappings[mapping.ID]; !ok {
+			f.order = append(f.order, mapping.ID)
+		}
+		f.mappings[mapping.ID] = body
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	case r.Method == http.MethodPut && id != "":
+		if _, ok := f.mappings[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.mappings[id] = body
+		_, _ = w.Write(body)
+	case r.Method == http.MethodGet && id == "":
+		ids := f.order
+		if offset, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && offset < len(ids) {
+			ids = ids[offset:]
+		} else if err == nil {
+			ids = nil
+		}
+		if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit < len(ids) {
+			ids = ids[:limit]
+		}
+		mappings := make([]json.RawMessage, len(ids))
+		for i, id := range ids {
+			mappings[i] = f.mappings[id]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"mappings": mappings,
+			"meta":     map[string]int{"total": len(f.order)},
+		})
+	case r.Method == http.MethodGet && id != "":
+		mapping, ok := f.mappings[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(mapping)
+	case r.Method == http.MethodDelete && id != "":
+		if _, ok := f.mappings[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.remove(id)
+	case r.Method == http.MethodDelete:
+		f.mappings = map[string]json.RawMessage{}
+		f.order = nil
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeServer) remove(id string) {
+	delete(f.mappings, id)
+	for i, orderID := range f.order {
+		if orderID == id {
+			f.order = append(f.order[:i], f.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (f *fakeServer) mappingCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.mappings)
+}
+
+// logServeEvent adds serve event to the head of the journal, the way WireMock orders it.
+func (f *fakeServer) logServeEvent(t *testing.T, event interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("serve event json.Marshal error: %v", err)
+	}
+	f.requests = append([]json.RawMessage{raw}, f.requests...)
+}
+
+// setCount sets answer of the requests count API for criteria.
+func (f *fakeServer) setCount(t *testing.T, criteria RequestCriteria, count int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pattern, err := criteria.Criteria().MarshalJSON()
+	if err != nil {
+		t.Fatalf("request json.Marshal error: %v", err)
+	}
+	if f.counts == nil {
+		f.counts = map[string]int64{}
+	}
+	f.counts[string(pattern)] = count
+}
+
+// setFound sets answer of the find requests API for criteria.
+func (f *fakeServer) setFound(t *testing.T, criteria RequestCriteria, requests ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pattern, err := criteria.Criteria().MarshalJSON()
+	if err != nil {
+		t.Fatalf("request json.Marshal error: %v", err)
+	}
+	if f.found == nil {
+		f.found = map[string][]json.RawMessage{}
+	}
+	for _, request := range requests {
+		raw, err := json.Marshal(request)
+		if err != nil {
+			t.Fatalf("logged request json.Marshal error: %v", err)
+		}
+		f.found[string(pattern)] = append(f.found[string(pattern)], raw)
+	}
+}
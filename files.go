@@ -0,0 +1,95 @@
+package wiremock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const wiremockAdminFilesURN = "__admin/files"
+
+// WillReturnFileBody sets response with the body file of content and returns *StubRule.
+// The client uploads content to the server files under name when registering the stub,
+// so big fixtures stay out of the stub JSON.
+func (s *StubRule) WillReturnFileBody(name string, content []byte, headers map[string]string, status int64) *StubRule {
+	s.WillReturnFileContent(name, headers, status)
+	s.response.bodyFile = content
+	return s
+}
+
+// UploadFile creates or replaces the server file used by bodyFileName of responses.
+func (c *Client) UploadFile(name string, content []byte) error {
+	return c.uploadFile(context.Background(), name, content)
+}
+
+func (c *Client) uploadFile(ctx context.Context, name string, content []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.fileURL(name), bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("upload file %s: build request error: %s", name, err.Error())
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload file %s: request error: %s", name, err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusNoContent {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("upload file %s: read response error: %s", name, err.Error())
+		}
+
+		return fmt.Errorf("upload file %s: bad response status: %d, response: %s", name, res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// DeleteFile deletes the server file.
+func (c *Client) DeleteFile(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.fileURL(name), nil)
+	if err != nil {
+		return fmt.Errorf("delete file %s: build request error: %s", name, err.Error())
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete file %s: request error: %s", name, err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("delete file %s: read response error: %s", name, err.Error())
+		}
+
+		return fmt.Errorf("delete file %s: bad response status: %d, response: %s", name, res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// fileURL gives the files API url of name, which may contain directories.
+func (c *Client) fileURL(name string) string {
+	segments := strings.Split(name, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+
+	return fmt.Sprintf("%s/%s/%s", c.url, wiremockAdminFilesURN, strings.Join(segments, "/"))
+}
+
+// uploadBodyFile uploads the body file set by WillReturnFileBody.
+func (c *Client) uploadBodyFile(ctx context.Context, stubRule *StubRule) error {
+	if stubRule.response.bodyFile == nil || stubRule.response.bodyFileName == nil {
+		return nil
+	}
+
+	return c.uploadFile(ctx, *stubRule.response.bodyFileName, stubRule.response.bodyFile)
+}
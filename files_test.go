@@ -0,0 +1,37 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStubRule_WillReturnFileBody(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	content := []byte(`[{"id": 1}]`)
+	stubRule := Get(URLPathEqualTo("/orders")).
+		WillReturnFileBody("orders/list.json", content, map[string]string{"Content-Type": "application/json"}, 200)
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"bodyFileName":"orders/list.json"`) || strings.Contains(string(raw), `"id": 1`) {
+		t.Errorf("expected stub to reference the file only; got %s", raw)
+	}
+
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+	if string(server.files["orders/list.json"]) != string(content) || server.mappingCount() != 1 {
+		t.Errorf("expected file uploaded with the stub; got %q", server.files["orders/list.json"])
+	}
+
+	if err := client.DeleteFile("orders/list.json"); err != nil {
+		t.Fatalf("DeleteFile error: %v", err)
+	}
+	if _, ok := server.files["orders/list.json"]; ok {
+		t.Error("expected file to be deleted")
+	}
+}
@@ -0,0 +1,112 @@
+// Package fixtures provides ready-made stub templates for common API shapes:
+// problem+json errors, rate limiting and paginated lists.
+//
+// Every template returns a *wiremock.StubRule, so it can be refined before registration:
+//
+//	client.StubFor(fixtures.NotFound("/orders/42").AtPriority(1))
+package fixtures
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// ContentTypeProblemJSON is the media type of RFC 7807 problem details.
+const ContentTypeProblemJSON = wiremock.ContentTypeProblemJSON
+
+// Problem returns stub answering any request to path with RFC 7807 problem details of status.
+func Problem(path string, status int64, detail string) *wiremock.StubRule {
+	return wiremock.NewStubRule(wiremock.MethodAny, wiremock.URLPathEqualTo(path)).
+		WillReturnProblem(wiremock.ProblemDetails(status, detail), status)
+}
+
+// NotFound returns stub answering path with 404 problem details.
+func NotFound(path string) *wiremock.StubRule {
+	return Problem(path, http.StatusNotFound, fmt.Sprintf("%s was not found", path))
+}
+
+// InternalServerError returns stub answering path with 500 problem details.
+func InternalServerError(path string) *wiremock.StubRule {
+	return Problem(path, http.StatusInternalServerError, "unexpected error")
+}
+
+// ServiceUnavailable returns stub answering path with 503 problem details and Retry-After header.
+func ServiceUnavailable(path string, retryAfter time.Duration) *wiremock.StubRule {
+	stubRule := Problem(path, http.StatusServiceUnavailable, "service is temporarily unavailable")
+	stubRule.Response().Headers()["Retry-After"] = retryAfterSeconds(retryAfter)
+	return stubRule
+}
+
+// RateLimited returns stub answering path with 429 problem details and Retry-After header.
+func RateLimited(path string, retryAfter time.Duration) *wiremock.StubRule {
+	stubRule := Problem(path, http.StatusTooManyRequests, "rate limit exceeded")
+	stubRule.Response().Headers()["Retry-After"] = retryAfterSeconds(retryAfter)
+	return stubRule
+}
+
+// PaginatedList returns stubs serving items by pageSize on GET path?page=N.
+// Pages are numbered from 1, the first page is also served without the page parameter.
+// Every page body is {"items": [...], "page": N, "pageSize": S, "totalItems": T, "totalPages": P}
+// and every page except the last has Link header to the next one.
+func PaginatedList(path string, items []interface{}, pageSize int) []*wiremock.StubRule {
+	if pageSize <= 0 {
+		pageSize = len(items)
+	}
+
+	totalPages := 1
+	if len(items) > 0 && pageSize > 0 {
+		totalPages = int(math.Ceil(float64(len(items)) / float64(pageSize)))
+	}
+
+	stubs := make([]*wiremock.StubRule, 0, totalPages+1)
+	for page := 1; page <= totalPages; page++ {
+		from := (page - 1) * pageSize
+		to := from + pageSize
+		if to > len(items) {
+			to = len(items)
+		}
+
+		pageItems := items[from:to]
+		if pageItems == nil {
+			pageItems = []interface{}{}
+		}
+
+		headers := map[string]string{"Content-Type": "application/json"}
+		if page < totalPages {
+			headers["Link"] = fmt.Sprintf(`<%s?page=%d>; rel="next"`, path, page+1)
+		}
+
+		body := map[string]interface{}{
+			"items":      pageItems,
+			"page":       page,
+			"pageSize":   pageSize,
+			"totalItems": len(items),
+			"totalPages": totalPages,
+		}
+
+		stubs = append(stubs, wiremock.Get(wiremock.URLPathEqualTo(path)).
+			WithQueryParam("page", wiremock.EqualTo(strconv.Itoa(page))).
+			WillReturnJSON(body, headers, http.StatusOK))
+
+		if page == 1 {
+			firstHeaders := make(map[string]string, len(headers))
+			for key, value := range headers {
+				firstHeaders[key] = value
+			}
+			stubs = append(stubs, wiremock.Get(wiremock.URLPathEqualTo(path)).
+				WithQueryParam("page", wiremock.Absent()).
+				WillReturnJSON(body, firstHeaders, http.StatusOK))
+		}
+	}
+
+	return stubs
+}
+
+func retryAfterSeconds(retryAfter time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10)
+}
@@ -0,0 +1,42 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	stubRule := RateLimited("/orders", 1500*time.Millisecond)
+
+	response := stubRule.Response()
+	if response.Status() != http.StatusTooManyRequests || response.Headers()["Retry-After"] != "2" ||
+		response.Headers()["Content-Type"] != ContentTypeProblemJSON {
+		t.Errorf("unexpected rate limited stub %s", stubRule)
+	}
+}
+
+func TestPaginatedList(t *testing.T) {
+	stubs := PaginatedList("/orders", []interface{}{1, 2, 3, 4, 5}, 2)
+	if len(stubs) != 4 {
+		t.Fatalf("expected 3 pages and the default page; got %d stubs", len(stubs))
+	}
+
+	raw, err := json.Marshal(stubs[3])
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+	for _, expected := range []string{`"page":{"equalTo":"3"}`, `"items":[5]`, `"totalPages":3`} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected last page to contain %s; got %s", expected, raw)
+		}
+	}
+	if _, ok := stubs[3].Response().Headers()["Link"]; ok {
+		t.Errorf("expected no next link on the last page")
+	}
+	if stubs[1].Response().Headers()["Link"] != `</orders?page=2>; rel="next"` {
+		t.Errorf("expected next link on the default page; got %v", stubs[1].Response().Headers())
+	}
+}
@@ -0,0 +1,92 @@
+package wiremock
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// TestingTB is the part of testing.TB the child client of the test uses.
+type TestingTB interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...interface{})
+}
+
+// stubTracker keeps ids of the stubs registered by the client, nil tracks nothing.
+type stubTracker struct {
+	mu  sync.Mutex
+	ids map[string]int
+	// next orders ids by registration
+	next int
+}
+
+func (t *stubTracker) track(id string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.ids[id]; !ok {
+		t.ids[id] = t.next
+		t.next++
+	}
+}
+
+func (t *stubTracker) untrack(id string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.ids, id)
+}
+
+// tracked gives ids of the tracked stubs from the most recently registered one.
+func (t *stubTracker) tracked() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.ids))
+	for id := range t.ids {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return t.ids[ids[i]] > t.ids[ids[j]]
+	})
+
+	return ids
+}
+
+// ForTest returns the child client of the test. It shares the server and the options of the client
+// and deletes the stubs it registers when the test and its subtests finish, leaving stubs of other tests alone,
+// so parallel tests can share one server:
+//
+//	func TestOrders(t *testing.T) {
+//		t.Parallel()
+//		client := sharedClient.ForTest(t)
+//		...
+//	}
+//
+// The Client is safe for concurrent use, so is the child client.
+// Stubs are told apart by ids only, so parallel tests should not register stubs matching the same requests.
+func (c *Client) ForTest(t TestingTB) *Client {
+	t.Helper()
+
+	child := *c
+	child.tracker = &stubTracker{ids: map[string]int{}}
+
+	t.Cleanup(func() {
+		for _, id := range child.tracker.tracked() {
+			if err := child.DeleteStubByID(id); err != nil && !errors.Is(err, ErrStubNotFound) {
+				t.Errorf("wiremock: delete stub %s of the test: %v", id, err)
+			}
+		}
+	})
+
+	return &child
+}
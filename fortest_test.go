@@ -0,0 +1,54 @@
+package wiremock
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestClient_ForTest(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	shared := Get(URLPathEqualTo("/shared"))
+	if err := client.StubFor(shared); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			i := i
+			t.Run(fmt.Sprintf("parallel %d", i), func(t *testing.T) {
+				t.Parallel()
+				child := client.ForTest(t)
+
+				var wg sync.WaitGroup
+				for j := 0; j < 3; j++ {
+					wg.Add(1)
+					go func(j int) {
+						defer wg.Done()
+						if err := child.StubFor(Get(URLPathEqualTo(fmt.Sprintf("/orders/%d/%d", i, j)))); err != nil {
+							t.Errorf("StubFor error: %v", err)
+						}
+					}(j)
+				}
+				wg.Wait()
+
+				deleted := Get(URLPathEqualTo(fmt.Sprintf("/deleted/%d", i)))
+				if err := child.ImportStubs([]*StubRule{deleted}); err != nil {
+					t.Fatalf("ImportStubs error: %v", err)
+				}
+				if err := child.DeleteStub(deleted); err != nil {
+					t.Fatalf("DeleteStub error: %v", err)
+				}
+			})
+		}
+	})
+
+	if count := server.mappingCount(); count != 1 {
+		t.Errorf("expected only the shared stub left; got %d stubs", count)
+	}
+	if _, err := client.GetStub(shared.UUID()); err != nil {
+		t.Errorf("expected shared stub kept; got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package wiremock
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// ImportStubs creates stub mappings in one request, overwriting the registered ones with the same ids.
+// It is much faster than StubFor for big fixture sets, see also WithGzipRequests.
+func (c *Client) ImportStubs(stubs []*StubRule) error {
+	defer c.cache.invalidate()
+
+	mappings := make([]interface{}, len(stubs))
+	for i, stubRule := range stubs {
+		if err := stubRule.Validate(); err != nil {
+			return fmt.Errorf("import stubs: invalid stub %s: %s", stubRule.UUID(), err.Error())
+		}
+		if err := c.checkSupported(stubRule); err != nil {
+			return fmt.Errorf("import stubs: stub %s: %w", stubRule.UUID(), err)
+		}
+		if err := c.uploadBodyFile(context.Background(), stubRule); err != nil {
+			return fmt.Errorf("import stubs: %s", err.Error())
+		}
+		mappings[i] = c.stubJSON(stubRule)
+	}
+
+	requestBody, err := c.codec.Marshal(map[string]interface{}{
+		"mappings": mappings,
+		"importOptions": map[string]interface{}{
+			"duplicatePolicy":      "OVERWRITE",
+			"deleteAllNotInImport": false,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("import stubs: build error: %s", err.Error())
+	}
+
+	req, err := c.newBulkRequest(http.MethodPost, fmt.Sprintf("%s/%s/import", c.url, wiremockAdminMappingsURN), requestBody)
+	if err != nil {
+		return fmt.Errorf("import stubs: build request error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("import stubs: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("import stubs: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("import stubs: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	for _, stubRule := range stubs {
+		c.tracker.track(stubRule.UUID())
+	}
+
+	return nil
+}
+
+// newBulkRequest builds the JSON request, compressing the body set by WithGzipRequests.
+func (c *Client) newBulkRequest(method, url string, body []byte) (*http.Request, error) {
+	gzipped := c.gzipMinSize > 0 && len(body) >= c.gzipMinSize
+	if gzipped {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(body); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		body = compressed.Bytes()
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	return req, nil
+}
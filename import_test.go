@@ -0,0 +1,46 @@
+package wiremock
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClient_ImportStubs(t *testing.T) {
+	server := newFakeServer(t)
+
+	stubs := make([]*StubRule, 50)
+	for i := range stubs {
+		stubs[i] = Get(URLPathEqualTo(fmt.Sprintf("/orders/%d", i))).WillReturn("{}", nil, 200)
+	}
+
+	if err := NewClient(server.URL).ImportStubs(stubs[:1]); err != nil {
+		t.Fatalf("ImportStubs error: %v", err)
+	}
+	if server.gzipped != 0 || server.mappingCount() != 1 {
+		t.Errorf("expected plain import without compression; got %d gzipped, %d mappings", server.gzipped, server.mappingCount())
+	}
+
+	client := NewClient(server.URL, WithGzipRequests(1024))
+	if err := client.ImportStubs(stubs[:1]); err != nil {
+		t.Fatalf("ImportStubs error: %v", err)
+	}
+	if server.gzipped != 0 {
+		t.Error("expected small import not to be compressed")
+	}
+
+	if err := client.ImportStubs(stubs); err != nil {
+		t.Fatalf("ImportStubs error: %v", err)
+	}
+	if server.gzipped != 1 || server.mappingCount() != len(stubs) {
+		t.Errorf("expected compressed import of all stubs; got %d gzipped, %d mappings", server.gzipped, server.mappingCount())
+	}
+
+	registered, err := client.GetStub(stubs[49].UUID())
+	if err != nil || !registered.Equal(stubs[49]) {
+		t.Errorf("expected imported stub equal to %s; got %v, %v", stubs[49], registered, err)
+	}
+
+	if err := client.ImportStubs([]*StubRule{Get(URLPathMatching("(a"))}); err == nil {
+		t.Error("expected invalid stub error")
+	}
+}
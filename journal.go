@@ -0,0 +1,454 @@
+package wiremock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const journalDateFormat = "2006-01-02T15:04:05.000Z"
+
+// A LoggedRequest is the http request received by WireMock and recorded in the request journal.
+type LoggedRequest struct {
+	ID                  string
+	URL                 string
+	AbsoluteURL         string
+	Method              string
+	ClientIP            string
+	Headers             map[string]string
+	Cookies             map[string]string
+	QueryParams         map[string][]string
+	Body                []byte
+	BrowserProxyRequest bool
+	LoggedDate          time.Time
+
+	// multiHeaders keep all values of the headers sent several times, which Headers join
+	multiHeaders map[string][]string
+	// multiCookies keep all values of the cookies sent several times, which Cookies join
+	multiCookies map[string][]string
+}
+
+// UnmarshalJSON fills LoggedRequest from WireMock JSON.
+func (r *LoggedRequest) UnmarshalJSON(data []byte) error {
+	jsonRequest := struct {
+		ID          string                     `json:"id"`
+		URL         string                     `json:"url"`
+		AbsoluteURL string                     `json:"absoluteUrl"`
+		Method      string                     `json:"method"`
+		ClientIP    string                     `json:"clientIp"`
+		Headers     map[string]json.RawMessage `json:"headers"`
+		Cookies     map[string]json.RawMessage `json:"cookies"`
+		QueryParams map[string]struct {
+			Values []string `json:"values"`
+		} `json:"queryParams"`
+		Body                string `json:"body"`
+		BodyAsBase64        string `json:"bodyAsBase64"`
+		BrowserProxyRequest bool   `json:"browserProxyRequest"`
+		LoggedDate          int64  `json:"loggedDate"`
+	}{}
+	if err := json.Unmarshal(data, &jsonRequest); err != nil {
+		return err
+	}
+
+	*r = LoggedRequest{
+		ID:                  jsonRequest.ID,
+		URL:                 jsonRequest.URL,
+		AbsoluteURL:         jsonRequest.AbsoluteURL,
+		Method:              jsonRequest.Method,
+		ClientIP:            jsonRequest.ClientIP,
+		Body:                []byte(jsonRequest.Body),
+		BrowserProxyRequest: jsonRequest.BrowserProxyRequest,
+		LoggedDate:          time.UnixMilli(jsonRequest.LoggedDate).UTC(),
+	}
+
+	if jsonRequest.BodyAsBase64 != "" {
+		body, err := base64.StdEncoding.DecodeString(jsonRequest.BodyAsBase64)
+		if err != nil {
+			return fmt.Errorf("decode bodyAsBase64: %s", err.Error())
+		}
+		r.Body = body
+	}
+
+	var err error
+	if r.multiHeaders, err = multipleValues(jsonRequest.Headers); err != nil {
+		return fmt.Errorf("decode headers: %s", err.Error())
+	}
+	r.Headers = joinValues(r.multiHeaders)
+	if r.multiCookies, err = multipleValues(jsonRequest.Cookies); err != nil {
+		return fmt.Errorf("decode cookies: %s", err.Error())
+	}
+	r.Cookies = joinValues(r.multiCookies)
+
+	if len(jsonRequest.QueryParams) > 0 {
+		r.QueryParams = make(map[string][]string, len(jsonRequest.QueryParams))
+		for key, param := range jsonRequest.QueryParams {
+			r.QueryParams[key] = param.Values
+		}
+	} else if i := strings.IndexByte(r.URL, '?'); i >= 0 {
+		query, err := url.ParseQuery(r.URL[i+1:])
+		if err == nil && len(query) > 0 {
+			r.QueryParams = query
+		}
+	}
+
+	return nil
+}
+
+// BodyAsJSON decodes the JSON body of the request into v.
+func (r *LoggedRequest) BodyAsJSON(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("decode body: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Header gives the value of the header, matching its name case-insensitively, empty when absent.
+// Multiple values are joined with comma.
+func (r *LoggedRequest) Header(name string) string {
+	if value := r.header(name); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// HeaderValues gives all values of the header, matching its name case-insensitively, nil when absent.
+// The header sent several times, e.g. Accept-Encoding, has as many values.
+func (r *LoggedRequest) HeaderValues(name string) []string {
+	return append([]string(nil), r.headerValues(name)...)
+}
+
+// Cookie gives the value of the cookie, empty when absent.
+// Multiple values are joined with comma.
+func (r *LoggedRequest) Cookie(name string) string {
+	return r.Cookies[name]
+}
+
+// QueryParam gives the first value of the query parameter, empty when absent.
+func (r *LoggedRequest) QueryParam(name string) string {
+	if values := r.queryValues(name); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// joinedValues decodes WireMock single or multi value map, joining multiple values with comma.
+func joinedValues(raw map[string]json.RawMessage) (map[string]string, error) {
+	values, err := multipleValues(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return joinValues(values), nil
+}
+
+// multipleValues decodes WireMock single or multi value map.
+func multipleValues(raw map[string]json.RawMessage) (map[string][]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string][]string, len(raw))
+	for key, rawValue := range raw {
+		var value string
+		if err := json.Unmarshal(rawValue, &value); err == nil {
+			result[key] = []string{value}
+			continue
+		}
+
+		var values []string
+		if err := json.Unmarshal(rawValue, &values); err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err.Error())
+		}
+		result[key] = values
+	}
+
+	return result, nil
+}
+
+func joinValues(values map[string][]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key] = strings.Join(value, ", ")
+	}
+
+	return result
+}
+
+// A LoggedResponse is the http response sent by WireMock and recorded in the request journal.
+type LoggedResponse struct {
+	Status  int64
+	Headers map[string]string
+	Body    []byte
+}
+
+// UnmarshalJSON fills LoggedResponse from WireMock JSON.
+func (r *LoggedResponse) UnmarshalJSON(data []byte) error {
+	jsonResponse := struct {
+		Status       int64                      `json:"status"`
+		Headers      map[string]json.RawMessage `json:"headers"`
+		Body         string                     `json:"body"`
+		BodyAsBase64 string                     `json:"bodyAsBase64"`
+	}{}
+	if err := json.Unmarshal(data, &jsonResponse); err != nil {
+		return err
+	}
+
+	*r = LoggedResponse{
+		Status: jsonResponse.Status,
+		Body:   []byte(jsonResponse.Body),
+	}
+
+	if jsonResponse.BodyAsBase64 != "" {
+		body, err := base64.StdEncoding.DecodeString(jsonResponse.BodyAsBase64)
+		if err != nil {
+			return fmt.Errorf("decode bodyAsBase64: %s", err.Error())
+		}
+		r.Body = body
+	}
+
+	var err error
+	if r.Headers, err = joinedValues(jsonResponse.Headers); err != nil {
+		return fmt.Errorf("decode headers: %s", err.Error())
+	}
+
+	return nil
+}
+
+// A ServeEventTiming is the time WireMock spent serving the request.
+type ServeEventTiming struct {
+	// AddedDelay is the delay injected by the stub or the global settings.
+	AddedDelay time.Duration
+	// ProcessTime is the time of matching the request and building the response.
+	ProcessTime time.Duration
+	// ResponseSendTime is the time of sending the response.
+	ResponseSendTime time.Duration
+	// TotalTime is the time from receiving the request to sending the response, including AddedDelay.
+	TotalTime time.Duration
+}
+
+// UnmarshalJSON fills ServeEventTiming from WireMock JSON in milliseconds.
+func (t *ServeEventTiming) UnmarshalJSON(data []byte) error {
+	jsonTiming := struct {
+		AddedDelay       int64 `json:"addedDelay"`
+		ProcessTime      int64 `json:"processTime"`
+		ResponseSendTime int64 `json:"responseSendTime"`
+		TotalTime        int64 `json:"totalTime"`
+	}{}
+	if err := json.Unmarshal(data, &jsonTiming); err != nil {
+		return err
+	}
+
+	*t = ServeEventTiming{
+		AddedDelay:       time.Duration(jsonTiming.AddedDelay) * time.Millisecond,
+		ProcessTime:      time.Duration(jsonTiming.ProcessTime) * time.Millisecond,
+		ResponseSendTime: time.Duration(jsonTiming.ResponseSendTime) * time.Millisecond,
+		TotalTime:        time.Duration(jsonTiming.TotalTime) * time.Millisecond,
+	}
+
+	return nil
+}
+
+// A ServeEvent is the request journal entry: received request, matched stub and sent response.
+type ServeEvent struct {
+	ID             string           `json:"id"`
+	Request        LoggedRequest    `json:"request"`
+	Response       LoggedResponse   `json:"response"`
+	WasMatched     bool             `json:"wasMatched"`
+	RawStubMapping json.RawMessage  `json:"stubMapping"`
+	Timing         ServeEventTiming `json:"timing"`
+}
+
+// StubMapping decodes the stub matched by the request.
+func (e *ServeEvent) StubMapping() (*StubRule, error) {
+	return decodeStubMapping(e.RawStubMapping)
+}
+
+// StubID gives the id of the stub matched by the request.
+func (e *ServeEvent) StubID() string {
+	var stubMapping struct {
+		ID   string `json:"id"`
+		UUID string `json:"uuid"`
+	}
+	if err := json.Unmarshal(e.RawStubMapping, &stubMapping); err != nil {
+		return ""
+	}
+	if stubMapping.ID == "" {
+		return stubMapping.UUID
+	}
+
+	return stubMapping.ID
+}
+
+// stubHasTag reports whether the stub matched by the request is tagged with tag, see StubRule.WithTag.
+func (e *ServeEvent) stubHasTag(tag string) bool {
+	var stubMapping StubRule
+	if err := json.Unmarshal(e.RawStubMapping, &stubMapping); err != nil {
+		return false
+	}
+
+	return stubMapping.HasTag(tag)
+}
+
+// A ServeEventQuery filters serve events retrieved from the request journal.
+type ServeEventQuery struct {
+	since     time.Time
+	limit     int
+	unmatched bool
+	stubID    string
+	tag       string
+}
+
+// NewServeEventQuery returns query matching all serve events.
+func NewServeEventQuery() *ServeEventQuery {
+	return &ServeEventQuery{}
+}
+
+// WithSince keeps only events logged after since and returns *ServeEventQuery
+func (q *ServeEventQuery) WithSince(since time.Time) *ServeEventQuery {
+	q.since = since
+	return q
+}
+
+// WithLimit keeps at most limit most recent events and returns *ServeEventQuery.
+// With the filters of events the limit is applied locally, after the events are filtered.
+func (q *ServeEventQuery) WithLimit(limit int) *ServeEventQuery {
+	q.limit = limit
+	return q
+}
+
+// WithUnmatched keeps only events of requests not matched by any stub and returns *ServeEventQuery
+func (q *ServeEventQuery) WithUnmatched() *ServeEventQuery {
+	q.unmatched = true
+	return q
+}
+
+// WithStubID keeps only events of requests matched by the stub and returns *ServeEventQuery
+func (q *ServeEventQuery) WithStubID(id string) *ServeEventQuery {
+	q.stubID = id
+	return q
+}
+
+// WithTag keeps only events of requests matched by stubs tagged with tag, see StubRule.WithTag,
+// and returns *ServeEventQuery. The events are filtered locally, by the stubs they record,
+// so the events of the deleted stubs are kept as well.
+func (q *ServeEventQuery) WithTag(tag string) *ServeEventQuery {
+	q.tag = tag
+	return q
+}
+
+// values gives query parameters of the request journal API.
+func (q *ServeEventQuery) values() url.Values {
+	values := url.Values{}
+	if q == nil {
+		return values
+	}
+
+	if !q.since.IsZero() {
+		values.Set("since", q.since.UTC().Format(journalDateFormat))
+	}
+	// the server may ignore the filters, so it must not limit the events before they are filtered locally
+	if q.limit > 0 && !q.filtered() {
+		values.Set("limit", strconv.Itoa(q.limit))
+	}
+	if q.unmatched {
+		values.Set("unmatched", "true")
+	}
+	if q.stubID != "" {
+		values.Set("matchingStub", q.stubID)
+	}
+
+	return values
+}
+
+// filter applies the query to events locally, for servers ignoring some query parameters.
+func (q *ServeEventQuery) filter(events []ServeEvent) []ServeEvent {
+	if q == nil || !q.filtered() {
+		return events
+	}
+
+	filtered := events[:0]
+	for _, event := range events {
+		if q.unmatched && event.WasMatched {
+			continue
+		}
+		if q.stubID != "" && event.StubID() != q.stubID {
+			continue
+		}
+		if q.tag != "" && !event.stubHasTag(q.tag) {
+			continue
+		}
+		filtered = append(filtered, event)
+		if len(filtered) == q.limit {
+			break
+		}
+	}
+
+	return filtered
+}
+
+// filtered reports whether the query has filters applied locally.
+func (q *ServeEventQuery) filtered() bool {
+	return q.unmatched || q.stubID != "" || q.tag != ""
+}
+
+// A MatchResult is the result of matching a request against a stub.
+type MatchResult struct {
+	// Distance is 0 for the exact match and 1 for the total mismatch.
+	Distance float64 `json:"distance"`
+}
+
+// A NearMiss is the stub or the request pattern closest to matching the request.
+type NearMiss struct {
+	Request           LoggedRequest   `json:"request"`
+	RawStubMapping    json.RawMessage `json:"stubMapping"`
+	RawRequestPattern json.RawMessage `json:"requestPattern"`
+	MatchResult       MatchResult     `json:"matchResult"`
+}
+
+// RequestPattern decodes the nearly matched request pattern, either of the stub or of the near misses query.
+func (m *NearMiss) RequestPattern() (*Request, error) {
+	if len(m.RawRequestPattern) == 0 || string(m.RawRequestPattern) == "null" {
+		stubRule, err := m.StubMapping()
+		if err != nil || stubRule == nil {
+			return nil, err
+		}
+
+		return stubRule.Request(), nil
+	}
+
+	var request Request
+	if err := json.Unmarshal(m.RawRequestPattern, &request); err != nil {
+		return nil, fmt.Errorf("decode request pattern: %s", err.Error())
+	}
+
+	return &request, nil
+}
+
+// StubMapping decodes the stub nearly matched by the request.
+func (m *NearMiss) StubMapping() (*StubRule, error) {
+	return decodeStubMapping(m.RawStubMapping)
+}
+
+func decodeStubMapping(raw json.RawMessage) (*StubRule, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var stubRule StubRule
+	if err := json.Unmarshal(raw, &stubRule); err != nil {
+		return nil, fmt.Errorf("decode stub mapping: %s", err.Error())
+	}
+
+	return &stubRule, nil
+}
@@ -0,0 +1,197 @@
+package wiremock
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetServeEvents(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	server.logServeEvent(t, map[string]interface{}{
+		"id": "e1",
+		"request": map[string]interface{}{
+			"url":         "/orders?page=1",
+			"method":      "GET",
+			"headers":     map[string]interface{}{"Accept": "application/json", "Accept-Encoding": []string{"gzip", "br"}},
+			"queryParams": map[string]interface{}{"page": map[string]interface{}{"key": "page", "values": []string{"1"}}},
+			"loggedDate":  1700000000000,
+		},
+		"response":    map[string]interface{}{"status": 200, "body": "[]"},
+		"wasMatched":  true,
+		"stubMapping": map[string]interface{}{"id": "s1", "request": map[string]interface{}{"method": "GET"}},
+	})
+	server.logServeEvent(t, map[string]interface{}{
+		"id":         "e2",
+		"request":    map[string]interface{}{"url": "/missing", "method": "GET", "bodyAsBase64": "aGk="},
+		"response":   map[string]interface{}{"status": 404},
+		"wasMatched": false,
+	})
+
+	since := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	events, err := client.GetServeEvents(NewServeEventQuery().WithSince(since).WithLimit(1).WithStubID("s1"))
+	if err != nil {
+		t.Fatalf("GetServeEvents error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "e1" {
+		t.Fatalf("expected event e1; got %+v", events)
+	}
+
+	query := server.queries[len(server.queries)-1]
+	if query.Get("since") != "2023-11-14T22:13:20.000Z" || query.Has("limit") || query.Get("matchingStub") != "s1" {
+		t.Errorf("unexpected query %v", query)
+	}
+
+	request := events[0].Request
+	if request.Headers["Accept-Encoding"] != "gzip, br" || request.QueryParams["page"][0] != "1" {
+		t.Errorf("unexpected logged request %+v", request)
+	}
+	if !request.LoggedDate.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("unexpected logged date %s", request.LoggedDate)
+	}
+	stubRule, err := events[0].StubMapping()
+	if err != nil || stubRule.UUID() != "s1" {
+		t.Errorf("expected stub s1; got %v, %v", stubRule, err)
+	}
+
+	events, err = client.GetServeEvents(NewServeEventQuery().WithLimit(1))
+	if err != nil {
+		t.Fatalf("GetServeEvents error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "e2" || server.queries[len(server.queries)-1].Get("limit") != "1" {
+		t.Errorf("expected the most recent event e2 limited by the server; got %+v", events)
+	}
+
+	events, err = client.GetServeEvents(NewServeEventQuery().WithUnmatched())
+	if err != nil {
+		t.Fatalf("GetServeEvents error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "e2" || string(events[0].Request.Body) != "hi" {
+		t.Errorf("expected unmatched event e2; got %+v", events)
+	}
+}
+
+func TestClient_GetRequest(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	server.logServeEvent(t, map[string]interface{}{
+		"id":          "e1",
+		"request":     map[string]interface{}{"url": "/orders", "method": "POST", "body": `{"id": 1}`},
+		"response":    map[string]interface{}{"status": 201, "headers": map[string]string{"Location": "/orders/1"}},
+		"wasMatched":  true,
+		"stubMapping": map[string]interface{}{"id": "s1", "request": map[string]interface{}{"method": "POST", "urlPath": "/orders"}},
+	})
+
+	event, err := client.GetRequest("e1")
+	if err != nil {
+		t.Fatalf("GetRequest error: %v", err)
+	}
+	if event.Request.Method != "POST" || string(event.Request.Body) != `{"id": 1}` || event.Response.Headers["Location"] != "/orders/1" {
+		t.Errorf("unexpected serve event %+v", event)
+	}
+
+	if _, err := client.GetRequest("unknown"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("expected ErrRequestNotFound; got %v", err)
+	}
+}
+
+func TestLoggedRequest_Helpers(t *testing.T) {
+	var request LoggedRequest
+	err := request.UnmarshalJSON([]byte(`{
+		"url": "/orders?status=paid&status=shipped",
+		"method": "POST",
+		"headers": {"Content-Type": "application/json", "Accept-Encoding": ["gzip", "br"]},
+		"cookies": {"session": "s1"},
+		"body": "{\"id\":7,\"items\":[\"book\"]}"
+	}`))
+	if err != nil {
+		t.Fatalf("LoggedRequest UnmarshalJSON error: %v", err)
+	}
+
+	var order struct {
+		ID    int      `json:"id"`
+		Items []string `json:"items"`
+	}
+	if err := request.BodyAsJSON(&order); err != nil {
+		t.Fatalf("BodyAsJSON error: %v", err)
+	}
+	if order.ID != 7 || len(order.Items) != 1 {
+		t.Errorf("unexpected decoded body %+v", order)
+	}
+
+	if value := request.Header("content-type"); value != "application/json" {
+		t.Errorf("expected header matched case-insensitively; got %q", value)
+	}
+	if value := request.Header("Accept-Encoding"); value != "gzip, br" {
+		t.Errorf("expected joined header values; got %q", value)
+	}
+	if values := request.HeaderValues("accept-encoding"); !reflect.DeepEqual(values, []string{"gzip", "br"}) {
+		t.Errorf("expected all header values; got %v", values)
+	}
+	for _, tc := range []struct {
+		matcher  ParamMatcher
+		expected bool
+	}{
+		{matcher: EqualTo("br"), expected: true},
+		{matcher: HavingExactly(EqualTo("br"), EqualTo("gzip")), expected: true},
+		{matcher: HavingExactly(EqualTo("gzip")), expected: false},
+	} {
+		stubRequest := NewRequest("", nil).WithHeader("Accept-Encoding", tc.matcher)
+		if matched, known := stubRequest.Match(&request); matched != tc.expected || !known {
+			t.Errorf("expected Accept-Encoding %v matched %v; got %v, %v", tc.matcher, tc.expected, matched, known)
+		}
+	}
+	if value := request.Header("X-Missing"); value != "" {
+		t.Errorf("expected empty missing header; got %q", value)
+	}
+	if value := request.Cookie("session"); value != "s1" {
+		t.Errorf("expected cookie s1; got %q", value)
+	}
+	if value := request.QueryParam("status"); value != "paid" {
+		t.Errorf("expected first query value; got %q", value)
+	}
+
+	request.Body = []byte("not json")
+	if err := request.BodyAsJSON(&order); err == nil {
+		t.Error("expected error decoding non JSON body")
+	}
+}
+
+func TestClient_DeleteServeEvents(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	for id, stubMapping := range map[string]map[string]interface{}{
+		"e1": {"id": "s1", "request": map[string]interface{}{"method": "GET"}, "metadata": map[string]interface{}{"tags": []string{"checkout"}}},
+		"e2": {"id": "s2", "request": map[string]interface{}{"method": "GET"}},
+		"e3": {"id": "s2", "request": map[string]interface{}{"method": "GET"}},
+	} {
+		server.logServeEvent(t, map[string]interface{}{
+			"id":          id,
+			"request":     map[string]interface{}{"url": "/orders", "method": "GET"},
+			"wasMatched":  true,
+			"stubMapping": stubMapping,
+		})
+	}
+
+	events, err := client.GetServeEvents(NewServeEventQuery().WithTag("checkout"))
+	if err != nil || len(events) != 1 || events[0].ID != "e1" {
+		t.Fatalf("expected tagged event e1; got %+v, %v", events, err)
+	}
+
+	ids, err := client.DeleteServeEvents(NewServeEventQuery().WithStubID("s2"))
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("expected events of stub s2 deleted; got %v, %v", ids, err)
+	}
+	if events, _ := client.GetServeEvents(nil); len(events) != 1 || events[0].ID != "e1" {
+		t.Errorf("expected only event e1 kept; got %+v", events)
+	}
+
+	if err := client.DeleteServeEvent("e2"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("expected ErrRequestNotFound; got %v", err)
+	}
+}
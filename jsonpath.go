@@ -0,0 +1,191 @@
+package wiremock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is the step of JSONPath expression selecting the child by name or by index,
+// any child when wildcard, the descendants as well when recursive.
+type jsonPathStep struct {
+	name      string
+	index     *int
+	wildcard  bool
+	recursive bool
+}
+
+// JSONPath gives the values selected by the JSONPath expression in the JSON body of the request, e.g.
+//
+//	keys, err := request.JSONPath("$.payment.idempotencyKey")
+//
+// Dot and bracket child notations, array indexes, negative ones from the end, wildcards and the recursive descent
+// are supported, filters and slices are not. Objects and arrays are maps and slices, numbers are json.Number.
+// No selected value is not an error, but the body which is not JSON is.
+func (r *LoggedRequest) JSONPath(expression string) ([]interface{}, error) {
+	steps, err := parseJSONPath(expression)
+	if err != nil {
+		return nil, fmt.Errorf("json path %s: %s", expression, err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(r.Body))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil, fmt.Errorf("json path %s: decode body: %s", expression, err.Error())
+	}
+
+	values := []interface{}{body}
+	for _, step := range steps {
+		values = step.selectFrom(values)
+	}
+
+	return values, nil
+}
+
+func parseJSONPath(expression string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("expression must start with $")
+	}
+
+	var steps []jsonPathStep
+	rest := expression[1:]
+	for rest != "" {
+		var step jsonPathStep
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			rest = step.parseName(rest)
+		case rest[0] == '.':
+			rest = step.parseName(rest[1:])
+		case rest[0] != '[':
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		if step.name == "" && !step.wildcard {
+			var err error
+			if rest, err = step.parseBracket(rest); err != nil {
+				return nil, err
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// parseName fills the step of dot notation name and gives the rest of the expression.
+func (s *jsonPathStep) parseName(expression string) string {
+	end := strings.IndexAny(expression, ".[")
+	if end < 0 {
+		end = len(expression)
+	}
+
+	s.name = expression[:end]
+	if s.name == "*" {
+		s.name = ""
+		s.wildcard = true
+	}
+
+	return expression[end:]
+}
+
+// parseBracket fills the step of bracket notation and gives the rest of the expression.
+func (s *jsonPathStep) parseBracket(expression string) (string, error) {
+	end := strings.IndexByte(expression, ']')
+	if !strings.HasPrefix(expression, "[") || end < 0 {
+		return "", fmt.Errorf("unterminated %q", expression)
+	}
+
+	if quote := expression[1]; quote == '\'' || quote == '"' {
+		// names with ] are found by the closing quote
+		end = strings.Index(expression[2:], string(quote)+"]")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated %q", expression)
+		}
+		s.name = expression[2 : end+2]
+		return expression[end+4:], nil
+	}
+
+	selector := expression[1:end]
+	if selector == "*" {
+		s.wildcard = true
+		return expression[end+1:], nil
+	}
+
+	index, err := strconv.Atoi(selector)
+	if err != nil {
+		return "", fmt.Errorf("unsupported selector [%s]", selector)
+	}
+	s.index = &index
+
+	return expression[end+1:], nil
+}
+
+// selectFrom gives the values selected by the step in every value.
+func (s *jsonPathStep) selectFrom(values []interface{}) []interface{} {
+	var selected []interface{}
+	for _, value := range values {
+		selected = append(selected, s.selectChildren(value)...)
+		if s.recursive {
+			selected = append(selected, s.selectFrom(jsonChildren(value))...)
+		}
+	}
+
+	return selected
+}
+
+func (s *jsonPathStep) selectChildren(value interface{}) []interface{} {
+	if s.wildcard {
+		return jsonChildren(value)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if child, ok := value[s.name]; ok && s.index == nil {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if s.index == nil {
+			return nil
+		}
+		index := *s.index
+		if index < 0 {
+			index += len(value)
+		}
+		if index >= 0 && index < len(value) {
+			return []interface{}{value[index]}
+		}
+	}
+
+	return nil
+}
+
+// jsonChildren gives the elements of the array or the values of the object in the order of the keys.
+func jsonChildren(value interface{}) []interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		children := make([]interface{}, len(keys))
+		for i, key := range keys {
+			children[i] = value[key]
+		}
+		return children
+	case []interface{}:
+		return value
+	}
+
+	return nil
+}
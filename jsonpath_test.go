@@ -0,0 +1,50 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLoggedRequest_JSONPath(t *testing.T) {
+	request := LoggedRequest{Body: []byte(`{
+		"payment": {"idempotencyKey": "6f1c", "amount": 10.50},
+		"items": [{"sku": "a", "tags": ["x"]}, {"sku": "b"}],
+		"odd.key": {"]": true}
+	}`)}
+
+	testCases := []struct {
+		expression string
+		expected   []interface{}
+	}{
+		{expression: "$.payment.idempotencyKey", expected: []interface{}{"6f1c"}},
+		{expression: "$['payment']['amount']", expected: []interface{}{json.Number("10.50")}},
+		{expression: "$.items[-1].sku", expected: []interface{}{"b"}},
+		{expression: "$.items[*].sku", expected: []interface{}{"a", "b"}},
+		{expression: "$..sku", expected: []interface{}{"a", "b"}},
+		{expression: "$..tags[0]", expected: []interface{}{"x"}},
+		{expression: "$['odd.key'][']']", expected: []interface{}{true}},
+		{expression: "$.missing", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			values, err := request.JSONPath(tc.expression)
+			if err != nil {
+				t.Fatalf("JSONPath error: %v", err)
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("expected %v; got %v", tc.expected, values)
+			}
+		})
+	}
+
+	for _, expression := range []string{"payment", "$.items[?(@.sku)]", "$.items[0"} {
+		if _, err := request.JSONPath(expression); err == nil {
+			t.Errorf("expected error of %s", expression)
+		}
+	}
+	if _, err := (&LoggedRequest{Body: []byte("plain")}).JSONPath("$"); err == nil {
+		t.Errorf("expected error of not JSON body")
+	}
+}
@@ -0,0 +1,219 @@
+package wiremock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// envPlaceholder is ${NAME}, ${NAME:-default} or ${NAME-default}, and $${ escaping the literal ${.
+var envPlaceholder = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?-)([^}]*))?\}`)
+
+// A MappingLoader reads stubs from the directory of WireMock mapping files, the way WireMock reads its mappings directory:
+// every .json file of the directory and its subdirectories holds one stub mapping or {"mappings": [...]}.
+type MappingLoader struct {
+	dir        string
+	lookupEnv  func(name string) (string, bool)
+	templated  bool
+	data       interface{}
+	funcs      template.FuncMap
+	leftDelim  string
+	rightDelim string
+}
+
+// NewMappingLoader returns *MappingLoader of the directory.
+func NewMappingLoader(dir string) *MappingLoader {
+	return &MappingLoader{dir: dir}
+}
+
+// WithEnvSubstitution replaces ${NAME} placeholders of every file by environment variables before reading it,
+// so fixtures do not hard-code hostnames and credentials.
+// ${NAME:-default} gives the default when the variable is unset or empty, ${NAME-default} only when it is unset,
+// $${ is the literal ${. The placeholder of the unset variable without default is an error.
+// Placeholders are replaced before templates are rendered, see WithTemplateData.
+func (l *MappingLoader) WithEnvSubstitution() *MappingLoader {
+	l.lookupEnv = os.LookupEnv
+	return l
+}
+
+// WithTemplateData renders every file by text/template with data before reading it, e.g. {{.BaseURL}},
+// so one fixture set can serve many environments.
+func (l *MappingLoader) WithTemplateData(data interface{}) *MappingLoader {
+	l.templated = true
+	l.data = data
+	return l
+}
+
+// WithTemplateFuncs adds functions of the file templates, see WithTemplateData
+func (l *MappingLoader) WithTemplateFuncs(funcs template.FuncMap) *MappingLoader {
+	l.templated = true
+	l.funcs = funcs
+	return l
+}
+
+// WithTemplateDelims sets action delimiters of the file templates.
+// The default {{ and }} are the ones of WireMock response templating too,
+// so files of templated responses need other delimiters, e.g. [[ and ]].
+func (l *MappingLoader) WithTemplateDelims(left, right string) *MappingLoader {
+	l.leftDelim = left
+	l.rightDelim = right
+	return l
+}
+
+// Load reads stubs of all files in lexical order of their paths.
+// Stubs without id get the one derived from their content, see WithDeterministicID, so loading is repeatable.
+func (l *MappingLoader) Load() ([]*StubRule, error) {
+	var stubs []*StubRule
+	err := filepath.WalkDir(l.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		fileStubs, err := l.loadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err.Error())
+		}
+		stubs = append(stubs, fileStubs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load mappings: %s", err.Error())
+	}
+
+	return stubs, nil
+}
+
+func (l *MappingLoader) loadFile(path string) ([]*StubRule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.lookupEnv != nil {
+		if content, err = expandEnv(content, l.lookupEnv); err != nil {
+			return nil, err
+		}
+	}
+	if l.templated {
+		if content, err = l.render(filepath.Base(path), content); err != nil {
+			return nil, err
+		}
+	}
+
+	var file struct {
+		Mappings []*StubRule `json:"mappings"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("read json error: %s", err.Error())
+	}
+	if file.Mappings == nil {
+		var stubRule StubRule
+		if err := json.Unmarshal(content, &stubRule); err != nil {
+			return nil, fmt.Errorf("read json error: %s", err.Error())
+		}
+		file.Mappings = []*StubRule{&stubRule}
+	}
+
+	for _, stubRule := range file.Mappings {
+		if stubRule.UUID() == "" {
+			id, err := stubRule.DeterministicID()
+			if err != nil {
+				return nil, err
+			}
+			stubRule.WithID(id)
+		}
+	}
+
+	return file.Mappings, nil
+}
+
+// expandEnv replaces environment variable placeholders of content, see WithEnvSubstitution.
+func expandEnv(content []byte, lookupEnv func(name string) (string, bool)) ([]byte, error) {
+	var unset []string
+	expanded := envPlaceholder.ReplaceAllFunc(content, func(placeholder []byte) []byte {
+		if string(placeholder) == "$${" {
+			return []byte("${")
+		}
+
+		match := envPlaceholder.FindSubmatch(placeholder)
+		name, operator, defaultValue := string(match[1]), string(match[2]), match[3]
+		value, ok := lookupEnv(name)
+		switch {
+		case ok && (value != "" || operator != ":-"):
+			return []byte(value)
+		case operator != "":
+			return defaultValue
+		default:
+			unset = append(unset, name)
+			return placeholder
+		}
+	})
+	if len(unset) > 0 {
+		return nil, fmt.Errorf("environment variables are not set: %s", strings.Join(unset, ", "))
+	}
+
+	return expanded, nil
+}
+
+func (l *MappingLoader) render(name string, content []byte) ([]byte, error) {
+	tmpl, err := template.New(name).
+		Delims(l.leftDelim, l.rightDelim).
+		Funcs(l.funcs).
+		Option("missingkey=error").
+		Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse template error: %s", err.Error())
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, l.data); err != nil {
+		return nil, fmt.Errorf("render template error: %s", err.Error())
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// LoadStubs registers stubs of the mapping files in one request, overwriting the registered ones with the same ids,
+// see ImportStubs.
+func (c *Client) LoadStubs(loader *MappingLoader) error {
+	stubs, err := loader.Load()
+	if err != nil {
+		return err
+	}
+
+	return c.ImportStubs(stubs)
+}
+
+// ExportStubs writes every registered stub to its own WireMock mapping file <id>.json in dir,
+// which NewMappingLoader reads back, so stubs edited on the server can be committed with the code.
+// Existing files of the same stubs are overwritten, other files are left as they are.
+func (c *Client) ExportStubs(dir string) error {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return fmt.Errorf("export stubs: %s", err.Error())
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("export stubs: %s", err.Error())
+	}
+	for _, stubRule := range stubs {
+		content, err := stubRule.PrettyJSON()
+		if err != nil {
+			return fmt.Errorf("export stubs: build stub %s error: %s", stubRule.UUID(), err.Error())
+		}
+		if err := os.WriteFile(filepath.Join(dir, stubRule.UUID()+".json"), append(content, '\n'), 0o644); err != nil {
+			return fmt.Errorf("export stubs: %s", err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package wiremock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMappingFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll error: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+}
+
+func TestMappingLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeMappingFile(t, filepath.Join(dir, "orders.json"), `{
+		"id": "8c5db8b0-2db4-4ad0-9c2b-3c1a4a1e0f11",
+		"request": {"method": "GET", "urlPath": "/orders/{{.OrderID}}"},
+		"response": {"status": 200, "headers": {"Location": "{{.BaseURL}}/orders/{{.OrderID}}"}}
+	}`)
+	writeMappingFile(t, filepath.Join(dir, "users", "users.json"), `{"mappings": [
+		{"request": {"method": "GET", "urlPath": "/users"}, "response": {"status": 200}},
+		{"request": {"method": "POST", "urlPath": "/users"}, "response": {"status": 201, "body": "[[.User]] {{request.body}}", "transformers": ["response-template"]}}
+	]}`)
+	writeMappingFile(t, filepath.Join(dir, "README.md"), `not a mapping`)
+
+	stubs, err := NewMappingLoader(filepath.Join(dir, "users")).
+		WithTemplateDelims("[[", "]]").
+		WithTemplateData(map[string]string{"User": "ann"}).
+		Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(stubs) != 2 || stubs[1].Response().Body() != "ann {{request.body}}" {
+		t.Fatalf("unexpected stubs %v", stubs)
+	}
+	if stubs[0].UUID() == "" || stubs[0].UUID() == stubs[1].UUID() {
+		t.Errorf("expected distinct derived ids; got %s, %s", stubs[0].UUID(), stubs[1].UUID())
+	}
+
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+	loader := NewMappingLoader(dir).WithTemplateData(map[string]string{"OrderID": "42", "BaseURL": "http://qa"})
+	if err := client.LoadStubs(loader); err == nil {
+		t.Error("expected error of response templating actions parsed with default delimiters")
+	}
+
+	loader = NewMappingLoader(filepath.Join(dir, "orders.json")).WithTemplateData(map[string]string{"OrderID": "42", "BaseURL": "http://qa"})
+	if err := client.LoadStubs(loader); err != nil {
+		t.Fatalf("LoadStubs error: %v", err)
+	}
+	stubRule, err := client.GetStub("8c5db8b0-2db4-4ad0-9c2b-3c1a4a1e0f11")
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if location := stubRule.Response().Headers()["Location"]; location != "http://qa/orders/42" {
+		t.Errorf("unexpected rendered header %q", location)
+	}
+}
+
+func TestMappingLoader_WithEnvSubstitution(t *testing.T) {
+	t.Setenv("WIREMOCK_TEST_HOST", "qa.local")
+	t.Setenv("WIREMOCK_TEST_EMPTY", "")
+
+	dir := t.TempDir()
+	writeMappingFile(t, filepath.Join(dir, "env.json"), `{
+		"request": {"method": "GET", "urlPath": "/env"},
+		"response": {"status": 200, "headers": {
+			"Host": "${WIREMOCK_TEST_HOST}",
+			"Port": "${WIREMOCK_TEST_PORT:-8080}",
+			"Empty": "${WIREMOCK_TEST_EMPTY-unused}",
+			"User": "${WIREMOCK_TEST_EMPTY:-guest}",
+			"Literal": "$${WIREMOCK_TEST_HOST}"
+		}}
+	}`)
+
+	stubs, err := NewMappingLoader(dir).WithEnvSubstitution().Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	for key, expected := range map[string]string{
+		"Host":    "qa.local",
+		"Port":    "8080",
+		"Empty":   "",
+		"User":    "guest",
+		"Literal": "${WIREMOCK_TEST_HOST}",
+	} {
+		if value := stubs[0].Response().Headers()[key]; value != expected {
+			t.Errorf("%s: expected %q; got %q", key, expected, value)
+		}
+	}
+
+	writeMappingFile(t, filepath.Join(dir, "unset.json"), `{"request": {"urlPath": "/${WIREMOCK_TEST_UNSET}"}}`)
+	if _, err := NewMappingLoader(dir).WithEnvSubstitution().Load(); err == nil {
+		t.Error("expected error of the unset variable")
+	}
+}
+
+func TestClient_ExportStubs(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubs := []*StubRule{
+		Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200),
+		Post(URLPathEqualTo("/orders")).WithHeader("X-Trace", Contains("abc")).WillReturn("", nil, 201),
+	}
+	for _, stubRule := range stubs {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "mappings")
+	if err := client.ExportStubs(dir); err != nil {
+		t.Fatalf("ExportStubs error: %v", err)
+	}
+
+	loaded, err := NewMappingLoader(dir).Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(loaded) != len(stubs) {
+		t.Fatalf("expected %d exported stubs; got %d", len(stubs), len(loaded))
+	}
+	for _, stubRule := range stubs {
+		content, err := os.ReadFile(filepath.Join(dir, stubRule.UUID()+".json"))
+		if err != nil {
+			t.Fatalf("ReadFile error: %v", err)
+		}
+		if expected, _ := stubRule.PrettyJSON(); string(content) != string(expected)+"\n" {
+			t.Errorf("expected exported stub %s; got %s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package wiremock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultPriority is the priority WireMock gives to stubs without explicit one.
+const DefaultPriority int64 = 5
+
+// A StubMatch is the result of the dry-run matching of the request against stubs.
+type StubMatch struct {
+	// Stub is the stub which serves the request, nil when no stub matches.
+	Stub *StubRule
+	// Candidates are all stubs matching the request in the order WireMock picks them, so Stub is the first one.
+	Candidates []*StubRule
+	// Undecided are stubs which cannot be evaluated locally, e.g. with matchesJsonPath or scenario state.
+	// They are not in Candidates, and Stub may be wrong when one of them has the same or a higher priority.
+	Undecided []*StubRule
+}
+
+// Ambiguous reports whether more than one stub of the highest priority matches the request,
+// so the one serving it depends on the registration order.
+func (m *StubMatch) Ambiguous() bool {
+	return len(m.Candidates) > 1 && m.Candidates[1].Priority() == m.Candidates[0].Priority()
+}
+
+// MatchStub matches the request against stubs given in registration order, the way WireMock picks the stub:
+// the matching stub of the highest priority (the lowest number) wins, the most recently registered one among equals.
+func MatchStub(stubs []*StubRule, request *LoggedRequest) *StubMatch {
+	match := &StubMatch{}
+	var matching []*StubRule
+	for _, stubRule := range stubs {
+		matched, known := stubRule.Match(request)
+		switch {
+		case !known:
+			match.Undecided = append(match.Undecided, stubRule)
+		case matched:
+			matching = append(matching, stubRule)
+		}
+	}
+
+	match.rank(matching)
+
+	return match
+}
+
+// MatchStub asks which registered stub would serve the request, without sending it.
+// Stubs are matched locally, the ones which cannot be evaluated locally are decided by the server near misses.
+func (c *Client) MatchStub(request *LoggedRequest) (*StubMatch, error) {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("match stub: %s", err.Error())
+	}
+
+	// WireMock lists the most recently registered stubs first.
+	for i, j := 0, len(stubs)-1; i < j; i, j = i+1, j-1 {
+		stubs[i], stubs[j] = stubs[j], stubs[i]
+	}
+
+	match := MatchStub(stubs, request)
+	if len(match.Undecided) == 0 {
+		return match, nil
+	}
+
+	nearMisses, err := c.findNearMissesForRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("match stub: %s", err.Error())
+	}
+
+	exact := map[string]bool{}
+	for i := range nearMisses {
+		if nearMisses[i].MatchResult.Distance == 0 {
+			if stubRule, err := nearMisses[i].StubMapping(); err == nil && stubRule != nil {
+				exact[stubRule.UUID()] = true
+			}
+		}
+	}
+
+	var matching []*StubRule
+	for _, stubRule := range stubs {
+		if matched, known := stubRule.Match(request); (known && matched) || (!known && exact[stubRule.UUID()]) {
+			matching = append(matching, stubRule)
+		}
+	}
+
+	resolved := &StubMatch{}
+	resolved.rank(matching)
+
+	return resolved, nil
+}
+
+// rank sets candidates from matching stubs given in registration order.
+func (m *StubMatch) rank(matching []*StubRule) {
+	m.Candidates = make([]*StubRule, 0, len(matching))
+	for i := len(matching) - 1; i >= 0; i-- {
+		m.Candidates = append(m.Candidates, matching[i])
+	}
+
+	sort.SliceStable(m.Candidates, func(i, j int) bool {
+		return m.Candidates[i].Priority() < m.Candidates[j].Priority()
+	})
+	if len(m.Candidates) > 0 {
+		m.Stub = m.Candidates[0]
+	}
+}
+
+func (c *Client) findNearMissesForRequest(request *LoggedRequest) ([]NearMiss, error) {
+	requestBody, err := c.codec.Marshal(request.jsonValue())
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: build error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/near-misses/request", c.url, wiremockAdminURN), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("find near misses: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("find near misses: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var nearMissesResponse struct {
+		NearMisses []NearMiss `json:"nearMisses"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &nearMissesResponse); err != nil {
+		return nil, fmt.Errorf("find near misses: read json error: %s", err.Error())
+	}
+
+	return nearMissesResponse.NearMisses, nil
+}
+
+// Match evaluates the stub request pattern against the request locally.
+// The second result is false when the stub can be evaluated only by the server, then the first one is meaningless.
+// Stubs requiring a scenario state are never evaluated locally.
+func (s *StubRule) Match(request *LoggedRequest) (bool, bool) {
+	matched, known := s.Request().Match(request)
+	if known && !matched {
+		return false, true
+	}
+	if s.requiredScenarioState != nil {
+		return false, false
+	}
+
+	return matched, known
+}
+
+// Match evaluates the request pattern against the request locally.
+// The second result is false when the pattern has matchers which can be evaluated only by the server,
+// e.g. matchesJsonPath or multipart patterns, then the first one is meaningless.
+func (r *Request) Match(request *LoggedRequest) (bool, bool) {
+	known := true
+	mismatch := func(matched, matcherKnown bool) bool {
+		if !matcherKnown {
+			known = false
+			return false
+		}
+		return !matched
+	}
+
+	if r.method != "" && r.method != MethodAny && !strings.EqualFold(r.method, request.Method) {
+		return false, true
+	}
+	if urlMatcher := r.URLMatcher(); urlMatcher != nil && !matchURL(urlMatcher, request.URL) {
+		return false, true
+	}
+
+	for key, matcher := range r.headers {
+		if mismatch(matchAnyValue(matcher, request.headerValues(key))) {
+			return false, true
+		}
+	}
+	for key, matcher := range r.cookies {
+		if mismatch(matchAnyValue(matcher, request.cookieValues(key))) {
+			return false, true
+		}
+	}
+	for key, matcher := range r.queryParams {
+		if mismatch(matchAnyValue(matcher, request.queryValues(key))) {
+			return false, true
+		}
+	}
+
+	body := string(request.Body)
+	for _, bodyPattern := range r.bodyPatterns {
+		if mismatch(matchValue(bodyPattern, &body)) {
+			return false, true
+		}
+	}
+
+	if r.basicAuthCredentials != nil {
+		credentials := r.basicAuthCredentials.username + ":" + r.basicAuthCredentials.password
+		authorization := request.header("Authorization")
+		if authorization == nil || *authorization != "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)) {
+			return false, true
+		}
+	}
+
+	if len(r.multipartPatterns) > 0 {
+		known = false
+	}
+
+	return known, known
+}
+
+// matchAnyValue evaluates matcher against multi-value parameter, which matches when any of its values does,
+// unless the matcher is the multi-value one.
+func matchAnyValue(matcher ParamMatcherInterface, values []string) (bool, bool) {
+	if paramMatcher, ok := matcher.(ParamMatcher); ok && paramMatcher.valueMatchers != nil {
+		switch paramMatcher.strategy {
+		case ParamHasExactly:
+			if len(values) != len(paramMatcher.valueMatchers) {
+				return false, true
+			}
+			return matchDistinctValues(paramMatcher.valueMatchers, values, make([]bool, len(values)))
+		case ParamIncludes:
+			known := true
+			for _, valueMatcher := range paramMatcher.valueMatchers {
+				matched, valueKnown := matchAnyValue(valueMatcher, values)
+				if valueKnown && !matched {
+					return false, true
+				}
+				known = known && valueKnown
+			}
+			return known, known
+		}
+	}
+
+	if len(values) == 0 {
+		return matchValue(matcher, nil)
+	}
+	if matcher.Strategy() == ParamAbsent {
+		return false, true
+	}
+
+	known := true
+	for i := range values {
+		matched, valueKnown := matchValue(matcher, &values[i])
+		if matched && valueKnown {
+			return true, true
+		}
+		known = known && valueKnown
+	}
+
+	return false, known
+}
+
+// matchDistinctValues reports whether every matcher matches its own value not used by the previous matchers.
+func matchDistinctValues(matchers []ParamMatcher, values []string, used []bool) (bool, bool) {
+	if len(matchers) == 0 {
+		return true, true
+	}
+
+	for i := range values {
+		if used[i] {
+			continue
+		}
+		matched, known := matchValue(matchers[0], &values[i])
+		if !known {
+			return false, false
+		}
+		if !matched {
+			continue
+		}
+
+		used[i] = true
+		if matched, known := matchDistinctValues(matchers[1:], values, used); matched || !known {
+			return matched, known
+		}
+		used[i] = false
+	}
+
+	return false, true
+}
+
+func (r *LoggedRequest) header(name string) *string {
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return &value
+		}
+	}
+
+	return nil
+}
+
+// headerValues gives all values of the header, matching its name case-insensitively,
+// the only one when the request has not been read from WireMock JSON.
+func (r *LoggedRequest) headerValues(name string) []string {
+	for key, value := range r.Headers {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if values, ok := r.multiHeaders[key]; ok {
+			return values
+		}
+		return []string{value}
+	}
+
+	return nil
+}
+
+// cookieValues gives all values of the cookie, the only one when the request has not been read from WireMock JSON.
+func (r *LoggedRequest) cookieValues(name string) []string {
+	if values, ok := r.multiCookies[name]; ok {
+		return values
+	}
+	if value, ok := r.Cookies[name]; ok {
+		return []string{value}
+	}
+
+	return nil
+}
+
+func (r *LoggedRequest) queryValues(name string) []string {
+	if r.QueryParams != nil {
+		return r.QueryParams[name]
+	}
+
+	if i := strings.IndexByte(r.URL, '?'); i >= 0 {
+		query, _ := url.ParseQuery(r.URL[i+1:])
+		return query[name]
+	}
+
+	return nil
+}
+
+// jsonValue gives the JSON representation of the request the way WireMock logs it.
+func (r *LoggedRequest) jsonValue() map[string]interface{} {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	absoluteURL := r.AbsoluteURL
+	if absoluteURL == "" {
+		absoluteURL = "http://localhost" + r.URL
+	}
+
+	return map[string]interface{}{
+		"url":          r.URL,
+		"absoluteUrl":  absoluteURL,
+		"method":       method,
+		"headers":      r.headersJSON(),
+		"cookies":      r.cookiesJSON(),
+		"body":         string(r.Body),
+		"bodyAsBase64": base64.StdEncoding.EncodeToString(r.Body),
+	}
+}
+
+// headersJSON gives headers the way WireMock logs them: a string, or an array of strings for the repeated header.
+func (r *LoggedRequest) headersJSON() map[string]interface{} {
+	if r.Headers == nil {
+		return nil
+	}
+
+	headers := make(map[string]interface{}, len(r.Headers))
+	for key, value := range r.Headers {
+		if values, ok := r.multiHeaders[key]; ok && len(values) > 1 {
+			headers[key] = values
+		} else {
+			headers[key] = value
+		}
+	}
+
+	return headers
+}
+
+// cookiesJSON gives cookies the way WireMock logs them: a string, or an array of strings for the repeated cookie.
+func (r *LoggedRequest) cookiesJSON() map[string]interface{} {
+	if r.Cookies == nil {
+		return nil
+	}
+
+	cookies := make(map[string]interface{}, len(r.Cookies))
+	for key := range r.Cookies {
+		if values := r.cookieValues(key); len(values) == 1 {
+			cookies[key] = values[0]
+		} else {
+			cookies[key] = values
+		}
+	}
+
+	return cookies
+}
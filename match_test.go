@@ -0,0 +1,102 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMatchStub(t *testing.T) {
+	fallback := Get(URLPathMatching("/orders/.*")).AtPriority(10)
+	older := Get(URLPathEqualTo("/orders/1")).WithQueryParam("expand", EqualTo("items"))
+	newer := Get(URLPathEqualTo("/orders/1")).WithHeader("Accept", Contains("json"))
+	secured := Get(URLPathEqualTo("/orders/1")).WithBasicAuth("ann", "secret").AtPriority(1)
+	byJSONPath := Post(URLPathEqualTo("/orders/1")).WithBodyPattern(MatchingJsonPath("$.id"))
+
+	request := &LoggedRequest{
+		Method:  "GET",
+		URL:     "/orders/1?expand=customer&expand=items",
+		Headers: map[string]string{"accept": "application/json"},
+	}
+
+	match := MatchStub([]*StubRule{fallback, older, newer, secured, byJSONPath}, request)
+	if match.Stub != newer || len(match.Candidates) != 3 || match.Candidates[1] != older || match.Candidates[2] != fallback {
+		t.Errorf("expected newer, older and fallback stubs; got %v", match.Candidates)
+	}
+	if !match.Ambiguous() {
+		t.Error("expected match of two stubs of default priority to be ambiguous")
+	}
+	if len(match.Undecided) != 0 {
+		t.Errorf("expected POST stub to be decided by method; got %v", match.Undecided)
+	}
+
+	request.Headers["Authorization"] = "Basic YW5uOnNlY3JldA=="
+	if match := MatchStub([]*StubRule{fallback, older, newer, secured}, request); match.Stub != secured || match.Ambiguous() {
+		t.Errorf("expected secured stub of priority 1; got %v", match.Stub)
+	}
+
+	request.Method = "POST"
+	if match := MatchStub([]*StubRule{fallback, byJSONPath}, request); match.Stub != nil || len(match.Undecided) != 1 {
+		t.Errorf("expected jsonPath stub to be undecided; got %v, %v", match.Stub, match.Undecided)
+	}
+}
+
+func TestClient_MatchStub(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	fallback := Post(URLPathMatching("/orders.*")).AtPriority(10)
+	byJSONPath := Post(URLPathEqualTo("/orders")).WithBodyPattern(MatchingJsonPath("$.id"))
+	for _, stubRule := range []*StubRule{fallback, byJSONPath} {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	request := &LoggedRequest{Method: "POST", URL: "/orders", Body: []byte(`{"id":1}`)}
+
+	match, err := client.MatchStub(request)
+	if err != nil {
+		t.Fatalf("MatchStub error: %v", err)
+	}
+	if match.Stub == nil || match.Stub.UUID() != fallback.UUID() {
+		t.Errorf("expected fallback stub without server near misses; got %v", match.Stub)
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{"stubMapping": byJSONPath, "matchResult": map[string]float64{"distance": 0}})
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	server.nearMisses = []json.RawMessage{raw}
+
+	match, err = client.MatchStub(request)
+	if err != nil {
+		t.Fatalf("MatchStub error: %v", err)
+	}
+	if match.Stub == nil || match.Stub.UUID() != byJSONPath.UUID() || len(match.Candidates) != 2 || len(match.Undecided) != 0 {
+		t.Errorf("expected jsonPath stub decided by the server; got %v", match.Candidates)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	for _, test := range []struct {
+		matcher       ParamMatcher
+		authorization string
+		matched       bool
+	}{
+		{EqualTo("abc"), "Bearer abc", true},
+		{EqualTo("abc"), "abc", false},
+		{Matching("[a-c]+"), "Bearer cab", true},
+		{Matching("[a-c]+"), "Bearer xyz", false},
+		{NotMatching("expired-.*"), "Bearer fresh", true},
+		{NotMatching("expired-.*"), "Bearer expired-1", false},
+		{NotMatching("expired-.*"), "Basic YW5u", false},
+		{Contains("a.c"), "Bearer xa.cx", true},
+		{Contains("a.c"), "Bearer xabcx", false},
+	} {
+		stubRule := Get(URLPathEqualTo("/me")).WithBearerToken(test.matcher)
+		request := &LoggedRequest{Method: "GET", URL: "/me", Headers: map[string]string{"Authorization": test.authorization}}
+		if matched, known := stubRule.Match(request); !known || matched != test.matched {
+			t.Errorf("%s %q against %q: expected matched %v; got %v, %v", test.matcher.Strategy(), test.matcher.Value(), test.authorization, test.matched, matched, known)
+		}
+	}
+}
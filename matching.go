@@ -1,5 +1,15 @@
 package wiremock
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
 // Types of params matching.
 const (
 	ParamEqualTo         ParamMatchingStrategy = "equalTo"
@@ -7,10 +17,15 @@ const (
 	ParamContains        ParamMatchingStrategy = "contains"
 	ParamEqualToXml      ParamMatchingStrategy = "equalToXml"
 	ParamEqualToJson     ParamMatchingStrategy = "equalToJson"
+	ParamBinaryEqualTo   ParamMatchingStrategy = "binaryEqualTo"
 	ParamMatchesXPath    ParamMatchingStrategy = "matchesXPath"
 	ParamMatchesJsonPath ParamMatchingStrategy = "matchesJsonPath"
 	ParamAbsent          ParamMatchingStrategy = "absent"
 	ParamDoesNotMatch    ParamMatchingStrategy = "doesNotMatch"
+	ParamHasExactly      ParamMatchingStrategy = "hasExactly"
+	ParamIncludes        ParamMatchingStrategy = "includes"
+	ParamAnd             ParamMatchingStrategy = "and"
+	ParamOr              ParamMatchingStrategy = "or"
 )
 
 // Types of url matching.
@@ -52,6 +67,46 @@ func (m URLMatcher) Value() string {
 	return m.value
 }
 
+// PathOf builds the url path of segments, escaping each of them, e.g. PathOf("users", "a/b c") is "/users/a%2Fb%20c".
+// Segments are raw values: already escaped ones are escaped once more.
+//
+//	wiremock.Get(wiremock.URLPathEqualTo(wiremock.PathOf("users", userID)))
+func PathOf(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+
+	return "/" + strings.Join(escaped, "/")
+}
+
+// PathPatternOf is PathOf quoted for patterns, so regexp metacharacters of segments are matched literally.
+//
+//	wiremock.Get(wiremock.URLPathMatching(wiremock.PathPatternOf("files", name) + "/.+"))
+func PathPatternOf(segments ...string) string {
+	return regexp.QuoteMeta(PathOf(segments...))
+}
+
+// IgnoreTrailingSlash returns URLMatcher accepting the path with and without the trailing slash,
+// e.g. URLPathEqualTo("/v1/users").IgnoreTrailingSlash() matches both /v1/users and /v1/users/.
+// Literal matchers become patterns. URLMatching patterns are returned as is, since their path end is unknown.
+func (m URLMatcher) IgnoreTrailingSlash() URLMatcher {
+	switch m.strategy {
+	case URLPathEqualToRule:
+		return URLPathMatching(regexp.QuoteMeta(strings.TrimSuffix(m.value, "/")) + "/?")
+	case URLEqualToRule:
+		path, query := m.value, ""
+		if i := strings.IndexByte(m.value, '?'); i >= 0 {
+			path, query = m.value[:i], m.value[i:]
+		}
+		return URLMatching(regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + "/?" + regexp.QuoteMeta(query))
+	case URLPathMatchingRule:
+		return URLPathMatching("(?:" + strings.TrimSuffix(m.value, "/") + ")/?")
+	}
+
+	return m
+}
+
 // URLEqualTo returns URLMatcher with URLEqualToRule matching strategy.
 func URLEqualTo(url string) URLMatcher {
 	return URLMatcher{
@@ -89,6 +144,10 @@ type ParamMatcher struct {
 	strategy ParamMatchingStrategy
 	value    string
 	flags    map[string]bool
+	// valueMatcher checks the value selected by the expression of path matchers, see MatchingXPathWith
+	valueMatcher *ParamMatcher
+	// valueMatchers check values of multi-value parameters, see HavingExactly, or are combined, see AllOf
+	valueMatchers []ParamMatcher
 }
 
 // Strategy returns ParamMatchingStrategy of ParamMatcher.
@@ -106,6 +165,16 @@ func (m ParamMatcher) Flags() map[string]bool {
 	return m.flags
 }
 
+// ValueMatchers returns matchers of multi-value parameter values or combined matchers.
+func (m ParamMatcher) ValueMatchers() []ParamMatcher {
+	return m.valueMatchers
+}
+
+// ValueMatcher returns the matcher of the value selected by the path expression, nil when not set.
+func (m ParamMatcher) ValueMatcher() *ParamMatcher {
+	return m.valueMatcher
+}
+
 // EqualTo returns ParamMatcher with ParamEqualTo matching strategy.
 func EqualTo(param string) ParamMatcher {
 	return ParamMatcher{
@@ -163,6 +232,15 @@ func EqualToJson(param string, flags ...EqualFlag) ParamMatcher {
 	}
 }
 
+// BinaryEqualTo returns ParamMatcher with ParamBinaryEqualTo matching strategy for binary bodies,
+// sent to WireMock as base64.
+func BinaryEqualTo(body []byte) ParamMatcher {
+	return ParamMatcher{
+		strategy: ParamBinaryEqualTo,
+		value:    base64.StdEncoding.EncodeToString(body),
+	}
+}
+
 // MatchingXPath returns ParamMatcher with ParamMatchesXPath matching strategy.
 func MatchingXPath(param string) ParamMatcher {
 	return ParamMatcher{
@@ -171,6 +249,20 @@ func MatchingXPath(param string) ParamMatcher {
 	}
 }
 
+// MatchingXPathWith returns ParamMatcher with ParamMatchesXPath matching strategy checking the text
+// of the node selected by the expression with matcher, e.g.
+//
+//	MatchingXPathWith("//order/status/text()", EqualTo("PAID"))
+//
+// Unlike MatchingXPath, which matches when the expression selects any node.
+func MatchingXPathWith(expression string, matcher ParamMatcher) ParamMatcher {
+	return ParamMatcher{
+		strategy:     ParamMatchesXPath,
+		value:        expression,
+		valueMatcher: &matcher,
+	}
+}
+
 // MatchingJsonPath returns ParamMatcher with ParamMatchesJsonPath matching strategy.
 func MatchingJsonPath(param string) ParamMatcher {
 	return ParamMatcher{
@@ -179,6 +271,20 @@ func MatchingJsonPath(param string) ParamMatcher {
 	}
 }
 
+// MatchingJsonPathWith returns ParamMatcher with ParamMatchesJsonPath matching strategy checking the value
+// selected by the expression with matcher, e.g.
+//
+//	MatchingJsonPathWith("$.order.id", EqualTo("42"))
+//
+// Unlike MatchingJsonPath, which matches when the expression selects any value.
+func MatchingJsonPathWith(expression string, matcher ParamMatcher) ParamMatcher {
+	return ParamMatcher{
+		strategy:     ParamMatchesJsonPath,
+		value:        expression,
+		valueMatcher: &matcher,
+	}
+}
+
 // NotMatching returns ParamMatcher with ParamDoesNotMatch matching strategy.
 func NotMatching(param string) ParamMatcher {
 	return ParamMatcher{
@@ -187,6 +293,65 @@ func NotMatching(param string) ParamMatcher {
 	}
 }
 
+// HavingExactly returns ParamMatcher with ParamHasExactly matching strategy for multi-value parameters:
+// the parameter has as many values as matchers and every matcher matches its own value, in any order.
+// Without matchers the parameter is present but empty, e.g. ?flag.
+func HavingExactly(matchers ...ParamMatcher) ParamMatcher {
+	if len(matchers) == 0 {
+		matchers = []ParamMatcher{EqualTo("")}
+	}
+
+	return ParamMatcher{
+		strategy:      ParamHasExactly,
+		valueMatchers: matchers,
+	}
+}
+
+// Including returns ParamMatcher with ParamIncludes matching strategy for multi-value parameters:
+// every matcher matches some value of the parameter, which may have other values.
+func Including(matchers ...ParamMatcher) ParamMatcher {
+	return ParamMatcher{
+		strategy:      ParamIncludes,
+		valueMatchers: matchers,
+	}
+}
+
+// AllOf returns ParamMatcher with ParamAnd matching strategy, matching the value matched by all matchers, e.g.
+//
+//	WithHeader("X-Trace", AllOf(Contains("foo"), Matching("^abc.*")))
+//
+// Combined matchers require WireMock 3, registering them on older servers fails with ErrUnsupportedByServer.
+func AllOf(matchers ...ParamMatcher) ParamMatcher {
+	return ParamMatcher{
+		strategy:      ParamAnd,
+		valueMatchers: matchers,
+	}
+}
+
+// AnyOf returns ParamMatcher with ParamOr matching strategy, matching the value matched by any of matchers.
+// Combined matchers require WireMock 3, registering them on older servers fails with ErrUnsupportedByServer.
+func AnyOf(matchers ...ParamMatcher) ParamMatcher {
+	return ParamMatcher{
+		strategy:      ParamOr,
+		valueMatchers: matchers,
+	}
+}
+
+// Count returns ParamMatcher of the multi-value parameter appearing exactly count times with any values.
+// Count(0) is Absent.
+func Count(count int) ParamMatcher {
+	if count <= 0 {
+		return Absent()
+	}
+
+	matchers := make([]ParamMatcher, count)
+	for i := range matchers {
+		matchers[i] = Matching(".*")
+	}
+
+	return HavingExactly(matchers...)
+}
+
 func Absent() ParamMatcher {
 	return ParamMatcher{
 		strategy: ParamAbsent,
@@ -196,3 +361,226 @@ func Absent() ParamMatcher {
 		},
 	}
 }
+
+// matchValue evaluates matcher against value locally.
+// The second result is false when the strategy cannot be evaluated without the server.
+func matchValue(matcher ParamMatcherInterface, value *string) (bool, bool) {
+	if paramMatcher, ok := matcher.(ParamMatcher); ok && (paramMatcher.strategy == ParamAnd || paramMatcher.strategy == ParamOr) {
+		return matchCombined(paramMatcher, value)
+	}
+	if matcher.Strategy() == ParamAbsent {
+		return value == nil, true
+	}
+	if value == nil {
+		return false, true
+	}
+
+	switch matcher.Strategy() {
+	case ParamEqualTo:
+		if matcher.Flags()["caseInsensitive"] {
+			return strings.EqualFold(*value, matcher.Value()), true
+		}
+		return *value == matcher.Value(), true
+	case ParamContains:
+		return strings.Contains(*value, matcher.Value()), true
+	case ParamMatches, ParamDoesNotMatch:
+		re, err := regexp.Compile("^(?:" + matcher.Value() + ")$")
+		if err != nil {
+			return false, false
+		}
+		return re.MatchString(*value) == (matcher.Strategy() == ParamMatches), true
+	case ParamEqualToJson:
+		if len(matcher.Flags()) > 0 {
+			return false, false
+		}
+		var expected, actual interface{}
+		if json.Unmarshal([]byte(matcher.Value()), &expected) != nil || json.Unmarshal([]byte(*value), &actual) != nil {
+			return false, true
+		}
+		return reflect.DeepEqual(expected, actual), true
+	case ParamBinaryEqualTo:
+		expected, err := base64.StdEncoding.DecodeString(matcher.Value())
+		if err != nil {
+			return false, false
+		}
+		return *value == string(expected), true
+	}
+
+	return false, false
+}
+
+// matchCombined evaluates the and or the or combination of matchers against value locally.
+func matchCombined(matcher ParamMatcher, value *string) (bool, bool) {
+	// and matches unless some matcher fails, or fails unless some matcher matches
+	decisive := matcher.strategy == ParamOr
+	known := true
+	for _, valueMatcher := range matcher.valueMatchers {
+		matched, valueKnown := matchValue(valueMatcher, value)
+		if valueKnown && matched == decisive {
+			return decisive, true
+		}
+		known = known && valueKnown
+	}
+
+	return !decisive && known, known
+}
+
+// matchURL evaluates url matcher against url with query locally.
+func matchURL(matcher URLMatcherInterface, url string) bool {
+	path := url
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		path = url[:i]
+	}
+
+	switch matcher.Strategy() {
+	case URLEqualToRule:
+		return url == matcher.Value()
+	case URLPathEqualToRule:
+		return path == matcher.Value()
+	case URLPathMatchingRule:
+		re, err := regexp.Compile("^(?:" + matcher.Value() + ")$")
+		return err == nil && re.MatchString(path)
+	case URLMatchingRule:
+		re, err := regexp.Compile("^(?:" + matcher.Value() + ")$")
+		return err == nil && re.MatchString(url)
+	}
+
+	return false
+}
+
+// cloneParamMatchers gives a copy of named matchers map.
+func cloneParamMatchers(matchers map[string]ParamMatcherInterface) map[string]ParamMatcherInterface {
+	if matchers == nil {
+		return nil
+	}
+
+	result := make(map[string]ParamMatcherInterface, len(matchers))
+	for key, matcher := range matchers {
+		result[key] = matcher
+	}
+
+	return result
+}
+
+// paramMatcherJSON gives the WireMock JSON object of matcher.
+// Flags are applied after the strategy, so a flag with the strategy name (see Absent) wins.
+func paramMatcherJSON(matcher ParamMatcherInterface) map[string]interface{} {
+	result := map[string]interface{}{
+		string(matcher.Strategy()): matcher.Value(),
+	}
+	if paramMatcher, ok := matcher.(ParamMatcher); ok && paramMatcher.valueMatcher != nil {
+		valueMatcher := paramMatcherJSON(*paramMatcher.valueMatcher)
+		valueMatcher["expression"] = matcher.Value()
+		result[string(matcher.Strategy())] = valueMatcher
+	}
+	if paramMatcher, ok := matcher.(ParamMatcher); ok && paramMatcher.valueMatchers != nil {
+		valueMatchers := make([]map[string]interface{}, len(paramMatcher.valueMatchers))
+		for i, valueMatcher := range paramMatcher.valueMatchers {
+			valueMatchers[i] = paramMatcherJSON(valueMatcher)
+		}
+		result[string(matcher.Strategy())] = valueMatchers
+	}
+
+	for flag, value := range matcher.Flags() {
+		result[flag] = value
+	}
+
+	return result
+}
+
+// paramMatchersJSON gives the WireMock JSON objects of named matchers.
+func paramMatchersJSON(matchers map[string]ParamMatcherInterface) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{}, len(matchers))
+	for key, matcher := range matchers {
+		result[key] = paramMatcherJSON(matcher)
+	}
+
+	return result
+}
+
+// UnmarshalJSON fills ParamMatcher from WireMock matcher JSON.
+func (m *ParamMatcher) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = ParamMatcher{}
+	for key, rawValue := range raw {
+		var flag bool
+		if key != string(ParamAbsent) {
+			if err := json.Unmarshal(rawValue, &flag); err == nil {
+				if m.flags == nil {
+					m.flags = map[string]bool{}
+				}
+				m.flags[key] = flag
+				continue
+			}
+		}
+
+		if m.strategy != "" {
+			return fmt.Errorf("matcher has several strategies: %s, %s", m.strategy, key)
+		}
+		m.strategy = ParamMatchingStrategy(key)
+
+		if m.strategy == ParamAbsent {
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			if m.strategy == ParamHasExactly || m.strategy == ParamIncludes || m.strategy == ParamAnd || m.strategy == ParamOr {
+				if err := json.Unmarshal(rawValue, &m.valueMatchers); err != nil {
+					return fmt.Errorf("%s: %s", key, err.Error())
+				}
+				continue
+			}
+			if m.strategy == ParamMatchesXPath || m.strategy == ParamMatchesJsonPath {
+				if err := m.unmarshalValueMatcher(rawValue); err != nil {
+					return fmt.Errorf("%s: %s", key, err.Error())
+				}
+				continue
+			}
+			if m.strategy != ParamEqualToJson {
+				return fmt.Errorf("unsupported %s matcher value: %s", key, string(rawValue))
+			}
+			value = string(rawValue)
+		}
+		m.value = value
+	}
+
+	if m.strategy == "" {
+		return fmt.Errorf("matcher strategy not found: %s", string(data))
+	}
+
+	if m.strategy == ParamAbsent {
+		*m = Absent()
+	}
+
+	return nil
+}
+
+// unmarshalValueMatcher fills the expression and the value matcher from the object form of path matchers.
+func (m *ParamMatcher) unmarshalValueMatcher(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("unsupported matcher value: %s", string(data))
+	}
+
+	if err := json.Unmarshal(raw["expression"], &m.value); err != nil {
+		return fmt.Errorf("expression not found: %s", string(data))
+	}
+	delete(raw, "expression")
+
+	rawMatcher, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	var valueMatcher ParamMatcher
+	if err := valueMatcher.UnmarshalJSON(rawMatcher); err != nil {
+		return err
+	}
+	m.valueMatcher = &valueMatcher
+
+	return nil
+}
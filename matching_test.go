@@ -0,0 +1,228 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMatchingXPathWith(t *testing.T) {
+	request := Post(URLPathEqualTo("/orders")).
+		WithBodyPattern(MatchingXPathWith("//order/status/text()", EqualToIgnoreCase("paid"))).
+		Request()
+
+	raw, err := request.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Request MarshalJSON error: %v", err)
+	}
+	expected := `"bodyPatterns":[{"matchesXPath":{"caseInsensitive":true,"equalTo":"paid","expression":"//order/status/text()"}}]`
+	if !strings.Contains(string(raw), expected) {
+		t.Errorf("expected request to contain %s; got %s", expected, raw)
+	}
+
+	var decoded Request
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("Request UnmarshalJSON error: %v", err)
+	}
+	bodyPattern := decoded.BodyPatterns()[0]
+	valueMatcher := bodyPattern.ValueMatcher()
+	if bodyPattern.Value() != "//order/status/text()" || valueMatcher == nil ||
+		valueMatcher.Strategy() != ParamEqualTo || valueMatcher.Value() != "paid" || !valueMatcher.Flags()["caseInsensitive"] {
+		t.Errorf("unexpected decoded matcher %+v", bodyPattern)
+	}
+
+	invalid := Post(URLPathEqualTo("/orders")).WithBodyPattern(MatchingXPathWith("//status/text()", Matching("(")))
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "bodyPatterns[0]: matchesXPath: matches: invalid regexp") {
+		t.Errorf("expected invalid value matcher reported; got %v", err)
+	}
+}
+
+func TestRequest_WithRawQuery(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/v1.0/search")).WithRawQuery(Matching("a=1&b=.*"))
+
+	urlMatcher := stubRule.Request().URLMatcher()
+	if urlMatcher.Strategy() != URLMatchingRule || urlMatcher.Value() != `/v1\.0/search\?(?:a=1&b=.*)` {
+		t.Errorf("expected combined url pattern; got %s %s", urlMatcher.Strategy(), urlMatcher.Value())
+	}
+
+	for url, expected := range map[string]bool{
+		"/v1.0/search?a=1&b=2": true,
+		"/v1.0/search?b=2&a=1": false,
+		"/v1.0/search":         false,
+		"/v1x0/search?a=1&b=2": false,
+	} {
+		if matched, _ := stubRule.Match(&LoggedRequest{Method: "GET", URL: url}); matched != expected {
+			t.Errorf("%s: expected matched %v", url, expected)
+		}
+	}
+
+	noQuery := Get(URLPathEqualTo("/health")).WithRawQuery(Absent())
+	if matched, _ := noQuery.Match(&LoggedRequest{Method: "GET", URL: "/health?verbose"}); matched {
+		t.Error("expected request with query not matched by absent raw query")
+	}
+
+	invalid := Get(URLEqualTo("/search?a=1")).WithRawQuery(EqualTo("a=1"))
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "rawQuery: url matches the query itself") {
+		t.Errorf("expected url matcher reported; got %v", err)
+	}
+}
+
+func TestURLMatcher_IgnoreTrailingSlash(t *testing.T) {
+	tests := []struct {
+		matcher URLMatcher
+		url     string
+		matched bool
+	}{
+		{URLPathEqualTo("/v1/users").IgnoreTrailingSlash(), "/v1/users", true},
+		{URLPathEqualTo("/v1/users").IgnoreTrailingSlash(), "/v1/users/?page=2", true},
+		{URLPathEqualTo("/v1/users/").IgnoreTrailingSlash(), "/v1/users", true},
+		{URLPathEqualTo("/v1/users").IgnoreTrailingSlash(), "/v1/users/1", false},
+		{URLPathEqualTo("/v1.users").IgnoreTrailingSlash(), "/v1xusers", false},
+		{URLEqualTo("/v1/users?page=2").IgnoreTrailingSlash(), "/v1/users/?page=2", true},
+		{URLEqualTo("/v1/users?page=2").IgnoreTrailingSlash(), "/v1/users?page=3", false},
+		{URLPathMatching("/v1/users/[0-9]+").IgnoreTrailingSlash(), "/v1/users/42/", true},
+	}
+
+	for _, test := range tests {
+		if matched := matchURL(test.matcher, test.url); matched != test.matched {
+			t.Errorf("%s %s against %s: expected matched %v", test.matcher.Strategy(), test.matcher.Value(), test.url, test.matched)
+		}
+	}
+
+	if matcher := URLMatching("/v1/.*").IgnoreTrailingSlash(); matcher != URLMatching("/v1/.*") {
+		t.Errorf("expected url pattern unchanged; got %s", matcher.Value())
+	}
+}
+
+func TestPathOf(t *testing.T) {
+	if path := PathOf("users", "a/b c", "50%"); path != "/users/a%2Fb%20c/50%25" {
+		t.Errorf("unexpected path %s", path)
+	}
+
+	pattern := PathPatternOf("files", "report.v1+final") + "/.+"
+	if !matchURL(URLPathMatching(pattern), "/files/report.v1+final/1") || matchURL(URLPathMatching(pattern), "/files/reportxv1final/1") {
+		t.Errorf("expected metacharacters of segments matched literally by %s", pattern)
+	}
+}
+
+func TestMultiValueMatchers(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/search")).
+		WithQueryParam("tag", HavingExactly(EqualTo("a"), Matching("b|c"))).
+		WithQueryParam("id", Count(2)).
+		WithQueryParam("flag", HavingExactly()).
+		WithQueryParam("debug", Count(0))
+
+	raw, err := stubRule.Request().MarshalJSON()
+	if err != nil {
+		t.Fatalf("Request MarshalJSON error: %v", err)
+	}
+	for _, expected := range []string{
+		`"tag":{"hasExactly":[{"equalTo":"a"},{"matches":"b|c"}]}`,
+		`"id":{"hasExactly":[{"matches":".*"},{"matches":".*"}]}`,
+		`"flag":{"hasExactly":[{"equalTo":""}]}`,
+		`"debug":{"absent":true}`,
+	} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected request to contain %s; got %s", expected, raw)
+		}
+	}
+
+	var decoded Request
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("Request UnmarshalJSON error: %v", err)
+	}
+	if matchers := decoded.QueryParams()["tag"].(ParamMatcher).ValueMatchers(); len(matchers) != 2 || matchers[1].Value() != "b|c" {
+		t.Errorf("unexpected decoded value matchers %+v", matchers)
+	}
+
+	for url, expected := range map[string]bool{
+		"/search?tag=c&tag=a&id=1&id=2&flag":       true,
+		"/search?tag=a&tag=a&id=1&id=2&flag":       false,
+		"/search?tag=a&tag=b&id=1&flag":            false,
+		"/search?tag=a&tag=b&id=1&id=2&flag=on":    false,
+		"/search?tag=a&tag=b&id=1&id=2&flag&debug": false,
+	} {
+		matched, known := stubRule.Match(&LoggedRequest{Method: "GET", URL: url})
+		if !known || matched != expected {
+			t.Errorf("%s: expected matched %v; got %v, %v", url, expected, matched, known)
+		}
+	}
+
+	including := Get(URLPathEqualTo("/search")).WithQueryParam("tag", Including(EqualTo("a")))
+	if matched, _ := including.Match(&LoggedRequest{Method: "GET", URL: "/search?tag=b&tag=a"}); !matched {
+		t.Error("expected request including the value matched")
+	}
+}
+
+func TestCombinedMatchers(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/trace")).
+		WithHeader("X-Trace", AllOf(Contains("foo"), Matching("abc.*"))).
+		WithHeader("X-Env", AnyOf(Absent(), EqualTo("qa")))
+
+	raw, err := stubRule.Request().MarshalJSON()
+	if err != nil {
+		t.Fatalf("Request MarshalJSON error: %v", err)
+	}
+	for _, expected := range []string{
+		`"X-Trace":{"and":[{"contains":"foo"},{"matches":"abc.*"}]}`,
+		`"X-Env":{"or":[{"absent":true},{"equalTo":"qa"}]}`,
+	} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected request to contain %s; got %s", expected, raw)
+		}
+	}
+
+	var decoded Request
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("Request UnmarshalJSON error: %v", err)
+	}
+	if matcher := decoded.Headers()["X-Trace"].(ParamMatcher); matcher.Strategy() != ParamAnd || len(matcher.ValueMatchers()) != 2 {
+		t.Errorf("unexpected decoded matcher %+v", matcher)
+	}
+
+	for _, test := range []struct {
+		headers map[string]string
+		matched bool
+	}{
+		{map[string]string{"X-Trace": "abc-foo"}, true},
+		{map[string]string{"X-Trace": "abc-foo", "X-Env": "qa"}, true},
+		{map[string]string{"X-Trace": "abc-foo", "X-Env": "prod"}, false},
+		{map[string]string{"X-Trace": "xyz-foo"}, false},
+	} {
+		matched, known := stubRule.Match(&LoggedRequest{Method: "GET", URL: "/trace", Headers: test.headers})
+		if !known || matched != test.matched {
+			t.Errorf("%v: expected matched %v; got %v, %v", test.headers, test.matched, matched, known)
+		}
+	}
+}
+
+func TestMultiValueCookies(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/cart")).
+		WithCookie("item", HavingExactly(EqualTo("apple"), EqualTo("pear"))).
+		WithCookie("session", Including(Matching("s-.*"))).
+		WithCookie("debug", Absent())
+
+	for body, expected := range map[string]bool{
+		`{"url":"/cart","method":"GET","cookies":{"item":["pear","apple"],"session":"s-1"}}`:                true,
+		`{"url":"/cart","method":"GET","cookies":{"item":["pear","apple","plum"],"session":"s-1"}}`:         false,
+		`{"url":"/cart","method":"GET","cookies":{"item":["pear","apple"],"session":"s-1","debug":"true"}}`: false,
+		`{"url":"/cart","method":"GET","cookies":{"item":"apple","session":"s-1"}}`:                         false,
+	} {
+		var request LoggedRequest
+		if err := json.Unmarshal([]byte(body), &request); err != nil {
+			t.Fatalf("LoggedRequest json.Unmarshal error: %v", err)
+		}
+		matched, known := stubRule.Match(&request)
+		if !known || matched != expected {
+			t.Errorf("%s: expected matched %v; got %v, %v", body, expected, matched, known)
+		}
+	}
+
+	var request LoggedRequest
+	if err := json.Unmarshal([]byte(`{"cookies":{"item":["pear","apple"]}}`), &request); err != nil {
+		t.Fatalf("LoggedRequest json.Unmarshal error: %v", err)
+	}
+	if cookie := request.Cookie("item"); cookie != "pear, apple" {
+		t.Errorf("expected joined cookie values; got %q", cookie)
+	}
+}
@@ -24,6 +24,15 @@ func NewMultipartPattern() *MultipartPattern {
 	}
 }
 
+// Clone returns a deep copy of MultipartPattern.
+func (m *MultipartPattern) Clone() *MultipartPattern {
+	return &MultipartPattern{
+		matchingType: m.matchingType,
+		headers:      cloneParamMatchers(m.headers),
+		bodyPatterns: append([]ParamMatcher(nil), m.bodyPatterns...),
+	}
+}
+
 func (m *MultipartPattern) WithName(name string) *MultipartPattern {
 	if m.headers == nil {
 		m.headers = map[string]ParamMatcherInterface{}
@@ -63,6 +72,7 @@ func (m *MultipartPattern) WithHeader(header string, matcher ParamMatcherInterfa
 }
 
 // MarshalJSON gives valid JSON or error.
+// The encoding is deterministic: object keys are always written in sorted order.
 func (m *MultipartPattern) MarshalJSON() ([]byte, error) {
 	multipart := map[string]interface{}{
 		"matchingType": m.matchingType,
@@ -71,30 +81,40 @@ func (m *MultipartPattern) MarshalJSON() ([]byte, error) {
 	if len(m.bodyPatterns) > 0 {
 		bodyPatterns := make([]map[string]interface{}, len(m.bodyPatterns))
 		for i, bodyPattern := range m.bodyPatterns {
-			bodyPatterns[i] = map[string]interface{}{
-				string(bodyPattern.Strategy()): bodyPattern.Value(),
-			}
-
-			for flag, value := range bodyPattern.flags {
-				bodyPatterns[i][flag] = value
-			}
+			bodyPatterns[i] = paramMatcherJSON(bodyPattern)
 		}
 		multipart["bodyPatterns"] = bodyPatterns
 	}
 
 	if len(m.headers) > 0 {
-		headers := make(map[string]map[string]interface{}, len(m.headers))
-		for key, header := range m.headers {
-			headers[key] = map[string]interface{}{
-				string(header.Strategy()): header.Value(),
-			}
-
-			for flag, value := range header.Flags() {
-				headers[key][flag] = value
-			}
-		}
-		multipart["headers"] = headers
+		multipart["headers"] = paramMatchersJSON(m.headers)
 	}
 
 	return json.Marshal(multipart)
 }
+
+// UnmarshalJSON fills MultipartPattern from WireMock JSON.
+func (m *MultipartPattern) UnmarshalJSON(data []byte) error {
+	jsonMultipart := struct {
+		MatchingType MultipartMatchingType   `json:"matchingType"`
+		Headers      map[string]ParamMatcher `json:"headers"`
+		BodyPatterns []ParamMatcher          `json:"bodyPatterns"`
+	}{}
+	if err := json.Unmarshal(data, &jsonMultipart); err != nil {
+		return err
+	}
+
+	*m = MultipartPattern{
+		matchingType: jsonMultipart.MatchingType,
+		bodyPatterns: jsonMultipart.BodyPatterns,
+	}
+	if m.matchingType == "" {
+		m.matchingType = MultipartMatchingTypeAny
+	}
+
+	for key, matcher := range jsonMultipart.Headers {
+		m.WithHeader(key, matcher)
+	}
+
+	return nil
+}
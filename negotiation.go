@@ -0,0 +1,86 @@
+package wiremock
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// A Representation is the variant of the resource in one media type, see Negotiate.
+type Representation struct {
+	mediaType string
+	body      string
+	headers   map[string]string
+}
+
+// NewRepresentation returns *Representation of the body in the media type.
+func NewRepresentation(mediaType, body string) *Representation {
+	return &Representation{
+		mediaType: mediaType,
+		body:      body,
+	}
+}
+
+// WithHeader adds the response header of the representation and returns *Representation
+func (r *Representation) WithHeader(key, value string) *Representation {
+	if r.headers == nil {
+		r.headers = map[string]string{}
+	}
+
+	r.headers[key] = value
+	return r
+}
+
+// MediaType is getter for media type
+func (r *Representation) MediaType() string {
+	return r.mediaType
+}
+
+// AcceptMatcher returns the Accept header matcher of the media type listed among others, ignoring its case and parameters,
+// so AcceptMatcher(ContentTypeXML) matches "application/json;q=0.9, application/xml".
+func AcceptMatcher(mediaType string) ParamMatcher {
+	return Matching(`(?i)(.*[,\s])?` + regexp.QuoteMeta(mediaType) + `\s*([;,].*)?`)
+}
+
+// Negotiate returns sibling copies of the stub, one per representation, differing only by the Accept header matcher
+// and returning the representation with its Content-Type and status.
+// The first representation is the default one: it is also returned, one priority lower,
+// to requests without the Accept header or accepting none of the media types, e.g. */*.
+func (s *StubRule) Negotiate(status int64, representations ...*Representation) []*StubRule {
+	if len(representations) == 0 {
+		return nil
+	}
+
+	stubs := make([]*StubRule, 0, len(representations)+1)
+	for _, representation := range representations {
+		stubs = append(stubs, s.Clone().
+			WithHeader("Accept", AcceptMatcher(representation.mediaType)).
+			willReturnRepresentation(representation, status))
+	}
+
+	stubs = append(stubs, s.Clone().
+		AtPriority(s.Priority()+1).
+		willReturnRepresentation(representations[0], status))
+
+	return stubs
+}
+
+func (s *StubRule) willReturnRepresentation(representation *Representation, status int64) *StubRule {
+	headers := make(map[string]string, len(representation.headers)+1)
+	for key, value := range representation.headers {
+		headers[key] = value
+	}
+	headers["Content-Type"] = representation.mediaType
+
+	return s.WillReturn(representation.body, headers, status)
+}
+
+// StubForNegotiated registers the stubs negotiating the representations, see Negotiate.
+func (c *Client) StubForNegotiated(stubRule *StubRule, status int64, representations ...*Representation) error {
+	for _, stub := range stubRule.Negotiate(status, representations...) {
+		if err := c.StubFor(stub); err != nil {
+			return fmt.Errorf("stub %s representation: %s", stub.Response().Headers()["Content-Type"], err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package wiremock
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	stubs := Get(URLPathEqualTo("/orders/1")).Negotiate(200,
+		NewRepresentation(ContentTypeJSON, `{"id":1}`),
+		NewRepresentation(ContentTypeXML, `<order id="1"/>`).WithHeader("X-Format", "xml"),
+	)
+	if len(stubs) != 3 {
+		t.Fatalf("expected stubs of two representations and the default one; got %d", len(stubs))
+	}
+
+	for accept, expected := range map[string]string{
+		"application/json":                        `{"id":1}`,
+		"application/json;q=0.5, application/xml": `<order id="1"/>`,
+		"text/html, */*":                          `{"id":1}`,
+		"":                                        `{"id":1}`,
+	} {
+		request := &LoggedRequest{Method: "GET", URL: "/orders/1"}
+		if accept != "" {
+			request.Headers = map[string]string{"Accept": accept}
+		}
+		match := MatchStub(stubs, request)
+		if match.Stub == nil || match.Stub.Response().Body() != expected {
+			t.Errorf("%q: expected %s; got %v", accept, expected, match.Stub)
+		}
+	}
+
+	if contentType := stubs[1].Response().Headers()["Content-Type"]; contentType != ContentTypeXML {
+		t.Errorf("expected xml content type; got %q", contentType)
+	}
+}
+
+func TestClient_StubForNegotiated(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	err := client.StubForNegotiated(Get(URLPathEqualTo("/orders/1")), 200,
+		NewRepresentation(ContentTypeJSON, `{"id":1}`),
+		NewRepresentation(ContentTypeXML, `<order id="1"/>`),
+	)
+	if err != nil {
+		t.Fatalf("StubForNegotiated error: %v", err)
+	}
+	if count := server.mappingCount(); count != 3 {
+		t.Errorf("expected 3 stubs; got %d", count)
+	}
+}
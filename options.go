@@ -0,0 +1,108 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// A JSONCodec encodes admin API request bodies and decodes responses.
+// It is compatible with encoding/json, e.g. jsoniter.ConfigCompatibleWithStandardLibrary.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type standardJSON struct{}
+
+func (standardJSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (standardJSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// A ClientOption configures Client.
+type ClientOption func(c *Client)
+
+// WithJSONCodec sets the codec of admin API bodies, encoding/json by default.
+// Stubs and request patterns are passed to the codec as plain values, so it encodes them entirely,
+// while decoded stubs and serve events still go through their encoding/json based UnmarshalJSON.
+func WithJSONCodec(codec JSONCodec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// WithGzipRequests makes bodies of bulk calls, e.g. ImportStubs, of at least minSize bytes gzip compressed,
+// cutting setup time of big fixture sets over slow networks.
+func WithGzipRequests(minSize int) ClientOption {
+	return func(c *Client) {
+		c.gzipMinSize = minSize
+	}
+}
+
+// WithHTTPClient sets the http client of admin API requests, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the number of kept alive connections to the server.
+// Raise it when registering stubs in parallel, the net/http default of 2 makes bursts open new connections.
+// Like the other transport options, it applies to a copy of the *http.Transport of the http client or
+// of http.DefaultTransport, and is ignored for other http.RoundTripper implementations.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.tuneTransport(func(transport *http.Transport) {
+			transport.MaxIdleConnsPerHost = n
+		})
+	}
+}
+
+// WithMaxConnsPerHost limits the number of connections to the server, making excess requests wait.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.tuneTransport(func(transport *http.Transport) {
+			transport.MaxConnsPerHost = n
+		})
+	}
+}
+
+// WithIdleConnTimeout sets how long kept alive connections to the server stay idle before closing.
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.tuneTransport(func(transport *http.Transport) {
+			transport.IdleConnTimeout = timeout
+		})
+	}
+}
+
+func (c *Client) tuneTransport(tune func(transport *http.Transport)) {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	transport = transport.Clone()
+	// a custom TLS config disables HTTP/2 unless forced
+	transport.ForceAttemptHTTP2 = true
+	tune(transport)
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
+
+// drainAndClose reads the rest of the response body, so the connection is reused, and closes it.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, body)
+	_ = body.Close()
+}
@@ -0,0 +1,107 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingCodec is encoding/json recording the values it encodes.
+type recordingCodec struct {
+	standardJSON
+	encoded []interface{}
+}
+
+func (c *recordingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.encoded = append(c.encoded, v)
+	return c.standardJSON.Marshal(v)
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	server := newFakeServer(t)
+	codec := &recordingCodec{}
+	client := NewClient(server.URL, WithJSONCodec(codec))
+
+	stubRule := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	if len(codec.encoded) != 1 {
+		t.Fatalf("expected stub to be encoded by codec; got %v", codec.encoded)
+	}
+	if _, ok := codec.encoded[0].(json.Marshaler); ok {
+		t.Errorf("expected codec to get plain values; got %T", codec.encoded[0])
+	}
+
+	registered, err := client.GetStub(stubRule.UUID())
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if !registered.Equal(stubRule) {
+		t.Errorf("expected registered stub equal to %s; got %s", stubRule, registered)
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	var connections int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "registered stub with some body to drain"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(server.URL, WithMaxIdleConnsPerHost(16), WithIdleConnTimeout(time.Minute))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok || transport.MaxIdleConnsPerHost != 16 || transport.IdleConnTimeout != time.Minute || transport == http.DefaultTransport {
+		t.Fatalf("expected tuned copy of the default transport; got %#v", client.httpClient.Transport)
+	}
+	if http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost == 16 {
+		t.Error("expected http.DefaultTransport to stay untouched")
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := client.StubFor(Get(URLPathEqualTo("/orders"))); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+	if connections := atomic.LoadInt64(&connections); connections != 1 {
+		t.Errorf("expected sequential registrations to reuse the connection; got %d connections", connections)
+	}
+}
+
+// roundTripFunc is http.RoundTripper of a function.
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var paths []string
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	client := NewClient("http://wiremock", WithHTTPClient(httpClient), WithMaxIdleConnsPerHost(16))
+	if err := client.Reset(); err != nil {
+		t.Fatalf("Reset error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/__admin/mappings/reset" {
+		t.Errorf("expected request through custom http client; got %v", paths)
+	}
+}
@@ -0,0 +1,128 @@
+package wiremock
+
+// proxyResponse is the WireMock JSON of the response proxied to another server, embedded in the response JSON.
+type proxyResponse struct {
+	ProxyBaseURL                  string            `json:"proxyBaseUrl,omitempty"`
+	ProxyURLPrefixToRemove        string            `json:"proxyUrlPrefixToRemove,omitempty"`
+	AdditionalProxyRequestHeaders map[string]string `json:"additionalProxyRequestHeaders,omitempty"`
+	RemoveProxyRequestHeaders     []string          `json:"removeProxyRequestHeaders,omitempty"`
+}
+
+func (p *proxyResponse) clone() *proxyResponse {
+	clone := *p
+	if p.AdditionalProxyRequestHeaders != nil {
+		clone.AdditionalProxyRequestHeaders = make(map[string]string, len(p.AdditionalProxyRequestHeaders))
+		for key, value := range p.AdditionalProxyRequestHeaders {
+			clone.AdditionalProxyRequestHeaders[key] = value
+		}
+	}
+	if p.RemoveProxyRequestHeaders != nil {
+		clone.RemoveProxyRequestHeaders = append([]string(nil), p.RemoveProxyRequestHeaders...)
+	}
+
+	return &clone
+}
+
+// WillProxyTo makes matched requests proxied to the server at baseURL, e.g. "https://api.example.com",
+// and returns *StubRule. The response is the one of the server, the status and body of the stub are ignored.
+// HTTPS servers must be trusted by WireMock, see ProxyTrust.
+func (s *StubRule) WillProxyTo(baseURL string) *StubRule {
+	s.proxy().ProxyBaseURL = baseURL
+	return s
+}
+
+// WithProxyURLPrefixToRemove strips prefix from the request path before proxying and returns *StubRule
+func (s *StubRule) WithProxyURLPrefixToRemove(prefix string) *StubRule {
+	s.proxy().ProxyURLPrefixToRemove = prefix
+	return s
+}
+
+// WithProxyRequestHeader adds header to the proxied request and returns *StubRule
+func (s *StubRule) WithProxyRequestHeader(key, value string) *StubRule {
+	proxy := s.proxy()
+	if proxy.AdditionalProxyRequestHeaders == nil {
+		proxy.AdditionalProxyRequestHeaders = map[string]string{}
+	}
+	proxy.AdditionalProxyRequestHeaders[key] = value
+
+	return s
+}
+
+// WithoutProxyRequestHeaders removes headers from the proxied request and returns *StubRule
+func (s *StubRule) WithoutProxyRequestHeaders(keys ...string) *StubRule {
+	proxy := s.proxy()
+	proxy.RemoveProxyRequestHeaders = append(proxy.RemoveProxyRequestHeaders, keys...)
+
+	return s
+}
+
+func (s *StubRule) proxy() *proxyResponse {
+	if s.response.proxy == nil {
+		s.response.proxy = &proxyResponse{}
+	}
+
+	return s.response.proxy
+}
+
+// ProxyBaseURL is getter for the base url of the server requests are proxied to, empty when not proxied
+func (r *Response) ProxyBaseURL() string {
+	if r.proxy == nil {
+		return ""
+	}
+
+	return r.proxy.ProxyBaseURL
+}
+
+// ProxyTrust is the trust configuration of proxy targets.
+// WireMock does not accept it per stub or through the settings API, it is read on startup only,
+// so ProxyTrust renders the startup arguments, e.g. for a container launcher.
+type ProxyTrust struct {
+	trustAll     bool
+	trustedHosts []string
+	trustStore   string
+	password     string
+}
+
+// NewProxyTrust returns *ProxyTrust trusting the targets with certificates of the JVM trusted CAs only.
+func NewProxyTrust() *ProxyTrust {
+	return &ProxyTrust{}
+}
+
+// WithTrustAll makes every target trusted, e.g. ones with self-signed certificates, and returns *ProxyTrust
+func (t *ProxyTrust) WithTrustAll() *ProxyTrust {
+	t.trustAll = true
+	return t
+}
+
+// WithTrustedHosts makes targets of hosts trusted whatever their certificates and returns *ProxyTrust
+func (t *ProxyTrust) WithTrustedHosts(hosts ...string) *ProxyTrust {
+	t.trustedHosts = append(t.trustedHosts, hosts...)
+	return t
+}
+
+// WithClientCertificate sets the trust store WireMock presents the client certificates of to mTLS targets
+// and returns *ProxyTrust. The path is the one on the WireMock host.
+func (t *ProxyTrust) WithClientCertificate(trustStorePath, password string) *ProxyTrust {
+	t.trustStore = trustStorePath
+	t.password = password
+	return t
+}
+
+// Args gives WireMock startup arguments of the trust configuration.
+func (t *ProxyTrust) Args() []string {
+	var args []string
+	if t.trustAll {
+		args = append(args, "--trust-all-proxy-targets")
+	}
+	for _, host := range t.trustedHosts {
+		args = append(args, "--trust-proxy-target="+host)
+	}
+	if t.trustStore != "" {
+		args = append(args, "--https-truststore="+t.trustStore)
+		if t.password != "" {
+			args = append(args, "--truststore-password="+t.password)
+		}
+	}
+
+	return args
+}
@@ -0,0 +1,67 @@
+package wiremock
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStubRule_WillProxyTo(t *testing.T) {
+	stubRule := Get(URLPathMatching("/payments/.*")).
+		WillProxyTo("https://payments.example.com").
+		WithProxyURLPrefixToRemove("/payments").
+		WithProxyRequestHeader("Authorization", "Bearer upstream").
+		WithoutProxyRequestHeaders("Cookie")
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	for _, expected := range []string{
+		`"proxyBaseUrl":"https://payments.example.com"`,
+		`"proxyUrlPrefixToRemove":"/payments"`,
+		`"additionalProxyRequestHeaders":{"Authorization":"Bearer upstream"}`,
+		`"removeProxyRequestHeaders":["Cookie"]`,
+	} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected stub to contain %s; got %s", expected, raw)
+		}
+	}
+
+	var decoded StubRule
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("StubRule UnmarshalJSON error: %v", err)
+	}
+	if !decoded.Equal(stubRule) || decoded.Response().ProxyBaseURL() != "https://payments.example.com" {
+		t.Errorf("expected decoded stub equal to the original; got %s", decoded.String())
+	}
+
+	clone := stubRule.Clone().WithProxyRequestHeader("Authorization", "Bearer other")
+	if stubRule.response.proxy.AdditionalProxyRequestHeaders["Authorization"] != "Bearer upstream" {
+		t.Error("expected clone not to share proxy headers")
+	}
+	if clone.Response().ProxyBaseURL() != stubRule.Response().ProxyBaseURL() {
+		t.Error("expected clone to keep the proxy base url")
+	}
+}
+
+func TestProxyTrust_Args(t *testing.T) {
+	args := NewProxyTrust().
+		WithTrustedHosts("payments.internal", "ledger.internal").
+		WithClientCertificate("/certs/client.jks", "secret").
+		Args()
+
+	expected := []string{
+		"--trust-proxy-target=payments.internal",
+		"--trust-proxy-target=ledger.internal",
+		"--https-truststore=/certs/client.jks",
+		"--truststore-password=secret",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v; got %v", expected, args)
+	}
+
+	if args := NewProxyTrust().WithTrustAll().Args(); !reflect.DeepEqual(args, []string{"--trust-all-proxy-targets"}) {
+		t.Errorf("unexpected trust all args %v", args)
+	}
+}
@@ -0,0 +1,56 @@
+package wiremock
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// WithRawQuery matches the whole query string, without the leading "?", by matcher and returns *Request.
+// Supported matchers are EqualTo, EqualToIgnoreCase, Matching, Contains and Absent, which means no query at all.
+// WireMock has no query string matcher, so the url matcher and the query matcher are combined into
+// the urlPattern, and the url must be matched by URLPathEqualTo, URLPathMatching or none.
+// Query parameter matchers keep working alongside.
+func (r *Request) WithRawQuery(matcher ParamMatcher) *Request {
+	r.rawQuery = &matcher
+	return r
+}
+
+// WithRawQuery matches the whole query string by matcher and returns *StubRule, see Request.WithRawQuery.
+func (s *StubRule) WithRawQuery(matcher ParamMatcher) *StubRule {
+	s.request.WithRawQuery(matcher)
+	return s
+}
+
+// rawQueryURLMatcher gives the urlPattern of the path matched by urlMatcher and the query matched by rawQuery.
+func rawQueryURLMatcher(urlMatcher URLMatcherInterface, rawQuery ParamMatcher) (URLMatcher, error) {
+	path := "[^?]*"
+	if urlMatcher != nil {
+		switch urlMatcher.Strategy() {
+		case URLPathEqualToRule:
+			path = regexp.QuoteMeta(urlMatcher.Value())
+		case URLPathMatchingRule:
+			path = "(?:" + urlMatcher.Value() + ")"
+		default:
+			return URLMatcher{}, fmt.Errorf("rawQuery: %s matches the query itself, match the path by urlPath or urlPathPattern", urlMatcher.Strategy())
+		}
+	}
+
+	var query string
+	switch rawQuery.Strategy() {
+	case ParamAbsent:
+		return URLMatching(path), nil
+	case ParamEqualTo:
+		query = regexp.QuoteMeta(rawQuery.Value())
+		if rawQuery.Flags()["caseInsensitive"] {
+			query = "(?i:" + query + ")"
+		}
+	case ParamMatches:
+		query = "(?:" + rawQuery.Value() + ")"
+	case ParamContains:
+		query = ".*" + regexp.QuoteMeta(rawQuery.Value()) + ".*"
+	default:
+		return URLMatcher{}, fmt.Errorf("rawQuery: unsupported %s matcher", rawQuery.Strategy())
+	}
+
+	return URLMatching(path + `\?` + query), nil
+}
@@ -0,0 +1,81 @@
+package wiremock
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// A RateLimit builds stubs rejecting the first calls to the stub with 429 Too Many Requests and Retry-After header,
+// then serving the stub, so client side rate limit handling can be tested.
+//
+// WireMock has no clock of the stub calls, so windows of the rate limit are counted in calls:
+// with WithAllowedCalls the stub is served that many times, then the calls are rejected again, as in the next window.
+type RateLimit struct {
+	stubRule     *StubRule
+	rejected     int
+	allowed      int
+	retryAfter   time.Duration
+	scenarioName string
+}
+
+// NewRateLimit returns *RateLimit rejecting the first rejected calls to stubRule with Retry-After of 1 second.
+func NewRateLimit(stubRule *StubRule, rejected int) *RateLimit {
+	return &RateLimit{
+		stubRule:     stubRule,
+		rejected:     rejected,
+		retryAfter:   time.Second,
+		scenarioName: "rate-limit-" + stubRule.UUID(),
+	}
+}
+
+// WithRetryAfter sets Retry-After of rejected calls, rounded up to seconds, and returns *RateLimit
+func (r *RateLimit) WithRetryAfter(retryAfter time.Duration) *RateLimit {
+	r.retryAfter = retryAfter
+	return r
+}
+
+// WithAllowedCalls sets calls served in every window and returns *RateLimit.
+// Zero, the default, serves all calls after the rejected ones.
+func (r *RateLimit) WithAllowedCalls(allowed int) *RateLimit {
+	r.allowed = allowed
+	return r
+}
+
+// WithScenarioName sets scenario of the calls and returns *RateLimit
+func (r *RateLimit) WithScenarioName(scenarioName string) *RateLimit {
+	r.scenarioName = scenarioName
+	return r
+}
+
+// Scenario returns *Scenario of rejected and served calls.
+func (r *RateLimit) Scenario() (*Scenario, error) {
+	if r.rejected < 0 || r.allowed < 0 {
+		return nil, fmt.Errorf("rate limit: rejected %d and allowed %d calls must not be negative", r.rejected, r.allowed)
+	}
+
+	rejection := r.stubRule.Clone()
+	rejection.response = Response{status: http.StatusOK}
+	rejection.WillReturn("", map[string]string{
+		"Retry-After": strconv.FormatInt(int64(math.Ceil(r.retryAfter.Seconds())), 10),
+	}, http.StatusTooManyRequests)
+
+	scenario := NewScenario(r.scenarioName).ThenTimes(r.rejected, rejection)
+	if r.allowed == 0 {
+		return scenario.Then(r.stubRule), nil
+	}
+
+	return scenario.ThenTimes(r.allowed, r.stubRule).Looping(), nil
+}
+
+// StubRateLimit registers stubs of the rate limit.
+func (c *Client) StubRateLimit(rateLimit *RateLimit) error {
+	scenario, err := rateLimit.Scenario()
+	if err != nil {
+		return err
+	}
+
+	return c.StubScenario(scenario)
+}
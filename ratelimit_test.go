@@ -0,0 +1,39 @@
+package wiremock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_Scenario(t *testing.T) {
+	base := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, http.StatusOK)
+
+	scenario, err := NewRateLimit(base, 2).WithRetryAfter(1500 * time.Millisecond).Scenario()
+	if err != nil {
+		t.Fatalf("Scenario error: %v", err)
+	}
+	stubs := scenario.Stubs()
+	if len(stubs) != 3 || stubs[2] != base || stubs[2].newScenarioState != nil {
+		t.Fatalf("expected 2 rejected calls, then served ones; got %v", stubs)
+	}
+	for _, stubRule := range stubs[:2] {
+		response := stubRule.Response()
+		if response.Status() != http.StatusTooManyRequests || response.Headers()["Retry-After"] != "2" || response.Body() != "" {
+			t.Errorf("unexpected rejected call %s", stubRule)
+		}
+	}
+
+	scenario, err = NewRateLimit(base, 1).WithAllowedCalls(2).Scenario()
+	if err != nil {
+		t.Fatalf("Scenario error: %v", err)
+	}
+	stubs = scenario.Stubs()
+	if len(stubs) != 3 || stubs[0].Response().Status() != http.StatusTooManyRequests || *stubs[2].newScenarioState != ScenarioStateStarted {
+		t.Errorf("expected window of 1 rejected and 2 served calls; got %v", stubs)
+	}
+
+	if _, err := NewRateLimit(base, -1).Scenario(); err == nil {
+		t.Error("expected error of negative rejected calls")
+	}
+}
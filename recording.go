@@ -0,0 +1,121 @@
+package wiremock
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+const wiremockAdminRecordingsURN = "__admin/recordings"
+
+// FallbackPriority is the priority of the proxy stub registered by RecordWithFallback,
+// low enough for explicit stubs of any usual priority to win.
+const FallbackPriority int64 = 1000
+
+// StartRecording starts recording of requests proxied to targetBaseURL.
+// WireMock registers the stub proxying all requests to the target, which wins over the stubs registered before it
+// with the default priority, see RecordWithFallback to keep explicit stubs served.
+// An empty targetBaseURL records requests proxied by the proxy stubs already registered.
+func (c *Client) StartRecording(targetBaseURL string) error {
+	spec := map[string]interface{}{}
+	if targetBaseURL != "" {
+		spec["targetBaseUrl"] = targetBaseURL
+	}
+
+	requestBody, err := c.codec.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("start recording: build error: %s", err.Error())
+	}
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/start", c.url, wiremockAdminRecordingsURN), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("start recording: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("start recording: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("start recording: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// StopRecording stops recording and gives the stubs recorded from the proxied requests,
+// which WireMock registers as well.
+func (c *Client) StopRecording() ([]*StubRule, error) {
+	defer c.cache.invalidate()
+
+	res, err := c.httpClient.Post(fmt.Sprintf("%s/%s/stop", c.url, wiremockAdminRecordingsURN), "application/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("stop recording: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("stop recording: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("stop recording: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var recordingResponse struct {
+		Mappings []*StubRule `json:"mappings"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &recordingResponse); err != nil {
+		return nil, fmt.Errorf("stop recording: read json error: %s", err.Error())
+	}
+
+	return recordingResponse.Mappings, nil
+}
+
+// A Recording is the recording of requests passed through to the real backend, started by RecordWithFallback.
+type Recording struct {
+	client *Client
+	proxy  *StubRule
+}
+
+// Proxy is getter for the fallback proxy stub
+func (r *Recording) Proxy() *StubRule {
+	return r.proxy
+}
+
+// Stop stops the recording, deletes the fallback proxy stub and gives the recorded stubs.
+func (r *Recording) Stop() ([]*StubRule, error) {
+	recorded, err := r.client.StopRecording()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.client.DeleteStub(r.proxy); err != nil {
+		return recorded, fmt.Errorf("delete fallback proxy: %s", err.Error())
+	}
+
+	return recorded, nil
+}
+
+// RecordWithFallback sets up the hybrid mock: requests matched by no explicit stub are proxied to targetBaseURL
+// by the stub of FallbackPriority and recorded, while explicit stubs registered before or after still win.
+func (c *Client) RecordWithFallback(targetBaseURL string) (*Recording, error) {
+	proxy := NewStubRule(MethodAny, URLMatching(".*")).
+		AtPriority(FallbackPriority).
+		WillProxyTo(targetBaseURL)
+	if err := c.StubFor(proxy); err != nil {
+		return nil, fmt.Errorf("record with fallback: %s", err.Error())
+	}
+
+	// without the target WireMock records the proxied requests without registering its own proxy stub
+	if err := c.StartRecording(""); err != nil {
+		_ = c.DeleteStub(proxy)
+		return nil, fmt.Errorf("record with fallback: %s", err.Error())
+	}
+
+	return &Recording{client: c, proxy: proxy}, nil
+}
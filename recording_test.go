@@ -0,0 +1,46 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClient_RecordWithFallback(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	recorded := Get(URLEqualTo("/users/1")).WillReturn(`{"id":1}`, nil, 200)
+	raw, _ := recorded.MarshalJSON()
+	server.recorded = append(server.recorded, raw)
+
+	recording, err := client.RecordWithFallback("https://api.example.com")
+	if err != nil {
+		t.Fatalf("RecordWithFallback error: %v", err)
+	}
+	if string(server.recording) != "{}" {
+		t.Errorf("expected recording without target; got %s", server.recording)
+	}
+
+	proxy, err := client.GetStub(recording.Proxy().UUID())
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if proxy.Priority() != FallbackPriority || proxy.Response().ProxyBaseURL() != "https://api.example.com" {
+		t.Errorf("unexpected fallback proxy %s", proxy)
+	}
+
+	stubs, err := recording.Stop()
+	if err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+	if len(stubs) != 1 || stubs[0].UUID() != recorded.UUID() {
+		t.Errorf("expected recorded stub %s; got %v", recorded.UUID(), stubs)
+	}
+	if server.mappingCount() != 0 {
+		t.Errorf("expected fallback proxy deleted; got %d mappings", server.mappingCount())
+	}
+
+	if _, err := client.StopRecording(); err == nil || !strings.HasPrefix(err.Error(), "stop recording: bad response status: 404") {
+		t.Errorf("expected stop error when not recording; got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package wiremock
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+// A ReplayedRequest is the logged request replayed against a handler.
+type ReplayedRequest struct {
+	Request LoggedRequest
+	// Original is the response WireMock sent to the request.
+	Original LoggedResponse
+	// Response is the response of the handler.
+	Response *http.Response
+}
+
+// HTTPRequest returns *http.Request equal to the logged one: method, url, headers and body.
+// Multiple values of a header are sent joined, the way the journal keeps them.
+func (r *LoggedRequest) HTTPRequest() (*http.Request, error) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range r.Headers {
+		req.Header.Set(key, value)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	req.RemoteAddr = r.ClientIP
+
+	return req, nil
+}
+
+// ReplayJournal fetches serve events matching query and replays their requests against handler in the order
+// WireMock received them, so a new implementation can be checked against the traffic the old dependency got.
+// A nil query replays the whole journal.
+func (c *Client) ReplayJournal(handler http.Handler, query *ServeEventQuery) ([]ReplayedRequest, error) {
+	events, err := c.GetServeEvents(query)
+	if err != nil {
+		return nil, fmt.Errorf("replay journal: %s", err.Error())
+	}
+
+	replayed := make([]ReplayedRequest, 0, len(events))
+	// the journal lists the most recent events first
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		req, err := event.Request.HTTPRequest()
+		if err != nil {
+			return replayed, fmt.Errorf("replay journal: request %s: %s", event.ID, err.Error())
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		replayed = append(replayed, ReplayedRequest{
+			Request:  event.Request,
+			Original: event.Response,
+			Response: recorder.Result(),
+		})
+	}
+
+	return replayed, nil
+}
@@ -0,0 +1,47 @@
+package wiremock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestClient_ReplayJournal(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	server.logServeEvent(t, map[string]interface{}{
+		"id":       "e1",
+		"request":  map[string]interface{}{"url": "/orders", "method": "POST", "headers": map[string]interface{}{"Content-Type": "application/json"}, "body": `{"id":1}`},
+		"response": map[string]interface{}{"status": 201},
+	})
+	server.logServeEvent(t, map[string]interface{}{
+		"id":       "e2",
+		"request":  map[string]interface{}{"url": "/orders/1?expand=items", "method": "GET"},
+		"response": map[string]interface{}{"status": 200, "body": `{"id":1}`},
+	})
+
+	var received []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+
+	replayed, err := client.ReplayJournal(handler, nil)
+	if err != nil {
+		t.Fatalf("ReplayJournal error: %v", err)
+	}
+
+	expected := []string{`POST /orders application/json {"id":1}`, "GET /orders/1?expand=items  "}
+	if len(received) != len(expected) || received[0] != expected[0] || received[1] != expected[1] {
+		t.Fatalf("expected requests replayed oldest first %q; got %q", expected, received)
+	}
+	for _, r := range replayed {
+		if int64(r.Response.StatusCode) != r.Original.Status {
+			t.Errorf("%s %s: expected status %d; got %d", r.Request.Method, r.Request.URL, r.Original.Status, r.Response.StatusCode)
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package wiremock
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
 )
 
 // A Request is the part of StubRule describing the matching of the http request
 type Request struct {
 	urlMatcher           URLMatcherInterface
+	rawQuery             *ParamMatcher
 	method               string
 	headers              map[string]ParamMatcherInterface
 	queryParams          map[string]ParamMatcherInterface
@@ -27,6 +33,103 @@ func NewRequest(method string, urlMatcher URLMatcherInterface) *Request {
 	}
 }
 
+// RequestFromHTTP returns *Request matching exactly the captured request: its method, url with query,
+// headers and body are equalTo matchers. Headers with several values match exactly these values, see HavingExactly.
+// The body is read and put back, so r can still be served or sent. Errors reading the body are returned.
+func RequestFromHTTP(r *http.Request) (*Request, error) {
+	request := NewRequest(r.Method, URLEqualTo(r.URL.RequestURI()))
+	for key, values := range r.Header {
+		switch {
+		case len(values) == 1:
+			request.WithHeader(key, EqualTo(values[0]))
+		case len(values) > 1:
+			matchers := make([]ParamMatcher, len(values))
+			for i, value := range values {
+				matchers[i] = EqualTo(value)
+			}
+			request.WithHeader(key, HavingExactly(matchers...))
+		}
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %s", err.Error())
+		}
+		if len(body) > 0 {
+			request.WithBodyPattern(EqualTo(string(body)))
+		}
+	}
+
+	return request, nil
+}
+
+// Clone returns a deep copy of Request.
+// Matchers are immutable values, so they are shared between copies.
+func (r *Request) Clone() *Request {
+	clone := &Request{
+		urlMatcher:   r.urlMatcher,
+		rawQuery:     r.rawQuery,
+		method:       r.method,
+		headers:      cloneParamMatchers(r.headers),
+		queryParams:  cloneParamMatchers(r.queryParams),
+		cookies:      cloneParamMatchers(r.cookies),
+		bodyPatterns: append([]ParamMatcher(nil), r.bodyPatterns...),
+	}
+
+	if r.multipartPatterns != nil {
+		clone.multipartPatterns = make([]*MultipartPattern, len(r.multipartPatterns))
+		for i, pattern := range r.multipartPatterns {
+			clone.multipartPatterns[i] = pattern.Clone()
+		}
+	}
+
+	if r.basicAuthCredentials != nil {
+		credentials := *r.basicAuthCredentials
+		clone.basicAuthCredentials = &credentials
+	}
+
+	return clone
+}
+
+// Method is getter for http verb
+func (r *Request) Method() string {
+	return r.method
+}
+
+// URLMatcher is getter for url matcher, combined with the raw query matcher when set
+func (r *Request) URLMatcher() URLMatcherInterface {
+	if r.rawQuery != nil {
+		if urlMatcher, err := rawQueryURLMatcher(r.urlMatcher, *r.rawQuery); err == nil {
+			return urlMatcher
+		}
+	}
+
+	return r.urlMatcher
+}
+
+// Headers is getter for header matchers
+func (r *Request) Headers() map[string]ParamMatcherInterface {
+	return r.headers
+}
+
+// QueryParams is getter for query param matchers
+func (r *Request) QueryParams() map[string]ParamMatcherInterface {
+	return r.queryParams
+}
+
+// Cookies is getter for cookie matchers
+func (r *Request) Cookies() map[string]ParamMatcherInterface {
+	return r.cookies
+}
+
+// BodyPatterns is getter for body patterns
+func (r *Request) BodyPatterns() []ParamMatcher {
+	return r.bodyPatterns
+}
+
 // WithMethod is fluent-setter for http verb
 func (r *Request) WithMethod(method string) *Request {
 	r.method = method
@@ -63,6 +166,30 @@ func (r *Request) WithBasicAuth(username, password string) *Request {
 	return r
 }
 
+// WithBearerToken adds the Authorization header matcher of the bearer token, the "Bearer " prefix is prepended for you.
+// The equalTo, matches, doesNotMatch and contains matchers check the token itself,
+// other matchers check the whole header value, which must start with the prefix.
+func (r *Request) WithBearerToken(matcher ParamMatcher) *Request {
+	return r.WithHeader("Authorization", bearerTokenMatcher(matcher))
+}
+
+func bearerTokenMatcher(matcher ParamMatcher) ParamMatcher {
+	const prefix = "Bearer "
+	switch matcher.strategy {
+	case ParamEqualTo:
+		matcher.value = prefix + matcher.value
+		return matcher
+	case ParamMatches:
+		return Matching(prefix + "(?:" + matcher.value + ")")
+	case ParamDoesNotMatch:
+		return AllOf(Matching(prefix+".*"), NotMatching(prefix+"(?:"+matcher.value+")"))
+	case ParamContains:
+		return Matching(prefix + ".*" + regexp.QuoteMeta(matcher.value) + ".*")
+	}
+
+	return AllOf(Matching(prefix+".*"), matcher)
+}
+
 // WithQueryParam add param to query param list
 func (r *Request) WithQueryParam(param string, matcher ParamMatcherInterface) *Request {
 	if r.queryParams == nil {
@@ -83,7 +210,8 @@ func (r *Request) WithHeader(header string, matcher ParamMatcherInterface) *Requ
 	return r
 }
 
-// WithCookie is fluent-setter for cookie
+// WithCookie is fluent-setter for cookie.
+// The cookie sent several times is matched like the multi-value query parameter, see HavingExactly and Including.
 func (r *Request) WithCookie(cookie string, matcher ParamMatcherInterface) *Request {
 	if r.cookies == nil {
 		r.cookies = map[string]ParamMatcherInterface{}
@@ -94,21 +222,23 @@ func (r *Request) WithCookie(cookie string, matcher ParamMatcherInterface) *Requ
 }
 
 // MarshalJSON gives valid JSON or error.
+// The encoding is deterministic: object keys are always written in sorted order.
 func (r *Request) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.jsonValue())
+}
+
+// jsonValue gives the JSON representation of Request made of plain values, so any JSON encoder can render it.
+func (r *Request) jsonValue() map[string]interface{} {
 	request := map[string]interface{}{
-		"method":                        r.method,
-		string(r.urlMatcher.Strategy()): r.urlMatcher.Value(),
+		"method": r.method,
+	}
+	if urlMatcher := r.URLMatcher(); urlMatcher != nil {
+		request[string(urlMatcher.Strategy())] = urlMatcher.Value()
 	}
 	if len(r.bodyPatterns) > 0 {
 		bodyPatterns := make([]map[string]interface{}, len(r.bodyPatterns))
 		for i, bodyPattern := range r.bodyPatterns {
-			bodyPatterns[i] = map[string]interface{}{
-				string(bodyPattern.Strategy()): bodyPattern.Value(),
-			}
-
-			for flag, value := range bodyPattern.flags {
-				bodyPatterns[i][flag] = value
-			}
+			bodyPatterns[i] = paramMatcherJSON(bodyPattern)
 		}
 		request["bodyPatterns"] = bodyPatterns
 	}
@@ -116,43 +246,13 @@ func (r *Request) MarshalJSON() ([]byte, error) {
 		request["multipartPatterns"] = r.multipartPatterns
 	}
 	if len(r.headers) > 0 {
-		headers := make(map[string]map[string]interface{}, len(r.headers))
-		for key, header := range r.headers {
-			headers[key] = map[string]interface{}{
-				string(header.Strategy()): header.Value(),
-			}
-
-			for flag, value := range header.Flags() {
-				headers[key][flag] = value
-			}
-		}
-		request["headers"] = headers
+		request["headers"] = paramMatchersJSON(r.headers)
 	}
 	if len(r.cookies) > 0 {
-		cookies := make(map[string]map[string]interface{}, len(r.cookies))
-		for key, cookie := range r.cookies {
-			cookies[key] = map[string]interface{}{
-				string(cookie.Strategy()): cookie.Value(),
-			}
-
-			for flag, value := range cookie.Flags() {
-				cookies[key][flag] = value
-			}
-		}
-		request["cookies"] = cookies
+		request["cookies"] = paramMatchersJSON(r.cookies)
 	}
 	if len(r.queryParams) > 0 {
-		params := make(map[string]map[string]interface{}, len(r.queryParams))
-		for key, param := range r.queryParams {
-			params[key] = map[string]interface{}{
-				string(param.Strategy()): param.Value(),
-			}
-
-			for flag, value := range param.Flags() {
-				params[key][flag] = value
-			}
-		}
-		request["queryParameters"] = params
+		request["queryParameters"] = paramMatchersJSON(r.queryParams)
 	}
 
 	if r.basicAuthCredentials != nil {
@@ -162,5 +262,84 @@ func (r *Request) MarshalJSON() ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(request)
+	return request
+}
+
+// PrettyJSON gives indented JSON of Request.
+func (r *Request) PrettyJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String gives indented JSON of Request, suitable for logs and error messages.
+func (r *Request) String() string {
+	result, err := r.PrettyJSON()
+	if err != nil {
+		return fmt.Sprintf("<invalid request: %s>", err.Error())
+	}
+
+	return string(result)
+}
+
+// UnmarshalJSON fills Request from WireMock JSON.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	jsonRequest := struct {
+		Method               string                  `json:"method"`
+		URL                  *string                 `json:"url"`
+		URLPath              *string                 `json:"urlPath"`
+		URLPathPattern       *string                 `json:"urlPathPattern"`
+		URLPattern           *string                 `json:"urlPattern"`
+		Headers              map[string]ParamMatcher `json:"headers"`
+		QueryParameters      map[string]ParamMatcher `json:"queryParameters"`
+		Cookies              map[string]ParamMatcher `json:"cookies"`
+		BodyPatterns         []ParamMatcher          `json:"bodyPatterns"`
+		MultipartPatterns    []*MultipartPattern     `json:"multipartPatterns"`
+		BasicAuthCredentials *struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"basicAuthCredentials"`
+	}{}
+	if err := json.Unmarshal(data, &jsonRequest); err != nil {
+		return err
+	}
+
+	*r = Request{
+		method:            jsonRequest.Method,
+		bodyPatterns:      jsonRequest.BodyPatterns,
+		multipartPatterns: jsonRequest.MultipartPatterns,
+	}
+	if r.method == "" {
+		r.method = MethodAny
+	}
+
+	urlMatchers := map[URLMatchingStrategy]*string{
+		URLEqualToRule:      jsonRequest.URL,
+		URLPathEqualToRule:  jsonRequest.URLPath,
+		URLPathMatchingRule: jsonRequest.URLPathPattern,
+		URLMatchingRule:     jsonRequest.URLPattern,
+	}
+	for strategy, value := range urlMatchers {
+		if value == nil {
+			continue
+		}
+		if r.urlMatcher != nil {
+			return fmt.Errorf("request has several url matchers: %s, %s", r.urlMatcher.Strategy(), strategy)
+		}
+		r.urlMatcher = URLMatcher{strategy: strategy, value: *value}
+	}
+
+	for key, matcher := range jsonRequest.Headers {
+		r.WithHeader(key, matcher)
+	}
+	for key, matcher := range jsonRequest.QueryParameters {
+		r.WithQueryParam(key, matcher)
+	}
+	for key, matcher := range jsonRequest.Cookies {
+		r.WithCookie(key, matcher)
+	}
+
+	if jsonRequest.BasicAuthCredentials != nil {
+		r.WithBasicAuth(jsonRequest.BasicAuthCredentials.Username, jsonRequest.BasicAuthCredentials.Password)
+	}
+
+	return nil
 }
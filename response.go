@@ -0,0 +1,333 @@
+package wiremock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault is enum of network faults WireMock can return instead of a response.
+type Fault string
+
+// Types of faults.
+const (
+	FaultConnectionResetByPeer  Fault = "CONNECTION_RESET_BY_PEER"
+	FaultEmptyResponse          Fault = "EMPTY_RESPONSE"
+	FaultMalformedResponseChunk Fault = "MALFORMED_RESPONSE_CHUNK"
+	FaultRandomDataThenClose    Fault = "RANDOM_DATA_THEN_CLOSE"
+)
+
+// ResponseTemplateTransformer is the name of WireMock response templating transformer.
+const ResponseTemplateTransformer = "response-template"
+
+// A Response is the part of StubRule describing the http response returned by WireMock
+type Response struct {
+	body         *string
+	base64Body   []byte
+	bodyFileName *string
+	// bodyFile is the content of bodyFileName uploaded by the client before the stub is registered
+	bodyFile []byte
+	jsonBody interface{}
+	headers  map[string]string
+	// multiHeaders keep all values of the headers with several values, which headers join
+	multiHeaders          map[string][]string
+	status                int64
+	fixedDelay            time.Duration
+	delayDistribution     *DelayDistribution
+	chunkedDribbleDelay   *chunkedDribbleDelay
+	fault                 Fault
+	transformers          []string
+	transformerParameters map[string]interface{}
+	proxy                 *proxyResponse
+}
+
+// Clone returns a copy of Response.
+// A JSON body and transformer parameter values are arbitrary user values, so they are shared between copies.
+func (r *Response) Clone() *Response {
+	clone := *r
+
+	if r.body != nil {
+		body := *r.body
+		clone.body = &body
+	}
+	if r.bodyFileName != nil {
+		bodyFileName := *r.bodyFileName
+		clone.bodyFileName = &bodyFileName
+	}
+	if r.base64Body != nil {
+		clone.base64Body = append([]byte(nil), r.base64Body...)
+	}
+	if r.bodyFile != nil {
+		clone.bodyFile = append([]byte(nil), r.bodyFile...)
+	}
+	if r.transformers != nil {
+		clone.transformers = append([]string(nil), r.transformers...)
+	}
+	if r.transformerParameters != nil {
+		clone.transformerParameters = make(map[string]interface{}, len(r.transformerParameters))
+		for key, value := range r.transformerParameters {
+			clone.transformerParameters[key] = value
+		}
+	}
+	if r.delayDistribution != nil {
+		delayDistribution := *r.delayDistribution
+		clone.delayDistribution = &delayDistribution
+	}
+	if r.chunkedDribbleDelay != nil {
+		chunkedDribbleDelay := *r.chunkedDribbleDelay
+		clone.chunkedDribbleDelay = &chunkedDribbleDelay
+	}
+	if r.proxy != nil {
+		clone.proxy = r.proxy.clone()
+	}
+	if r.headers != nil {
+		clone.headers = make(map[string]string, len(r.headers))
+		for key, value := range r.headers {
+			clone.headers[key] = value
+		}
+	}
+	if r.multiHeaders != nil {
+		clone.multiHeaders = make(map[string][]string, len(r.multiHeaders))
+		for key, values := range r.multiHeaders {
+			clone.multiHeaders[key] = append([]string(nil), values...)
+		}
+	}
+
+	return &clone
+}
+
+// Status is getter for status
+func (r *Response) Status() int64 {
+	return r.status
+}
+
+// Body is getter for body
+func (r *Response) Body() string {
+	if r.body == nil {
+		return ""
+	}
+
+	return *r.body
+}
+
+// Fault is getter for fault
+func (r *Response) Fault() Fault {
+	return r.fault
+}
+
+// Transformers is getter for transformers
+func (r *Response) Transformers() []string {
+	return r.transformers
+}
+
+// TransformerParameters is getter for transformerParameters
+func (r *Response) TransformerParameters() map[string]interface{} {
+	return r.transformerParameters
+}
+
+// Headers is getter for headers, multiple values of a header are joined with comma.
+func (r *Response) Headers() map[string]string {
+	return r.headers
+}
+
+// WithHeaders sets the headers, keeping every value of the multi-value ones, and returns *Response.
+// Other headers are kept, so headers built for Go servers can be added to the response:
+//
+//	stubRule.Response().WithHeaders(http.Header{"Set-Cookie": {"a=1", "b=2"}})
+//
+// Set it after WillReturn and its siblings, which replace the response headers.
+func (r *Response) WithHeaders(headers http.Header) *Response {
+	for key, values := range headers {
+		if len(values) == 0 {
+			continue
+		}
+
+		r.withHeader(key, strings.Join(values, ", "))
+		if len(values) > 1 {
+			if r.multiHeaders == nil {
+				r.multiHeaders = map[string][]string{}
+			}
+			r.multiHeaders[key] = append([]string(nil), values...)
+		}
+	}
+
+	return r
+}
+
+// HeaderValues gives the headers with all values of the multi-value ones.
+// The keys are as they were set, use Get of http.Header only when they are canonical.
+func (r *Response) HeaderValues() http.Header {
+	if r.headers == nil {
+		return nil
+	}
+
+	headers := make(http.Header, len(r.headers))
+	for key := range r.headers {
+		headers[key] = r.headerValues(key)
+	}
+
+	return headers
+}
+
+// headerValues gives the values of the header, the multiple ones unless the header was replaced since.
+func (r *Response) headerValues(key string) []string {
+	value := r.headers[key]
+	if values, ok := r.multiHeaders[key]; ok && strings.Join(values, ", ") == value {
+		return append([]string(nil), values...)
+	}
+
+	return []string{value}
+}
+
+// headersJSON gives WireMock JSON of the headers, with arrays of the multi-value ones.
+func (r *Response) headersJSON() map[string]interface{} {
+	if len(r.headers) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]interface{}, len(r.headers))
+	for key, value := range r.headers {
+		if values := r.headerValues(key); len(values) > 1 {
+			headers[key] = values
+			continue
+		}
+		headers[key] = value
+	}
+
+	return headers
+}
+
+// withHeader sets the single value header. The headers are copied first,
+// since they may be the map given to WillReturn and shared by other stubs.
+func (r *Response) withHeader(key, value string) {
+	headers := make(map[string]string, len(r.headers)+1)
+	for existing, existingValue := range r.headers {
+		headers[existing] = existingValue
+	}
+	headers[key] = value
+
+	r.headers = headers
+	delete(r.multiHeaders, key)
+}
+
+// setHeaders replaces the headers.
+func (r *Response) setHeaders(headers map[string]string) {
+	r.headers = headers
+	r.multiHeaders = nil
+}
+
+// MarshalJSON gives valid JSON or error.
+func (r *Response) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.jsonValue())
+}
+
+// jsonValue gives the JSON representation of Response made of plain values, so any JSON encoder can render it.
+func (r *Response) jsonValue() interface{} {
+	jsonResponse := struct {
+		Body                   string                 `json:"body,omitempty"`
+		Base64Body             string                 `json:"base64Body,omitempty"`
+		BodyFileName           string                 `json:"bodyFileName,omitempty"`
+		JSONBody               interface{}            `json:"jsonBody,omitempty"`
+		Headers                map[string]interface{} `json:"headers,omitempty"`
+		Status                 int64                  `json:"status,omitempty"`
+		FixedDelayMilliseconds int                    `json:"fixedDelayMilliseconds,omitempty"`
+		DelayDistribution      *DelayDistribution     `json:"delayDistribution,omitempty"`
+		ChunkedDribbleDelay    *chunkedDribbleDelay   `json:"chunkedDribbleDelay,omitempty"`
+		Fault                  Fault                  `json:"fault,omitempty"`
+		Transformers           []string               `json:"transformers,omitempty"`
+		TransformerParameters  map[string]interface{} `json:"transformerParameters,omitempty"`
+		*proxyResponse
+	}{}
+
+	if r.body != nil {
+		jsonResponse.Body = *r.body
+	} else if len(r.base64Body) > 0 {
+		jsonResponse.Base64Body = base64.StdEncoding.EncodeToString(r.base64Body)
+	} else if r.bodyFileName != nil {
+		jsonResponse.BodyFileName = *r.bodyFileName
+	} else if r.jsonBody != nil {
+		jsonResponse.JSONBody = r.jsonBody
+	}
+
+	jsonResponse.Headers = r.headersJSON()
+	jsonResponse.Status = r.status
+	jsonResponse.FixedDelayMilliseconds = int(r.fixedDelay.Milliseconds())
+	jsonResponse.DelayDistribution = r.delayDistribution
+	jsonResponse.ChunkedDribbleDelay = r.chunkedDribbleDelay
+	jsonResponse.Fault = r.fault
+	jsonResponse.Transformers = r.transformers
+	jsonResponse.TransformerParameters = r.transformerParameters
+	jsonResponse.proxyResponse = r.proxy
+
+	return jsonResponse
+}
+
+// UnmarshalJSON fills Response from WireMock JSON.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	jsonResponse := struct {
+		Body                   *string                    `json:"body"`
+		Base64Body             *string                    `json:"base64Body"`
+		BodyFileName           *string                    `json:"bodyFileName"`
+		JSONBody               interface{}                `json:"jsonBody"`
+		Headers                map[string]json.RawMessage `json:"headers"`
+		Status                 int64                      `json:"status"`
+		FixedDelayMilliseconds int64                      `json:"fixedDelayMilliseconds"`
+		DelayDistribution      *DelayDistribution         `json:"delayDistribution"`
+		ChunkedDribbleDelay    *chunkedDribbleDelay       `json:"chunkedDribbleDelay"`
+		Fault                  Fault                      `json:"fault"`
+		Transformers           []string                   `json:"transformers"`
+		TransformerParameters  map[string]interface{}     `json:"transformerParameters"`
+		proxyResponse
+	}{}
+	if err := json.Unmarshal(data, &jsonResponse); err != nil {
+		return err
+	}
+
+	*r = Response{
+		body:                  jsonResponse.Body,
+		bodyFileName:          jsonResponse.BodyFileName,
+		jsonBody:              jsonResponse.JSONBody,
+		status:                jsonResponse.Status,
+		fixedDelay:            time.Duration(jsonResponse.FixedDelayMilliseconds) * time.Millisecond,
+		delayDistribution:     jsonResponse.DelayDistribution,
+		chunkedDribbleDelay:   jsonResponse.ChunkedDribbleDelay,
+		fault:                 jsonResponse.Fault,
+		transformers:          jsonResponse.Transformers,
+		transformerParameters: jsonResponse.TransformerParameters,
+	}
+	multiHeaders, err := multipleValues(jsonResponse.Headers)
+	if err != nil {
+		return fmt.Errorf("decode headers: %s", err.Error())
+	}
+	r.headers = joinValues(multiHeaders)
+	for key, values := range multiHeaders {
+		if len(values) < 2 {
+			delete(multiHeaders, key)
+		}
+	}
+	if len(multiHeaders) > 0 {
+		r.multiHeaders = multiHeaders
+	}
+
+	if jsonResponse.ProxyBaseURL != "" {
+		proxy := jsonResponse.proxyResponse
+		r.proxy = &proxy
+	}
+
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	if jsonResponse.Base64Body != nil {
+		base64Body, err := base64.StdEncoding.DecodeString(*jsonResponse.Base64Body)
+		if err != nil {
+			return fmt.Errorf("decode base64Body: %s", err.Error())
+		}
+		r.base64Body = base64Body
+	}
+
+	return nil
+}
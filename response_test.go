@@ -0,0 +1,46 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestResponse_WithHeaders(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/login")).
+		WillReturn("", map[string]string{"Content-Type": "text/plain"}, http.StatusOK).
+		WithResponseHeaders(http.Header{"Set-Cookie": {"session=1", "theme=dark"}})
+
+	raw, err := json.Marshal(stubRule.Response())
+	if err != nil {
+		t.Fatalf("Response json.Marshal error: %v", err)
+	}
+	expected := `{"headers":{"Content-Type":"text/plain","Set-Cookie":["session=1","theme=dark"]},"status":200}`
+	if string(raw) != expected {
+		t.Fatalf("expected response %s; got %s", expected, raw)
+	}
+
+	var response Response
+	if err := json.Unmarshal(raw, &response); err != nil {
+		t.Fatalf("Response json.Unmarshal error: %v", err)
+	}
+	expectedHeaders := http.Header{"Content-Type": {"text/plain"}, "Set-Cookie": {"session=1", "theme=dark"}}
+	if !reflect.DeepEqual(response.HeaderValues(), expectedHeaders) {
+		t.Errorf("expected header values %v; got %v", expectedHeaders, response.HeaderValues())
+	}
+	if cookie := response.Headers()["Set-Cookie"]; cookie != "session=1, theme=dark" {
+		t.Errorf("expected joined Set-Cookie; got %q", cookie)
+	}
+
+	headers := map[string]string{"Set-Cookie": "session=2"}
+	stubRule.WillReturn("", headers, http.StatusOK)
+	if values := stubRule.Response().HeaderValues()["Set-Cookie"]; !reflect.DeepEqual(values, []string{"session=2"}) {
+		t.Errorf("expected replaced Set-Cookie; got %v", values)
+	}
+
+	stubRule.WithResponseHeaders(http.Header{"X-Trace": {"1"}})
+	if len(headers) != 1 {
+		t.Errorf("expected headers given to WillReturn not changed; got %v", headers)
+	}
+}
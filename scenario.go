@@ -0,0 +1,182 @@
+package wiremock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// ErrScenarioNotFound is returned when no registered stub is in the scenario with requested name.
+var ErrScenarioNotFound = errors.New("scenario not found")
+
+// A Scenario builds stubs served one after another, wiring WireMock scenario states for you:
+//
+//	client.StubScenario(wiremock.NewScenario("order-flow").
+//		StartingWith(Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"new"}`, nil, 200)).
+//		Then(Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"paid"}`, nil, 200)))
+//
+// Every call served by a step moves the scenario to the next step, the last step is served from then on, see Looping.
+type Scenario struct {
+	name    string
+	steps   []*StubRule
+	looping bool
+}
+
+// NewScenario returns *Scenario without steps.
+func NewScenario(name string) *Scenario {
+	return &Scenario{name: name}
+}
+
+// StartingWith sets the first step of the scenario and returns *Scenario
+func (s *Scenario) StartingWith(stubRule *StubRule) *Scenario {
+	s.steps = []*StubRule{stubRule}
+	return s
+}
+
+// Then adds the next step of the scenario and returns *Scenario
+func (s *Scenario) Then(stubRule *StubRule) *Scenario {
+	s.steps = append(s.steps, stubRule)
+	return s
+}
+
+// ThenTimes adds the step served count times in a row and returns *Scenario.
+// The calls after the first one are served by copies of the stub.
+func (s *Scenario) ThenTimes(count int, stubRule *StubRule) *Scenario {
+	for i := 0; i < count; i++ {
+		if i == 0 {
+			s.steps = append(s.steps, stubRule)
+		} else {
+			s.steps = append(s.steps, stubRule.Clone())
+		}
+	}
+	return s
+}
+
+// FirstCalls returns *Scenario serving first for the first count calls, then then for all the following ones,
+// e.g. to test retries:
+//
+//	client.StubScenario(wiremock.FirstCalls(2,
+//		Get(URLPathEqualTo("/orders")).WillReturn("", nil, 503),
+//		Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)))
+func FirstCalls(count int, first, then *StubRule) *Scenario {
+	return NewScenario("calls-"+first.UUID()).
+		ThenTimes(count, first).
+		Then(then)
+}
+
+// Looping makes the last step move the scenario back to the first one and returns *Scenario
+func (s *Scenario) Looping() *Scenario {
+	s.looping = true
+	return s
+}
+
+// Name is getter for scenario name
+func (s *Scenario) Name() string {
+	return s.name
+}
+
+// Stubs returns stubs of the steps in order, setting their scenario and states.
+func (s *Scenario) Stubs() []*StubRule {
+	for i, stubRule := range s.steps {
+		stubRule.
+			InScenario(s.name).
+			WhenScenarioStateIs(scenarioStepState(i))
+		switch {
+		case i < len(s.steps)-1:
+			stubRule.WillSetStateTo(scenarioStepState(i + 1))
+		case s.looping:
+			stubRule.WillSetStateTo(ScenarioStateStarted)
+		default:
+			stubRule.newScenarioState = nil
+		}
+	}
+
+	return s.steps
+}
+
+// StubScenario registers stubs of the scenario steps in order.
+func (c *Client) StubScenario(scenario *Scenario) error {
+	for _, stubRule := range scenario.Stubs() {
+		if err := c.StubFor(stubRule); err != nil {
+			return fmt.Errorf("stub scenario %s: %s", scenario.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func scenarioStepState(i int) string {
+	if i == 0 {
+		return ScenarioStateStarted
+	}
+
+	return fmt.Sprintf("step %d", i+1)
+}
+
+// ResetScenario moves the scenario back to ScenarioStateStarted, leaving other scenarios as they are.
+// It requires WireMock 3, older servers answer with ErrScenarioNotFound, use ResetAllScenarios with them.
+func (c *Client) ResetScenario(scenarioName string) error {
+	requestBody, err := c.codec.Marshal(map[string]string{"state": ScenarioStateStarted})
+	if err != nil {
+		return fmt.Errorf("reset scenario: build error: %s", err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s/scenarios/%s/state", c.url, wiremockAdminURN, url.PathEscape(scenarioName)), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("reset scenario: build request error: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reset scenario: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("reset scenario %s: %w", scenarioName, ErrScenarioNotFound)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		bodyBytes, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("reset scenario: read response error: %s", err.Error())
+		}
+
+		return fmt.Errorf("reset scenario: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// DeleteScenarioStubs resets the scenario and deletes the stubs in it, so stateful fixtures are torn down as a unit,
+// and returns their ids. WireMock forgets the scenario without stubs, so it starts over when its stubs are registered
+// again, with WireMock 2 as well.
+func (c *Client) DeleteScenarioStubs(scenarioName string) ([]string, error) {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("delete scenario stubs: %s", err.Error())
+	}
+
+	var ids []string
+	for _, stubRule := range stubs {
+		if stubRule.scenarioName != nil && *stubRule.scenarioName == scenarioName {
+			ids = append(ids, stubRule.UUID())
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err := c.ResetScenario(scenarioName); err != nil && !errors.Is(err, ErrScenarioNotFound) {
+		return nil, fmt.Errorf("delete scenario stubs: %s", err.Error())
+	}
+	if err := c.DeleteStubs(ids...); err != nil {
+		return nil, fmt.Errorf("delete scenario stubs: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,91 @@
+package wiremock
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScenario_Stubs(t *testing.T) {
+	created := Post(URLPathEqualTo("/orders")).WillReturn(`{"id":1}`, nil, 201)
+	pending := Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"pending"}`, nil, 200)
+	paid := Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"paid"}`, nil, 200)
+
+	stubs := NewScenario("order-flow").StartingWith(created).Then(pending).Then(paid).Stubs()
+	if len(stubs) != 3 || stubs[0] != created || stubs[2] != paid {
+		t.Fatalf("expected steps in order; got %v", stubs)
+	}
+
+	for i, expected := range []struct{ required, next string }{
+		{ScenarioStateStarted, "step 2"},
+		{"step 2", "step 3"},
+		{"step 3", ""},
+	} {
+		stubRule := stubs[i]
+		if *stubRule.scenarioName != "order-flow" || *stubRule.requiredScenarioState != expected.required {
+			t.Errorf("unexpected scenario of step %d: %s", i+1, stubRule)
+		}
+		if next := stubRule.newScenarioState; (next == nil) != (expected.next == "") || (next != nil && *next != expected.next) {
+			t.Errorf("unexpected next state of step %d: %s", i+1, stubRule)
+		}
+	}
+
+	server := newFakeServer(t)
+	if err := NewClient(server.URL).StubScenario(NewScenario("order-flow").StartingWith(created).Then(paid)); err != nil {
+		t.Fatalf("StubScenario error: %v", err)
+	}
+	if count := server.mappingCount(); count != 2 {
+		t.Errorf("expected 2 stubs; got %d", count)
+	}
+}
+
+func TestFirstCalls(t *testing.T) {
+	failing := Get(URLPathEqualTo("/orders")).WillReturn("", nil, 503)
+	succeeding := Get(URLPathEqualTo("/orders")).WillReturn("[]", nil, 200)
+
+	stubs := FirstCalls(3, failing, succeeding).Stubs()
+	if len(stubs) != 4 || stubs[0] != failing || stubs[3] != succeeding {
+		t.Fatalf("expected 3 failing calls, then succeeding; got %v", stubs)
+	}
+	for i, stubRule := range stubs[:3] {
+		if stubRule.Response().Status() != 503 || *stubRule.scenarioName != "calls-"+failing.UUID() || *stubRule.newScenarioState != scenarioStepState(i+1) {
+			t.Errorf("unexpected call %d: %s", i+1, stubRule)
+		}
+	}
+	if stubs[1].UUID() == stubs[0].UUID() {
+		t.Error("expected repeated calls to be served by copies of the stub")
+	}
+
+	if stubs := FirstCalls(0, failing, succeeding).Stubs(); len(stubs) != 1 || stubs[0] != succeeding {
+		t.Errorf("expected only succeeding call; got %v", stubs)
+	}
+}
+
+func TestClient_DeleteScenarioStubs(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	scenario := NewScenario("order flow").
+		StartingWith(Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"new"}`, nil, 200)).
+		Then(Get(URLPathEqualTo("/orders/1")).WillReturn(`{"status":"paid"}`, nil, 200))
+	if err := client.StubScenario(scenario); err != nil {
+		t.Fatalf("StubScenario error: %v", err)
+	}
+	if err := client.StubFor(Get(URLPathEqualTo("/users"))); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	ids, err := client.DeleteScenarioStubs("order flow")
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("expected 2 scenario stubs deleted; got %v, %v", ids, err)
+	}
+	if server.mappingCount() != 1 || server.scenarioStates["order flow"] != ScenarioStateStarted {
+		t.Errorf("expected other stub kept and scenario reset; got %d mappings, states %v", server.mappingCount(), server.scenarioStates)
+	}
+
+	if err := client.ResetScenario("order flow"); !errors.Is(err, ErrScenarioNotFound) {
+		t.Errorf("expected ErrScenarioNotFound; got %v", err)
+	}
+	if ids, err := client.DeleteScenarioStubs("order flow"); err != nil || ids != nil {
+		t.Errorf("expected nothing deleted; got %v, %v", ids, err)
+	}
+}
@@ -0,0 +1,108 @@
+package wiremock
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// A ServerInfo describes the connected WireMock server.
+//
+// WireMock does not list loaded extensions through the admin API, so they are not reported;
+// extensions configured through the settings are in GlobalSettings.Extended.
+type ServerInfo struct {
+	// Version is the WireMock version, e.g. "3.3.1", empty when the server does not report it.
+	// WireMock reports it since 3.0 through the version and the health APIs.
+	Version string
+}
+
+// MajorVersion gives the major number of the version, 2 when the server does not report it,
+// since every WireMock 3 server does.
+func (i *ServerInfo) MajorVersion() int {
+	if i.Version == "" {
+		return 2
+	}
+
+	return versionNumbers(i.Version)[0]
+}
+
+// AtLeast reports whether the version is the given one or later, e.g. AtLeast("3.1").
+// The server which does not report its version is taken as WireMock 2.
+func (i *ServerInfo) AtLeast(version string) bool {
+	actual := versionNumbers(i.Version)
+	if i.Version == "" {
+		actual = []int{2}
+	}
+
+	expected := versionNumbers(version)
+	for j := range expected {
+		var number int
+		if j < len(actual) {
+			number = actual[j]
+		}
+		if number != expected[j] {
+			return number > expected[j]
+		}
+	}
+
+	return true
+}
+
+// versionNumbers gives the dot separated numbers of the version, ignoring the suffix, e.g. [3 0 0] of "3.0.0-beta-10".
+func versionNumbers(version string) []int {
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		digits := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' })
+		if digits < 0 {
+			digits = len(part)
+		}
+		number, err := strconv.Atoi(part[:digits])
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, number)
+		if digits < len(part) {
+			break
+		}
+	}
+
+	if numbers == nil {
+		return []int{0}
+	}
+
+	return numbers
+}
+
+// ServerInfo retrieves the version of the server, so WireMock 2 and 3 behaviors can be told apart.
+// WireMock 2 servers do not have the version API, they give ServerInfo with the empty version.
+func (c *Client) ServerInfo() (*ServerInfo, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/version", c.url, wiremockAdminURN))
+	if err != nil {
+		return nil, fmt.Errorf("server info: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode == http.StatusNotFound {
+		return &ServerInfo{}, nil
+	}
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("server info: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server info: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var versionResponse struct {
+		Version string `json:"version"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &versionResponse); err != nil {
+		return nil, fmt.Errorf("server info: read json error: %s", err.Error())
+	}
+
+	return &ServerInfo{Version: versionResponse.Version}, nil
+}
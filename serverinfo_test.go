@@ -0,0 +1,69 @@
+package wiremock
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServerInfo_AtLeast(t *testing.T) {
+	for _, test := range []struct {
+		version, atLeast string
+		expected         bool
+	}{
+		{"3.3.1", "3", true},
+		{"3.3.1", "3.4", false},
+		{"3.0.0-beta-10", "3.0.0", true},
+		{"2.35.0", "3.0", false},
+		{"", "2.0", true},
+		{"", "3", false},
+	} {
+		info := &ServerInfo{Version: test.version}
+		if actual := info.AtLeast(test.atLeast); actual != test.expected {
+			t.Errorf("%q at least %q: expected %v; got %v", test.version, test.atLeast, test.expected, actual)
+		}
+	}
+}
+
+func TestClient_ServerInfo(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	info, err := client.ServerInfo()
+	if err != nil {
+		t.Fatalf("ServerInfo error: %v", err)
+	}
+	if info.Version != "" || info.MajorVersion() != 2 {
+		t.Errorf("expected WireMock 2 without version API; got %+v", info)
+	}
+
+	server.mu.Lock()
+	server.version = "3.3.1"
+	server.mu.Unlock()
+	if info, err = client.ServerInfo(); err != nil || info.Version != "3.3.1" || info.MajorVersion() != 3 {
+		t.Errorf("expected version 3.3.1; got %+v, error %v", info, err)
+	}
+}
+
+func TestClient_UnsupportedByServer(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	combined := Get(URLPathEqualTo("/trace")).WithHeader("X-Trace", AnyOf(Absent(), Including(AllOf(Contains("a"), Contains("b")))))
+	if err := client.StubFor(combined); !errors.Is(err, ErrUnsupportedByServer) || !strings.Contains(err.Error(), "or matcher requires WireMock 3.0, server is 2") {
+		t.Errorf("expected ErrUnsupportedByServer of WireMock 2; got %v", err)
+	}
+	if err := client.ImportStubs([]*StubRule{combined}); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("expected ErrUnsupportedByServer of import; got %v", err)
+	}
+	if err := client.StubFor(Get(URLPathEqualTo("/plain"))); err != nil {
+		t.Errorf("StubFor error: %v", err)
+	}
+
+	server.mu.Lock()
+	server.version = "3.3.1"
+	server.mu.Unlock()
+	if err := NewClient(server.URL).StubFor(combined); err != nil {
+		t.Errorf("expected combined matchers served by WireMock 3; got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const wiremockAdminSettingsURN = "__admin/settings"
+
+// ErrRequestJournalDisabled is returned by journal queries when the server runs without the request journal.
+var ErrRequestJournalDisabled = errors.New("request journal is disabled")
+
+// GlobalSettings is the server wide configuration changeable at runtime through the WireMock settings API.
+//
+// Extensions read their configuration from the extended settings, see Client.ConfigureExtension.
+// Response templating and the request journal are configured by server startup flags instead.
+//
+// The request journal cannot be capped or disabled through it: WireMock reads
+// --max-request-journal-entries and --no-request-journal on startup only, see JournalOptions.
+// Long running suites can free journal memory with Client.ResetRequests instead.
+type GlobalSettings struct {
+	fixedDelay        time.Duration
+	delayDistribution *DelayDistribution
+	proxyPassThrough  *bool
+	extended          map[string]interface{}
+}
+
+// NewGlobalSettings returns empty *GlobalSettings.
+func NewGlobalSettings() *GlobalSettings {
+	return &GlobalSettings{}
+}
+
+// WithFixedDelay sets delay added to every response and returns *GlobalSettings
+func (s *GlobalSettings) WithFixedDelay(delay time.Duration) *GlobalSettings {
+	s.fixedDelay = delay
+	return s
+}
+
+// WithProxyPassThrough enables or disables proxying of requests to proxy stubs and returns *GlobalSettings
+func (s *GlobalSettings) WithProxyPassThrough(enabled bool) *GlobalSettings {
+	s.proxyPassThrough = &enabled
+	return s
+}
+
+// WithExtended adds extension specific setting and returns *GlobalSettings
+func (s *GlobalSettings) WithExtended(key string, value interface{}) *GlobalSettings {
+	if s.extended == nil {
+		s.extended = map[string]interface{}{}
+	}
+
+	s.extended[key] = value
+	return s
+}
+
+// FixedDelay is getter for fixed delay
+func (s *GlobalSettings) FixedDelay() time.Duration {
+	return s.fixedDelay
+}
+
+// ProxyPassThrough is getter for proxy pass through, nil means server default
+func (s *GlobalSettings) ProxyPassThrough() *bool {
+	return s.proxyPassThrough
+}
+
+// Extended is getter for extension specific settings
+func (s *GlobalSettings) Extended() map[string]interface{} {
+	return s.extended
+}
+
+// MarshalJSON gives valid JSON or error.
+func (s *GlobalSettings) MarshalJSON() ([]byte, error) {
+	jsonSettings := struct {
+		FixedDelay        int64                  `json:"fixedDelay,omitempty"`
+		DelayDistribution *DelayDistribution     `json:"delayDistribution,omitempty"`
+		ProxyPassThrough  *bool                  `json:"proxyPassThrough,omitempty"`
+		Extended          map[string]interface{} `json:"extended,omitempty"`
+	}{
+		FixedDelay:        s.fixedDelay.Milliseconds(),
+		DelayDistribution: s.delayDistribution,
+		ProxyPassThrough:  s.proxyPassThrough,
+		Extended:          s.extended,
+	}
+
+	return json.Marshal(jsonSettings)
+}
+
+// UnmarshalJSON fills GlobalSettings from WireMock JSON.
+func (s *GlobalSettings) UnmarshalJSON(data []byte) error {
+	jsonSettings := struct {
+		FixedDelay        int64                  `json:"fixedDelay"`
+		DelayDistribution *DelayDistribution     `json:"delayDistribution"`
+		ProxyPassThrough  *bool                  `json:"proxyPassThrough"`
+		Extended          map[string]interface{} `json:"extended"`
+	}{}
+	if err := json.Unmarshal(data, &jsonSettings); err != nil {
+		return err
+	}
+
+	*s = GlobalSettings{
+		fixedDelay:        time.Duration(jsonSettings.FixedDelay) * time.Millisecond,
+		delayDistribution: jsonSettings.DelayDistribution,
+		proxyPassThrough:  jsonSettings.ProxyPassThrough,
+		extended:          jsonSettings.Extended,
+	}
+
+	return nil
+}
+
+// ConfigureExtension sets the extended setting of the extension, keeping other settings.
+// By convention extensions read their configuration from the extended setting named after them,
+// so a freshly started server can be configured without restart.
+func (c *Client) ConfigureExtension(name string, config interface{}) error {
+	settings, err := c.GetSettings()
+	if err != nil {
+		return fmt.Errorf("configure extension %s: %s", name, err.Error())
+	}
+
+	if err := c.UpdateSettings(settings.WithExtended(name, config)); err != nil {
+		return fmt.Errorf("configure extension %s: %s", name, err.Error())
+	}
+
+	return nil
+}
+
+// JournalOptions is the request journal configuration.
+// WireMock reads it on startup only, so JournalOptions renders the startup arguments, e.g. for a container launcher,
+// and Client.RequestJournalEnabled tells how the connected server runs.
+type JournalOptions struct {
+	disabled   bool
+	maxEntries int
+}
+
+// NewJournalOptions returns *JournalOptions of the enabled journal without limit.
+func NewJournalOptions() *JournalOptions {
+	return &JournalOptions{}
+}
+
+// WithDisabled disables the journal, e.g. for soak tests, and returns *JournalOptions.
+// Verification, GetServeEvents, FindRequests, FindUnmatchedRequests and FindNearMissesForUnmatched
+// fail with ErrRequestJournalDisabled then.
+func (o *JournalOptions) WithDisabled() *JournalOptions {
+	o.disabled = true
+	return o
+}
+
+// WithMaxEntries keeps only the most recent entries of the journal and returns *JournalOptions
+func (o *JournalOptions) WithMaxEntries(maxEntries int) *JournalOptions {
+	o.maxEntries = maxEntries
+	return o
+}
+
+// Args gives WireMock startup arguments of the journal configuration.
+func (o *JournalOptions) Args() []string {
+	var args []string
+	if o.disabled {
+		args = append(args, "--no-request-journal")
+	}
+	if o.maxEntries > 0 {
+		args = append(args, "--max-request-journal-entries="+strconv.Itoa(o.maxEntries))
+	}
+
+	return args
+}
+
+// RequestJournalEnabled reports whether the server keeps the request journal.
+func (c *Client) RequestJournalEnabled() (bool, error) {
+	res, err := c.httpClient.Get(fmt.Sprintf("%s/%s/requests?limit=1", c.url, wiremockAdminURN))
+	if err != nil {
+		return false, fmt.Errorf("request journal enabled: request error: %s", err.Error())
+	}
+	defer drainAndClose(res.Body)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return false, fmt.Errorf("request journal enabled: read response error: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("request journal enabled: bad response status: %d, response: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	var requestsResponse struct {
+		RequestJournalDisabled bool `json:"requestJournalDisabled"`
+	}
+	if err := c.codec.Unmarshal(bodyBytes, &requestsResponse); err != nil {
+		return false, fmt.Errorf("request journal enabled: read json error: %s", err.Error())
+	}
+
+	return !requestsResponse.RequestJournalDisabled, nil
+}
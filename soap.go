@@ -0,0 +1,72 @@
+package wiremock
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// SOAP 1.1 constants.
+const (
+	SOAPEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	SOAPContentType       = "text/xml; charset=utf-8"
+)
+
+// SOAP 1.1 fault codes.
+const (
+	SOAPFaultClient          = "soap:Client"
+	SOAPFaultServer          = "soap:Server"
+	SOAPFaultVersionMismatch = "soap:VersionMismatch"
+	SOAPFaultMustUnderstand  = "soap:MustUnderstand"
+)
+
+// SOAPRequest returns POST *StubRule for path matching SOAPAction header, quoted or not.
+func SOAPRequest(path, action string) *StubRule {
+	return Post(URLPathEqualTo(path)).
+		WithHeader("SOAPAction", Matching(`"?`+regexp.QuoteMeta(action)+`"?`))
+}
+
+// WithSOAPOperation adds body pattern matching the first element of the SOAP Body by local name
+// regardless of namespace prefixes and returns *StubRule
+func (s *StubRule) WithSOAPOperation(operation string) *StubRule {
+	return s.WithBodyPattern(MatchingXPath(fmt.Sprintf(
+		"/*[local-name()='Envelope']/*[local-name()='Body']/*[local-name()='%s']",
+		operation,
+	)))
+}
+
+// WillReturnSOAP sets response with body wrapped into SOAP envelope and returns *StubRule
+func (s *StubRule) WillReturnSOAP(body string, status int64) *StubRule {
+	return s.WillReturn(SOAPEnvelope(body), map[string]string{"Content-Type": SOAPContentType}, status)
+}
+
+// WillReturnSOAPFault sets 500 response with SOAP fault envelope and returns *StubRule
+func (s *StubRule) WillReturnSOAPFault(code, message string) *StubRule {
+	return s.WillReturnSOAP(SOAPFault(code, message), http.StatusInternalServerError)
+}
+
+// SOAPEnvelope wraps body XML into SOAP 1.1 envelope.
+func SOAPEnvelope(body string) string {
+	return fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="%s"><soap:Body>%s</soap:Body></soap:Envelope>`,
+		SOAPEnvelopeNamespace,
+		body,
+	)
+}
+
+// SOAPFault gives SOAP 1.1 fault element with escaped message.
+func SOAPFault(code, message string) string {
+	return fmt.Sprintf(
+		"<soap:Fault><faultcode>%s</faultcode><faultstring>%s</faultstring></soap:Fault>",
+		escapeXML(code),
+		escapeXML(message),
+	)
+}
+
+func escapeXML(text string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
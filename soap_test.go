@@ -0,0 +1,41 @@
+package wiremock
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+)
+
+func TestSOAPRequest(t *testing.T) {
+	stubRule := SOAPRequest("/ws", "urn:GetOrder").
+		WithSOAPOperation("GetOrder").
+		WillReturnSOAPFault(SOAPFaultClient, "order <42> not found")
+
+	action := stubRule.Request().Headers()["SOAPAction"]
+	for _, value := range []string{`"urn:GetOrder"`, "urn:GetOrder"} {
+		if matched, _ := matchValue(action, &value); !matched {
+			t.Errorf("expected SOAPAction %s to match", value)
+		}
+	}
+
+	if stubRule.Response().Status() != http.StatusInternalServerError {
+		t.Errorf("expected status 500; got %d", stubRule.Response().Status())
+	}
+
+	var envelope struct {
+		XMLName xml.Name
+		Body    struct {
+			Fault struct {
+				Code   string `xml:"faultcode"`
+				String string `xml:"faultstring"`
+			} `xml:"Fault"`
+		} `xml:"Body"`
+	}
+	if err := xml.Unmarshal([]byte(*stubRule.Response().body), &envelope); err != nil {
+		t.Fatalf("fault xml.Unmarshal error: %v", err)
+	}
+	if envelope.XMLName.Space != SOAPEnvelopeNamespace || envelope.Body.Fault.Code != SOAPFaultClient ||
+		envelope.Body.Fault.String != "order <42> not found" {
+		t.Errorf("unexpected fault envelope %+v", envelope)
+	}
+}
@@ -0,0 +1,91 @@
+package wiremock
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentTypeEventStream is the content type of Server-Sent Events responses.
+const ContentTypeEventStream = "text/event-stream"
+
+// An SSEEvent is the event of the Server-Sent Events response.
+type SSEEvent struct {
+	data  string
+	event string
+	id    string
+	retry time.Duration
+	delay time.Duration
+}
+
+// NewSSEEvent returns *SSEEvent with data, which may have multiple lines.
+func NewSSEEvent(data string) *SSEEvent {
+	return &SSEEvent{data: data}
+}
+
+// WithEvent sets event type and returns *SSEEvent
+func (e *SSEEvent) WithEvent(event string) *SSEEvent {
+	e.event = event
+	return e
+}
+
+// WithID sets event id and returns *SSEEvent
+func (e *SSEEvent) WithID(id string) *SSEEvent {
+	e.id = id
+	return e
+}
+
+// WithRetry sets reconnection time advised to the client and returns *SSEEvent
+func (e *SSEEvent) WithRetry(retry time.Duration) *SSEEvent {
+	e.retry = retry
+	return e
+}
+
+// WithDelay sets delay of the event after the previous one, or after the response start for the first event,
+// and returns *SSEEvent
+func (e *SSEEvent) WithDelay(delay time.Duration) *SSEEvent {
+	e.delay = delay
+	return e
+}
+
+// String renders the event in the event stream format.
+func (e *SSEEvent) String() string {
+	var b strings.Builder
+	if e.id != "" {
+		b.WriteString("id: " + e.id + "\n")
+	}
+	if e.event != "" {
+		b.WriteString("event: " + e.event + "\n")
+	}
+	if e.retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(e.data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// WillReturnSSE sets 200 response streaming events and returns *StubRule.
+// Events are sent at their delays the way WillReturnChunks sends chunks, padded by comment lines, which clients ignore.
+func (s *StubRule) WillReturnSSE(events ...*SSEEvent) *StubRule {
+	headers := map[string]string{
+		"Content-Type":  ContentTypeEventStream,
+		"Cache-Control": "no-cache",
+	}
+
+	parts := make([]string, len(events))
+	delays := make([]time.Duration, len(events))
+	for i, event := range events {
+		parts[i] = event.String()
+		delays[i] = event.delay
+	}
+
+	return s.willStream(parts, delays, func(size int) string {
+		// padded to at least 2, the shortest comment ":\n"
+		return ":" + strings.Repeat(" ", size-2) + "\n"
+	}, 2, headers, http.StatusOK)
+}
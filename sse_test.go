@@ -0,0 +1,65 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStubRule_WillReturnSSE(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/events")).WillReturnSSE(
+		NewSSEEvent("connected").WithEvent("open").WithRetry(time.Second),
+		NewSSEEvent("line 1\nline 2").WithID("1"),
+	)
+	if body := stubRule.Response().Body(); body != "event: open\nretry: 1000\ndata: connected\n\nid: 1\ndata: line 1\ndata: line 2\n\n" {
+		t.Errorf("unexpected event stream %q", body)
+	}
+	if chunks, _ := stubRule.Response().ChunkedDribbleDelay(); chunks != 0 || stubRule.Response().Headers()["Content-Type"] != ContentTypeEventStream {
+		t.Errorf("expected plain event stream without delays; got %d chunks, %v", chunks, stubRule.Response().Headers())
+	}
+
+	stubRule.WillReturnSSE(
+		NewSSEEvent("a").WithDelay(100*time.Millisecond),
+		NewSSEEvent("b"),
+		NewSSEEvent("long event").WithDelay(300*time.Millisecond),
+	)
+
+	chunks, total := stubRule.Response().ChunkedDribbleDelay()
+	if chunks != 5 || total != 500*time.Millisecond {
+		t.Fatalf("expected 5 chunks at 100ms; got %d chunks in %v", chunks, total)
+	}
+
+	body := stubRule.Response().Body()
+	size := len(body) / int(chunks)
+	if len(body)%int(chunks) != 0 {
+		t.Fatalf("expected chunks of equal size; got body of %d bytes", len(body))
+	}
+	// chunk i is sent at i * 100ms
+	for i, expected := range []string{"", "data: a\n\ndata: b\n\n", "", "", "data: long event\n\n"} {
+		chunk := body[i*size : (i+1)*size]
+		if !strings.HasPrefix(chunk, ":") || !strings.HasSuffix(chunk, expected) || strings.Count(chunk, "data:") != strings.Count(expected, "data:") {
+			t.Errorf("chunk %d: expected comment padding and %q; got %q", i, expected, chunk)
+		}
+	}
+}
+
+func TestStubRule_WillReturnChunks(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/orders.ndjson")).WillReturnChunks([]*BodyChunk{
+		NewBodyChunk(`{"id":1}` + "\n"),
+		NewBodyChunk(`{"id":22}` + "\n").WithDelay(50 * time.Millisecond),
+		NewBodyChunk(`{"id":3}` + "\n").WithDelay(100 * time.Millisecond),
+	}, ' ', map[string]string{"Content-Type": "application/x-ndjson"}, 200)
+
+	chunks, total := stubRule.Response().ChunkedDribbleDelay()
+	if chunks != 4 || total != 200*time.Millisecond {
+		t.Fatalf("expected 4 chunks at 50ms; got %d chunks in %v", chunks, total)
+	}
+
+	body := stubRule.Response().Body()
+	size := len(body) / int(chunks)
+	for i, expected := range []string{" " + `{"id":1}` + "\n", `{"id":22}` + "\n", "          ", " " + `{"id":3}` + "\n"} {
+		if chunk := body[i*size : (i+1)*size]; chunk != expected {
+			t.Errorf("chunk %d: expected %q; got %q", i, expected, chunk)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package wiremock
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A StubStat is the usage of the stub aggregated from the request journal.
+type StubStat struct {
+	StubID string
+	// Stub is the registered stub, nil when the journal keeps requests of the stub which is deleted since.
+	Stub *StubRule
+	// Hits is the number of requests matched by the stub.
+	Hits int
+	// LastMatched is the date of the most recent request matched by the stub, zero when there is none.
+	LastMatched time.Time
+}
+
+// Unused reports whether no request in the journal matched the stub.
+func (s *StubStat) Unused() bool {
+	return s.Hits == 0
+}
+
+// StubStats aggregates the request journal into the usage of every registered stub, in the order of ListStubs,
+// followed by the deleted stubs the journal keeps requests of, e.g. to find unused fixtures:
+//
+//	stats, err := client.StubStats()
+//	for _, stat := range stats {
+//		if stat.Unused() {
+//			log.Printf("stub %s is unused", stat.StubID)
+//		}
+//	}
+//
+// The journal may be limited by --max-request-journal-entries, then older hits are not counted.
+func (c *Client) StubStats() ([]StubStat, error) {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("stub stats: %s", err.Error())
+	}
+
+	events, err := c.GetServeEvents(nil)
+	if err != nil {
+		return nil, fmt.Errorf("stub stats: %s", err.Error())
+	}
+
+	stats := make([]StubStat, len(stubs))
+	byID := make(map[string]*StubStat, len(stubs))
+	for i, stub := range stubs {
+		stats[i] = StubStat{StubID: stub.UUID(), Stub: stub}
+		byID[stub.UUID()] = &stats[i]
+	}
+
+	deleted := map[string]*StubStat{}
+	for _, event := range events {
+		if !event.WasMatched {
+			continue
+		}
+
+		id := event.StubID()
+		stat, ok := byID[id]
+		if !ok {
+			if stat, ok = deleted[id]; !ok {
+				stat = &StubStat{StubID: id}
+				deleted[id] = stat
+			}
+		}
+
+		stat.Hits++
+		if event.Request.LoggedDate.After(stat.LastMatched) {
+			stat.LastMatched = event.Request.LoggedDate
+		}
+	}
+
+	ids := make([]string, 0, len(deleted))
+	for id := range deleted {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		stats = append(stats, *deleted[id])
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,50 @@
+package wiremock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClient_StubStats(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Get(URLPathEqualTo("/orders"))
+	users := Get(URLPathEqualTo("/users"))
+	for _, stub := range []*StubRule{orders, users} {
+		if err := client.StubFor(stub); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	logged := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, stubID := range []string{orders.UUID(), orders.UUID(), "deleted", ""} {
+		event := map[string]interface{}{
+			"id":         fmt.Sprintf("e%d", i),
+			"request":    map[string]interface{}{"url": "/", "method": "GET", "loggedDate": logged.Add(time.Duration(i) * time.Minute).UnixMilli()},
+			"wasMatched": stubID != "",
+		}
+		if stubID != "" {
+			event["stubMapping"] = map[string]interface{}{"id": stubID, "request": map[string]interface{}{"method": "GET"}}
+		}
+		server.logServeEvent(t, event)
+	}
+
+	stats, err := client.StubStats()
+	if err != nil {
+		t.Fatalf("StubStats error: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected stats of 2 stubs and the deleted one; got %+v", stats)
+	}
+	if stats[0].StubID != orders.UUID() || stats[0].Hits != 2 || !stats[0].LastMatched.Equal(logged.Add(time.Minute)) {
+		t.Errorf("unexpected stat of orders %+v", stats[0])
+	}
+	if stats[1].StubID != users.UUID() || !stats[1].Unused() || stats[1].Stub == nil {
+		t.Errorf("expected unused users stub; got %+v", stats[1])
+	}
+	if stats[2].StubID != "deleted" || stats[2].Hits != 1 || stats[2].Stub != nil {
+		t.Errorf("unexpected stat of deleted stub %+v", stats[2])
+	}
+}
@@ -0,0 +1,72 @@
+package wiremock
+
+import "net/http"
+
+// ContentTypeProblemJSON is the media type of RFC 7807 problem details.
+const ContentTypeProblemJSON = "application/problem+json"
+
+// ProblemDetails gives RFC 7807 problem details of the status with the detail, omitted when empty.
+func ProblemDetails(status int64, detail string) map[string]interface{} {
+	problem := map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(int(status)),
+		"status": status,
+	}
+	if detail != "" {
+		problem["detail"] = detail
+	}
+
+	return problem
+}
+
+// WillReturnStatus sets response with status and no body and returns *StubRule
+func (s *StubRule) WillReturnStatus(status int64) *StubRule {
+	s.response.body = nil
+	s.response.base64Body = nil
+	s.response.bodyFileName = nil
+	s.response.bodyFile = nil
+	s.response.jsonBody = nil
+	s.response.setHeaders(nil)
+	s.response.status = status
+	return s
+}
+
+// WillReturnNoContent sets 204 response with no body and returns *StubRule
+func (s *StubRule) WillReturnNoContent() *StubRule {
+	return s.WillReturnStatus(http.StatusNoContent)
+}
+
+// WillReturnProblem sets response with RFC 7807 problem json body and returns *StubRule.
+// The problem is any value encoded to json, nil is ProblemDetails of the status.
+func (s *StubRule) WillReturnProblem(problem interface{}, status int64) *StubRule {
+	if problem == nil {
+		problem = ProblemDetails(status, "")
+	}
+
+	return s.WillReturnJSON(problem, map[string]string{"Content-Type": ContentTypeProblemJSON}, status)
+}
+
+// WillReturnBadRequest sets 400 response with problem json body, see WillReturnProblem, and returns *StubRule
+func (s *StubRule) WillReturnBadRequest(problem interface{}) *StubRule {
+	return s.WillReturnProblem(problem, http.StatusBadRequest)
+}
+
+// WillReturnUnauthorized sets 401 response with problem json body of the status and returns *StubRule
+func (s *StubRule) WillReturnUnauthorized() *StubRule {
+	return s.WillReturnProblem(nil, http.StatusUnauthorized)
+}
+
+// WillReturnForbidden sets 403 response with problem json body of the status and returns *StubRule
+func (s *StubRule) WillReturnForbidden() *StubRule {
+	return s.WillReturnProblem(nil, http.StatusForbidden)
+}
+
+// WillReturnNotFound sets 404 response with problem json body of the status and returns *StubRule
+func (s *StubRule) WillReturnNotFound() *StubRule {
+	return s.WillReturnProblem(nil, http.StatusNotFound)
+}
+
+// WillReturnServerError sets 500 response with problem json body, see WillReturnProblem, and returns *StubRule
+func (s *StubRule) WillReturnServerError(problem interface{}) *StubRule {
+	return s.WillReturnProblem(problem, http.StatusInternalServerError)
+}
@@ -0,0 +1,43 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCannedStatuses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stubRule *StubRule
+		expected string
+	}{
+		{
+			name:     "no content drops the body",
+			stubRule: Delete(URLPathEqualTo("/orders/1")).WillReturn(`{}`, map[string]string{"Content-Type": ContentTypeJSON}, 200).WillReturnNoContent(),
+			expected: `{"status":204}`,
+		},
+		{
+			name:     "not found",
+			stubRule: Get(URLPathEqualTo("/orders/1")).WillReturnNotFound(),
+			expected: `{"jsonBody":{"status":404,"title":"Not Found","type":"about:blank"},"headers":{"Content-Type":"application/problem+json"},"status":404}`,
+		},
+		{
+			name:     "server error of the problem",
+			stubRule: Get(URLPathEqualTo("/orders")).WillReturnServerError(ProblemDetails(http.StatusInternalServerError, "database is down")),
+			expected: `{"jsonBody":{"detail":"database is down","status":500,"title":"Internal Server Error","type":"about:blank"},"headers":{"Content-Type":"application/problem+json"},"status":500}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(tc.stubRule.Response())
+			if err != nil {
+				t.Fatalf("Response json.Marshal error: %v", err)
+			}
+			if string(raw) != tc.expected {
+				t.Errorf("expected response %s; got %s", tc.expected, raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package wiremock
+
+import (
+	"strings"
+	"time"
+)
+
+// A BodyChunk is the part of the streamed response body.
+type BodyChunk struct {
+	data  string
+	delay time.Duration
+}
+
+// NewBodyChunk returns *BodyChunk of data.
+func NewBodyChunk(data string) *BodyChunk {
+	return &BodyChunk{data: data}
+}
+
+// WithDelay sets delay of the chunk after the previous one, or after the response start for the first chunk,
+// and returns *BodyChunk
+func (c *BodyChunk) WithDelay(delay time.Duration) *BodyChunk {
+	c.delay = delay
+	return c
+}
+
+// WillReturnChunks sets response streaming chunks at their delays and returns *StubRule.
+// WireMock sends bodies in chunks of equal size at equal intervals only, so chunks are left padded with padding
+// to equal size and idle intervals are filled with padding. The padding must be insignificant to the consumer,
+// e.g. ' ' for JSON and NDJSON bodies.
+// Intervals are the greatest common divisor of the delays in milliseconds, keep delays round to keep the body small.
+func (s *StubRule) WillReturnChunks(chunks []*BodyChunk, padding byte, headers map[string]string, status int64) *StubRule {
+	parts := make([]string, len(chunks))
+	delays := make([]time.Duration, len(chunks))
+	for i, chunk := range chunks {
+		parts[i] = chunk.data
+		delays[i] = chunk.delay
+	}
+
+	return s.willStream(parts, delays, func(size int) string {
+		return strings.Repeat(string(padding), size)
+	}, 0, headers, status)
+}
+
+// willStream sets response sending parts after delays on the chunked dribble delay.
+// pad gives padding of size, which is at least minPad for padded parts.
+func (s *StubRule) willStream(parts []string, delays []time.Duration, pad func(size int) string, minPad int, headers map[string]string, status int64) *StubRule {
+	// parts sent at once make one chunk, led by a part with delay
+	var grouped []string
+	var idle []int
+	interval := 0
+	for i, part := range parts {
+		delay := int(delays[i].Milliseconds())
+		if i == 0 || delay > 0 {
+			grouped = append(grouped, "")
+			idle = append(idle, delay)
+		}
+		grouped[len(grouped)-1] += part
+		interval = gcd(interval, delay)
+	}
+
+	s.response.chunkedDribbleDelay = nil
+	if interval == 0 {
+		return s.WillReturn(strings.Join(grouped, ""), headers, status)
+	}
+
+	size := 0
+	for _, part := range grouped {
+		if len(part) > size {
+			size = len(part)
+		}
+	}
+	size += minPad
+
+	var chunks []string
+	for i, part := range grouped {
+		idleChunks := idle[i] / interval
+		// the previous chunk is written one interval before
+		if i > 0 {
+			idleChunks--
+		}
+		for j := 0; j < idleChunks; j++ {
+			chunks = append(chunks, pad(size))
+		}
+		chunks = append(chunks, pad(size-len(part))+part)
+	}
+
+	return s.WillReturn(strings.Join(chunks, ""), headers, status).
+		WithChunkedDribbleDelay(int64(len(chunks)), time.Duration(len(chunks)*interval)*time.Millisecond)
+}
@@ -0,0 +1,46 @@
+package wiremock
+
+// A StubOption sets the attribute of StubRule on construction, see NewStubRule.
+type StubOption func(s *StubRule)
+
+// AtPriority sets priority of the stub, see StubRule.AtPriority.
+func AtPriority(priority int64) StubOption {
+	return func(s *StubRule) {
+		s.AtPriority(priority)
+	}
+}
+
+// WithStubID sets uuid of the stub, see StubRule.WithID.
+func WithStubID(uuid string) StubOption {
+	return func(s *StubRule) {
+		s.WithID(uuid)
+	}
+}
+
+// InScenario sets scenario of the stub, see StubRule.InScenario.
+func InScenario(scenarioName string) StubOption {
+	return func(s *StubRule) {
+		s.InScenario(scenarioName)
+	}
+}
+
+// WhenScenarioStateIs sets scenario state required by the stub, see StubRule.WhenScenarioStateIs.
+func WhenScenarioStateIs(scenarioState string) StubOption {
+	return func(s *StubRule) {
+		s.WhenScenarioStateIs(scenarioState)
+	}
+}
+
+// WillSetStateTo sets scenario state the stub moves to, see StubRule.WillSetStateTo.
+func WillSetStateTo(scenarioState string) StubOption {
+	return func(s *StubRule) {
+		s.WillSetStateTo(scenarioState)
+	}
+}
+
+// WithStubMetadata adds metadata entry of the stub, see StubRule.WithMetadata.
+func WithStubMetadata(key string, value interface{}) StubOption {
+	return func(s *StubRule) {
+		s.WithMetadata(key, value)
+	}
+}
@@ -0,0 +1,27 @@
+package wiremock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewStubRule_Options(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/orders"),
+		AtPriority(1),
+		WithStubID("2c8f6a4e-6f86-4a43-9b6a-8d1f3f1b9e21"),
+		InScenario("orders"),
+		WhenScenarioStateIs(ScenarioStateStarted),
+		WillSetStateTo("listed"),
+		WithStubMetadata("team", "payments"),
+	)
+
+	if stubRule.Request().Method() != http.MethodGet || stubRule.Priority() != 1 || stubRule.UUID() != "2c8f6a4e-6f86-4a43-9b6a-8d1f3f1b9e21" {
+		t.Errorf("unexpected stub %s", stubRule)
+	}
+	if *stubRule.scenarioName != "orders" || *stubRule.requiredScenarioState != ScenarioStateStarted || *stubRule.newScenarioState != "listed" {
+		t.Errorf("unexpected scenario of stub %s", stubRule)
+	}
+	if stubRule.Metadata()["team"] != "payments" {
+		t.Errorf("unexpected metadata %v", stubRule.Metadata())
+	}
+}
@@ -1,8 +1,8 @@
 package wiremock
 
 import (
-	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -11,6 +11,9 @@ import (
 
 const ScenarioStateStarted = "Started"
 
+// stubIDNamespace is the namespace of content derived stub ids.
+var stubIDNamespace = uuidPkg.NewSHA1(uuidPkg.NameSpaceURL, []byte("https://github.com/walkerus/go-wiremock"))
+
 // ParamMatcherInterface is pair ParamMatchingStrategy and string matched value
 type ParamMatcherInterface interface {
 	Strategy() ParamMatchingStrategy
@@ -24,37 +27,70 @@ type URLMatcherInterface interface {
 	Value() string
 }
 
-type response struct {
-	body                   *string
-	base64Body             []byte
-	bodyFileName           *string
-	jsonBody               interface{}
-	headers                map[string]string
-	status                 int64
-	fixedDelayMilliseconds time.Duration
-}
-
 // StubRule is struct of http Request body to WireMock
 type StubRule struct {
 	uuid                  string
 	request               *Request
-	response              response
+	response              Response
 	priority              *int64
 	scenarioName          *string
 	requiredScenarioState *string
 	newScenarioState      *string
+	metadata              map[string]interface{}
+	webhooks              []*Webhook
+	// postServeActions and serveEventListeners are the ones other than webhooks read from the server, kept as they are
+	postServeActions    []json.RawMessage
+	serveEventListeners []json.RawMessage
+	// idErr is the error of WithDeterministicID, reported by Validate
+	idErr error
 }
 
-// NewStubRule returns a new *StubRule.
-func NewStubRule(method string, urlMatcher URLMatcher) *StubRule {
+// NewStubRule returns a new *StubRule with the options applied, e.g.
+//
+//	NewStubRule(http.MethodGet, URLPathEqualTo("/orders"), AtPriority(1), InScenario("orders"))
+func NewStubRule(method string, urlMatcher URLMatcher, options ...StubOption) *StubRule {
 	uuid, _ := uuidPkg.NewRandom()
-	return &StubRule{
+	stubRule := &StubRule{
 		uuid:    uuid.String(),
 		request: NewRequest(method, urlMatcher),
-		response: response{
+		response: Response{
 			status: http.StatusOK,
 		},
 	}
+	for _, option := range options {
+		option(stubRule)
+	}
+
+	return stubRule
+}
+
+// Clone returns a deep copy of StubRule with a newly generated uuid,
+// so the copy can be registered alongside the original.
+func (s *StubRule) Clone() *StubRule {
+	uuid, _ := uuidPkg.NewRandom()
+	clone := &StubRule{
+		uuid:                  uuid.String(),
+		request:               s.request.Clone(),
+		response:              *s.response.Clone(),
+		priority:              cloneInt64Ptr(s.priority),
+		scenarioName:          cloneStringPtr(s.scenarioName),
+		requiredScenarioState: cloneStringPtr(s.requiredScenarioState),
+		newScenarioState:      cloneStringPtr(s.newScenarioState),
+	}
+
+	if s.metadata != nil {
+		clone.metadata = make(map[string]interface{}, len(s.metadata))
+		for key, value := range s.metadata {
+			clone.metadata[key] = value
+		}
+	}
+	for _, webhook := range s.webhooks {
+		clone.webhooks = append(clone.webhooks, webhook.clone())
+	}
+	clone.postServeActions = append([]json.RawMessage(nil), s.postServeActions...)
+	clone.serveEventListeners = append([]json.RawMessage(nil), s.serveEventListeners...)
+
+	return clone
 }
 
 // Request is getter for Request
@@ -62,6 +98,11 @@ func (s *StubRule) Request() *Request {
 	return s.request
 }
 
+// Response is getter for Response
+func (s *StubRule) Response() *Response {
+	return &s.response
+}
+
 // WithQueryParam adds query param and returns *StubRule
 func (s *StubRule) WithQueryParam(param string, matcher ParamMatcherInterface) *StubRule {
 	s.request.WithQueryParam(param, matcher)
@@ -95,7 +136,7 @@ func (s *StubRule) WithMultipartPattern(pattern *MultipartPattern) *StubRule {
 // WillReturn sets response and returns *StubRule
 func (s *StubRule) WillReturn(body string, headers map[string]string, status int64) *StubRule {
 	s.response.body = &body
-	s.response.headers = headers
+	s.response.setHeaders(headers)
 	s.response.status = status
 	return s
 }
@@ -103,7 +144,7 @@ func (s *StubRule) WillReturn(body string, headers map[string]string, status int
 // WillReturnBinary sets response with binary body and returns *StubRule
 func (s *StubRule) WillReturnBinary(body []byte, headers map[string]string, status int64) *StubRule {
 	s.response.base64Body = body
-	s.response.headers = headers
+	s.response.setHeaders(headers)
 	s.response.status = status
 	return s
 }
@@ -111,7 +152,7 @@ func (s *StubRule) WillReturnBinary(body []byte, headers map[string]string, stat
 // WillReturnFileContent sets response with some file content and returns *StubRule
 func (s *StubRule) WillReturnFileContent(bodyFileName string, headers map[string]string, status int64) *StubRule {
 	s.response.bodyFileName = &bodyFileName
-	s.response.headers = headers
+	s.response.setHeaders(headers)
 	s.response.status = status
 	return s
 }
@@ -119,23 +160,51 @@ func (s *StubRule) WillReturnFileContent(bodyFileName string, headers map[string
 // WillReturnJSON sets response with json body and returns *StubRule
 func (s *StubRule) WillReturnJSON(json interface{}, headers map[string]string, status int64) *StubRule {
 	s.response.jsonBody = json
-	s.response.headers = headers
+	s.response.setHeaders(headers)
 	s.response.status = status
 	return s
 }
 
-// WithFixedDelayMilliseconds sets fixed delay milliseconds for response
-func (s *StubRule) WithFixedDelayMilliseconds(time time.Duration) *StubRule {
-	s.response.fixedDelayMilliseconds = time
+// WithResponseHeaders adds the headers, with every value of the multi-value ones, to the response and returns *StubRule.
+// Set it after WillReturn and its siblings, which replace the response headers.
+func (s *StubRule) WithResponseHeaders(headers http.Header) *StubRule {
+	s.response.WithHeaders(headers)
+	return s
+}
+
+// WillReturnRedirect sets redirect response to location with 3xx status and returns *StubRule.
+// The status is not checked, so Location can be returned with other statuses too, e.g. 201 Created.
+func (s *StubRule) WillReturnRedirect(location string, status int64) *StubRule {
+	s.response.withHeader("Location", location)
+	s.response.status = status
+	return s
+}
+
+// WillReturnFault sets network fault instead of response and returns *StubRule
+func (s *StubRule) WillReturnFault(fault Fault) *StubRule {
+	s.response.fault = fault
 	return s
 }
 
+// WithFixedDelayMilliseconds sets fixed delay for response
+//
+// Deprecated: the delay is time.Duration, not milliseconds, use WithFixedDelay.
+func (s *StubRule) WithFixedDelayMilliseconds(time time.Duration) *StubRule {
+	return s.WithFixedDelay(time)
+}
+
 // WithBasicAuth adds basic auth credentials
 func (s *StubRule) WithBasicAuth(username, password string) *StubRule {
 	s.request.WithBasicAuth(username, password)
 	return s
 }
 
+// WithBearerToken adds the Authorization header matcher of the bearer token
+func (s *StubRule) WithBearerToken(matcher ParamMatcher) *StubRule {
+	s.request.WithBearerToken(matcher)
+	return s
+}
+
 // AtPriority sets priority and returns *StubRule
 func (s *StubRule) AtPriority(priority int64) *StubRule {
 	s.priority = &priority
@@ -160,77 +229,234 @@ func (s *StubRule) WillSetStateTo(scenarioState string) *StubRule {
 	return s
 }
 
+// WithMetadata adds metadata entry and returns *StubRule
+func (s *StubRule) WithMetadata(key string, value interface{}) *StubRule {
+	if s.metadata == nil {
+		s.metadata = map[string]interface{}{}
+	}
+
+	s.metadata[key] = value
+	return s
+}
+
+// Metadata is getter for metadata
+func (s *StubRule) Metadata() map[string]interface{} {
+	return s.metadata
+}
+
+// WithID sets uuid and returns *StubRule
+func (s *StubRule) WithID(uuid string) *StubRule {
+	s.uuid = uuid
+	s.idErr = nil
+	return s
+}
+
+// WithDeterministicID sets uuid derived from the stub content and returns *StubRule.
+// Equal stubs get equal ids, so it must be called after the stub is fully built.
+// When the stub cannot be encoded to JSON, e.g. its JSON body is a channel, the error is reported by Validate,
+// so StubFor fails rather than registering a duplicate under a random id. See DeterministicID to get the error.
+func (s *StubRule) WithDeterministicID() *StubRule {
+	uuid, err := s.DeterministicID()
+	if err != nil {
+		s.idErr = err
+		return s
+	}
+
+	s.uuid = uuid
+	s.idErr = nil
+	return s
+}
+
+// DeterministicID gives uuid derived from JSON of the stub without its id, see WithDeterministicID.
+// The stub is not changed, so it can be called from several goroutines.
+func (s *StubRule) DeterministicID() (string, error) {
+	stubRule := s.Clone()
+	stubRule.uuid = ""
+	// the expiry differs between runs and the version between updates, so they must not change the id
+	delete(stubRule.metadata, MetadataExpiresAt)
+	delete(stubRule.metadata, MetadataVersion)
+
+	content, err := json.Marshal(stubRule)
+	if err != nil {
+		return "", fmt.Errorf("stub id: %s", err.Error())
+	}
+
+	return uuidPkg.NewSHA1(stubIDNamespace, content).String(), nil
+}
+
 // UUID is getter for uuid
 func (s *StubRule) UUID() string {
 	return s.uuid
 }
 
+// ScenarioName gives the name of the scenario of the stub, empty when it is not set.
+func (s *StubRule) ScenarioName() string {
+	if s.scenarioName == nil {
+		return ""
+	}
+
+	return *s.scenarioName
+}
+
+// Priority gives priority, DefaultPriority when it is not set.
+func (s *StubRule) Priority() int64 {
+	if s.priority == nil {
+		return DefaultPriority
+	}
+
+	return *s.priority
+}
+
 // Post returns *StubRule for POST method.
-func Post(urlMatchingPair URLMatcher) *StubRule {
-	return NewStubRule(http.MethodPost, urlMatchingPair)
+func Post(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodPost, urlMatchingPair, options...)
 }
 
 // Get returns *StubRule for GET method.
-func Get(urlMatchingPair URLMatcher) *StubRule {
-	return NewStubRule(http.MethodGet, urlMatchingPair)
+func Get(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodGet, urlMatchingPair, options...)
 }
 
 // Delete returns *StubRule for DELETE method.
-func Delete(urlMatchingPair URLMatcher) *StubRule {
-	return NewStubRule(http.MethodDelete, urlMatchingPair)
+func Delete(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodDelete, urlMatchingPair, options...)
 }
 
 // Put returns *StubRule for PUT method.
-func Put(urlMatchingPair URLMatcher) *StubRule {
-	return NewStubRule(http.MethodPut, urlMatchingPair)
+func Put(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodPut, urlMatchingPair, options...)
 }
 
 // Patch returns *StubRule for PATCH method.
-func Patch(urlMatchingPair URLMatcher) *StubRule {
-	return NewStubRule(http.MethodPatch, urlMatchingPair)
+func Patch(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodPatch, urlMatchingPair, options...)
+}
+
+// Head returns *StubRule for HEAD method.
+func Head(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodHead, urlMatchingPair, options...)
+}
+
+// Options returns *StubRule for OPTIONS method.
+func Options(urlMatchingPair URLMatcher, options ...StubOption) *StubRule {
+	return NewStubRule(http.MethodOptions, urlMatchingPair, options...)
 }
 
 // MarshalJSON makes json body for http Request
 func (s *StubRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.jsonValue())
+}
+
+// jsonValue gives the JSON representation of StubRule made of plain values, so any JSON encoder can render it.
+func (s *StubRule) jsonValue() interface{} {
 	jsonStubRule := struct {
-		UUID                          string   `json:"uuid,omitempty"`
-		ID                            string   `json:"id,omitempty"`
-		Priority                      *int64   `json:"priority,omitempty"`
-		ScenarioName                  *string  `json:"scenarioName,omitempty"`
-		RequiredScenarioScenarioState *string  `json:"requiredScenarioState,omitempty"`
-		NewScenarioState              *string  `json:"newScenarioState,omitempty"`
-		Request                       *Request `json:"request"`
-		Response                      struct {
-			Body                   string            `json:"body,omitempty"`
-			Base64Body             string            `json:"base64Body,omitempty"`
-			BodyFileName           string            `json:"bodyFileName,omitempty"`
-			JSONBody               interface{}       `json:"jsonBody,omitempty"`
-			Headers                map[string]string `json:"headers,omitempty"`
-			Status                 int64             `json:"status,omitempty"`
-			FixedDelayMilliseconds int               `json:"fixedDelayMilliseconds,omitempty"`
-		} `json:"response"`
+		UUID                          string                 `json:"uuid,omitempty"`
+		ID                            string                 `json:"id,omitempty"`
+		Priority                      *int64                 `json:"priority,omitempty"`
+		ScenarioName                  *string                `json:"scenarioName,omitempty"`
+		RequiredScenarioScenarioState *string                `json:"requiredScenarioState,omitempty"`
+		NewScenarioState              *string                `json:"newScenarioState,omitempty"`
+		Request                       interface{}            `json:"request"`
+		Response                      interface{}            `json:"response"`
+		Metadata                      map[string]interface{} `json:"metadata,omitempty"`
+		PostServeActions              []json.RawMessage      `json:"postServeActions,omitempty"`
+		ServeEventListeners           []json.RawMessage      `json:"serveEventListeners,omitempty"`
 	}{}
 	jsonStubRule.Priority = s.priority
 	jsonStubRule.ScenarioName = s.scenarioName
 	jsonStubRule.RequiredScenarioScenarioState = s.requiredScenarioState
 	jsonStubRule.NewScenarioState = s.newScenarioState
-
-	if s.response.body != nil {
-		jsonStubRule.Response.Body = *s.response.body
-	} else if len(s.response.base64Body) > 0 {
-		jsonStubRule.Response.Base64Body = base64.StdEncoding.EncodeToString(s.response.base64Body)
-	} else if s.response.bodyFileName != nil {
-		jsonStubRule.Response.BodyFileName = *s.response.bodyFileName
-	} else if s.response.jsonBody != nil {
-		jsonStubRule.Response.JSONBody = s.response.jsonBody
+	if s.request != nil {
+		jsonStubRule.Request = s.request.jsonValue()
 	}
-
-	jsonStubRule.Response.Headers = s.response.headers
-	jsonStubRule.Response.Status = s.response.status
-	jsonStubRule.Response.FixedDelayMilliseconds = int(s.response.fixedDelayMilliseconds.Milliseconds())
-	jsonStubRule.Request = s.request
+	jsonStubRule.Response = s.response.jsonValue()
+	jsonStubRule.Metadata = s.metadata
+	jsonStubRule.PostServeActions = s.postServeActionsJSON()
+	jsonStubRule.ServeEventListeners = s.serveEventListeners
 	jsonStubRule.ID = s.uuid
 	jsonStubRule.UUID = s.uuid
 
-	return json.Marshal(jsonStubRule)
+	return jsonStubRule
+}
+
+// PrettyJSON gives indented JSON of StubRule.
+func (s *StubRule) PrettyJSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// String gives indented JSON of StubRule, suitable for logs and error messages.
+func (s *StubRule) String() string {
+	result, err := s.PrettyJSON()
+	if err != nil {
+		return fmt.Sprintf("<invalid stub rule %s: %s>", s.uuid, err.Error())
+	}
+
+	return string(result)
+}
+
+// UnmarshalJSON fills StubRule from WireMock stub mapping JSON.
+func (s *StubRule) UnmarshalJSON(data []byte) error {
+	jsonStubRule := struct {
+		UUID                          string                 `json:"uuid"`
+		ID                            string                 `json:"id"`
+		Priority                      *int64                 `json:"priority"`
+		ScenarioName                  *string                `json:"scenarioName"`
+		RequiredScenarioScenarioState *string                `json:"requiredScenarioState"`
+		NewScenarioState              *string                `json:"newScenarioState"`
+		Request                       *Request               `json:"request"`
+		Response                      Response               `json:"response"`
+		Metadata                      map[string]interface{} `json:"metadata"`
+		PostServeActions              []json.RawMessage      `json:"postServeActions"`
+		ServeEventListeners           []json.RawMessage      `json:"serveEventListeners"`
+	}{
+		Response: Response{status: http.StatusOK},
+	}
+	if err := json.Unmarshal(data, &jsonStubRule); err != nil {
+		return err
+	}
+
+	*s = StubRule{
+		uuid:                  jsonStubRule.ID,
+		request:               jsonStubRule.Request,
+		response:              jsonStubRule.Response,
+		priority:              jsonStubRule.Priority,
+		scenarioName:          jsonStubRule.ScenarioName,
+		requiredScenarioState: jsonStubRule.RequiredScenarioScenarioState,
+		newScenarioState:      jsonStubRule.NewScenarioState,
+		metadata:              jsonStubRule.Metadata,
+	}
+	if s.uuid == "" {
+		s.uuid = jsonStubRule.UUID
+	}
+	if s.request == nil {
+		s.request = &Request{}
+	}
+
+	var err error
+	if s.postServeActions, err = s.readWebhooks(jsonStubRule.PostServeActions); err != nil {
+		return err
+	}
+	if s.serveEventListeners, err = s.readWebhooks(jsonStubRule.ServeEventListeners); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func cloneInt64Ptr(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+
+	clone := *value
+	return &clone
+}
+
+func cloneStringPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	clone := *value
+	return &clone
 }
@@ -0,0 +1,160 @@
+package wiremock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A ServeEventSubscription delivers serve events logged after the subscription was made, oldest first,
+// polling the request journal in the background, see Client.SubscribeServeEvents.
+type ServeEventSubscription struct {
+	client   *Client
+	query    ServeEventQuery
+	interval time.Duration
+	events   chan ServeEvent
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// since is the date of the most recent delivered or skipped event, seen are the ids of events around it
+	since time.Time
+	seen  map[string]time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+// SubscribeServeEvents subscribes to serve events matching query, nil matches all, polled every interval.
+// Events logged before the subscription are skipped. The events channel is closed when ctx is done,
+// the subscription is closed or polling fails, then Err tells why:
+//
+//	subscription, err := client.SubscribeServeEvents(ctx, wiremock.NewServeEventQuery().WithUnmatched(), 100*time.Millisecond)
+//	...
+//	defer subscription.Close()
+//	for event := range subscription.Events() {
+//		t.Logf("unmatched %s %s", event.Request.Method, event.Request.URL)
+//	}
+//
+// The limit of the query bounds the events of every poll, so events may be missed when it is lower than
+// the events logged during the interval.
+func (c *Client) SubscribeServeEvents(ctx context.Context, query *ServeEventQuery, interval time.Duration) (*ServeEventSubscription, error) {
+	s := &ServeEventSubscription{
+		client:   c,
+		interval: interval,
+		events:   make(chan ServeEvent),
+		done:     make(chan struct{}),
+		seen:     map[string]time.Time{},
+	}
+	if query != nil {
+		s.query = *query
+	}
+
+	// events logged so far are seen, so only the later ones are delivered
+	events, err := s.poll()
+	if err != nil {
+		return nil, fmt.Errorf("subscribe serve events: %s", err.Error())
+	}
+	s.skip(events)
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// Events gives the channel of the new serve events.
+func (s *ServeEventSubscription) Events() <-chan ServeEvent {
+	return s.events
+}
+
+// Err gives the error which stopped polling, e.g. wrapping ErrRequestJournalDisabled,
+// nil while events are delivered and when ctx is done or the subscription is closed.
+func (s *ServeEventSubscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Close stops polling and waits until the events channel is closed.
+func (s *ServeEventSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *ServeEventSubscription) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.events)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		events, err := s.poll()
+		if err != nil {
+			if ctx.Err() == nil {
+				s.mu.Lock()
+				s.err = fmt.Errorf("serve event subscription: %w", err)
+				s.mu.Unlock()
+			}
+			return
+		}
+
+		// events are most recent first
+		for i := len(events) - 1; i >= 0; i-- {
+			if !s.skip(events[i : i+1]) {
+				continue
+			}
+
+			select {
+			case s.events <- events[i]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (s *ServeEventSubscription) poll() ([]ServeEvent, error) {
+	query := s.query
+	if !s.since.IsZero() {
+		// the journal keeps milliseconds, so events of the last seen millisecond may still be arriving
+		query.since = s.since.Add(-time.Millisecond)
+	}
+
+	return s.client.GetServeEvents(&query)
+}
+
+// skip marks events seen and reports whether any of them was not seen before.
+func (s *ServeEventSubscription) skip(events []ServeEvent) bool {
+	var unseen bool
+	for _, event := range events {
+		if _, ok := s.seen[event.ID]; ok {
+			continue
+		}
+		// older events were seen already, the server may ignore since
+		if event.Request.LoggedDate.Before(s.since.Add(-time.Millisecond)) {
+			continue
+		}
+		unseen = true
+		s.seen[event.ID] = event.Request.LoggedDate
+		if event.Request.LoggedDate.After(s.since) {
+			s.since = event.Request.LoggedDate
+		}
+	}
+
+	for id, loggedDate := range s.seen {
+		if loggedDate.Before(s.since.Add(-time.Millisecond)) {
+			delete(s.seen, id)
+		}
+	}
+
+	return unseen
+}
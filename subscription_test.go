@@ -0,0 +1,70 @@
+package wiremock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClient_SubscribeServeEvents(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	logged := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	logEvent := func(id string, at time.Time) {
+		server.logServeEvent(t, map[string]interface{}{
+			"id":      id,
+			"request": map[string]interface{}{"url": "/orders", "method": "GET", "loggedDate": at.UnixMilli()},
+		})
+	}
+	logEvent("before", logged)
+
+	subscription, err := client.SubscribeServeEvents(context.Background(), nil, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SubscribeServeEvents error: %v", err)
+	}
+	defer subscription.Close()
+
+	logEvent("first", logged)
+	logEvent("second", logged.Add(time.Second))
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case event := <-subscription.Events():
+			if event.ID != expected {
+				t.Errorf("expected event %s; got %s", expected, event.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected event %s delivered", expected)
+		}
+	}
+
+	logEvent("third", logged.Add(2*time.Second))
+	if event := <-subscription.Events(); event.ID != "third" {
+		t.Errorf("expected only new event third; got %s", event.ID)
+	}
+
+	subscription.Close()
+	if _, ok := <-subscription.Events(); ok || subscription.Err() != nil {
+		t.Errorf("expected events closed without error; got %v", subscription.Err())
+	}
+}
+
+func TestClient_SubscribeServeEvents_Error(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	subscription, err := client.SubscribeServeEvents(context.Background(), nil, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SubscribeServeEvents error: %v", err)
+	}
+	defer subscription.Close()
+
+	server.mu.Lock()
+	server.journalDisabled = true
+	server.mu.Unlock()
+
+	if _, ok := <-subscription.Events(); ok || !errors.Is(subscription.Err(), ErrRequestJournalDisabled) {
+		t.Errorf("expected events closed by disabled journal; got %v", subscription.Err())
+	}
+}
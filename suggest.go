@@ -0,0 +1,141 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// suggestParamFix gives the DSL change making the failed matcher match the actual value, empty when none is known.
+func suggestParamFix(matcher ParamMatcherInterface, actual string) string {
+	expected := matcher.Value()
+
+	switch matcher.Strategy() {
+	case ParamEqualTo:
+		switch {
+		case !matcher.Flags()["caseInsensitive"] && strings.EqualFold(actual, expected):
+			return fmt.Sprintf("the case differs, consider EqualToIgnoreCase(%q)", expected)
+		case strings.TrimSpace(actual) == expected:
+			return fmt.Sprintf("the value has surrounding whitespace, consider Matching(%q)", `\s*`+regexp.QuoteMeta(expected)+`\s*`)
+		case expected != "" && strings.Contains(actual, expected):
+			return fmt.Sprintf("the value has more around it, consider Contains(%q)", expected)
+		}
+	case ParamContains:
+		if strings.Contains(strings.ToLower(actual), strings.ToLower(expected)) {
+			return fmt.Sprintf("the case differs, consider Matching(%q)", "(?i).*"+regexp.QuoteMeta(expected)+".*")
+		}
+	case ParamMatches:
+		if re, err := regexp.Compile(expected); err == nil && re.MatchString(actual) {
+			return fmt.Sprintf("the pattern matches a part of the value, but the whole value is matched, consider Matching(%q)", ".*(?:"+expected+").*")
+		}
+	case ParamEqualToJson:
+		if len(matcher.Flags()) == 0 {
+			return suggestJSONFix(expected, actual)
+		}
+	}
+
+	return ""
+}
+
+// suggestJSONFix gives the flags of EqualToJson making the expected JSON match the actual one, empty when none does.
+func suggestJSONFix(expected, actual string) string {
+	var expectedValue, actualValue interface{}
+	if json.Unmarshal([]byte(expected), &expectedValue) != nil || json.Unmarshal([]byte(actual), &actualValue) != nil {
+		return ""
+	}
+
+	for _, flags := range [][]EqualFlag{{IgnoreExtraElements}, {IgnoreArrayOrder}, {IgnoreExtraElements, IgnoreArrayOrder}} {
+		if jsonMatches(expectedValue, actualValue, flags[0] == IgnoreExtraElements, flags[len(flags)-1] == IgnoreArrayOrder) {
+			names := make([]string, len(flags))
+			for i, flag := range flags {
+				names[i] = flagName(flag)
+			}
+			return fmt.Sprintf("consider EqualToJson(json, %s)", strings.Join(names, ", "))
+		}
+	}
+
+	return ""
+}
+
+func flagName(flag EqualFlag) string {
+	if flag == IgnoreExtraElements {
+		return "IgnoreExtraElements"
+	}
+
+	return "IgnoreArrayOrder"
+}
+
+// jsonMatches reports whether actual JSON value equals expected one, allowing extra object fields and array elements
+// with ignoreExtra and any order of array elements with ignoreOrder, the way WireMock flags of equalToJson do.
+func jsonMatches(expected, actual interface{}, ignoreExtra, ignoreOrder bool) bool {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok || (!ignoreExtra && len(actual) != len(expected)) {
+			return false
+		}
+		for key, value := range expected {
+			actualValue, ok := actual[key]
+			if !ok || !jsonMatches(value, actualValue, ignoreExtra, ignoreOrder) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok || (!ignoreExtra && len(actual) != len(expected)) || len(actual) < len(expected) {
+			return false
+		}
+		if !ignoreOrder {
+			for i := range expected {
+				if !jsonMatches(expected[i], actual[i], ignoreExtra, ignoreOrder) {
+					return false
+				}
+			}
+			return true
+		}
+		used := make([]bool, len(actual))
+		for _, value := range expected {
+			found := false
+			for i := range actual {
+				if !used[i] && jsonMatches(value, actual[i], ignoreExtra, ignoreOrder) {
+					used[i], found = true, true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+// suggestURLFix gives the DSL change making the failed url matcher match the actual url, empty when none is known.
+func suggestURLFix(matcher URLMatcherInterface, url string) string {
+	path := url
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		path = url[:i]
+	}
+	expected := matcher.Value()
+
+	switch matcher.Strategy() {
+	case URLEqualToRule:
+		if path != url && path == expected {
+			return fmt.Sprintf("URLEqualTo matches the query too, consider URLPathEqualTo(%q)", expected)
+		}
+	case URLPathEqualToRule:
+		switch {
+		case strings.TrimSuffix(path, "/") == strings.TrimSuffix(expected, "/"):
+			return fmt.Sprintf("the trailing slash differs, consider URLPathEqualTo(%q).IgnoreTrailingSlash()", expected)
+		case strings.EqualFold(path, expected):
+			return fmt.Sprintf("the case differs, consider URLPathMatching(%q)", "(?i)"+regexp.QuoteMeta(expected))
+		}
+	}
+
+	return ""
+}
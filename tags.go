@@ -0,0 +1,73 @@
+package wiremock
+
+import "fmt"
+
+// MetadataTags is the metadata key of the stub tags set by WithTag.
+const MetadataTags = "tags"
+
+// WithTag adds tags grouping the stub, e.g. by the test suite registering it, and returns *StubRule
+func (s *StubRule) WithTag(tags ...string) *StubRule {
+	merged := s.Tags()
+	for _, tag := range tags {
+		if !containsTag(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+
+	return s.WithMetadata(MetadataTags, merged)
+}
+
+// Tags gives the stub tags set by WithTag.
+func (s *StubRule) Tags() []string {
+	switch tags := s.metadata[MetadataTags].(type) {
+	case []string:
+		return append([]string(nil), tags...)
+	case []interface{}:
+		// metadata read from the server
+		result := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok {
+				result = append(result, tag)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// HasTag reports whether the stub is tagged with tag.
+func (s *StubRule) HasTag(tag string) bool {
+	return containsTag(s.Tags(), tag)
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeleteByTag deletes stub mappings tagged with tag and returns their ids.
+func (c *Client) DeleteByTag(tag string) ([]string, error) {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("delete by tag: %w", err)
+	}
+
+	var tagged []string
+	for _, stubRule := range stubs {
+		if stubRule.HasTag(tag) {
+			tagged = append(tagged, stubRule.UUID())
+		}
+	}
+
+	if err := c.DeleteStubs(tagged...); err != nil {
+		return nil, fmt.Errorf("delete by tag: %w", err)
+	}
+
+	return tagged, nil
+}
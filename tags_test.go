@@ -0,0 +1,39 @@
+package wiremock
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient_DeleteByTag(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	checkout := Get(URLPathEqualTo("/cart")).WithTag("checkout-tests").WithTag("smoke", "checkout-tests", "smoke")
+	search := Get(URLPathEqualTo("/search")).WithTag("search-tests")
+	for _, stubRule := range []*StubRule{checkout, search} {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(checkout.Tags(), []string{"checkout-tests", "smoke"}) {
+		t.Errorf("unexpected tags %v", checkout.Tags())
+	}
+
+	registered, err := client.GetStub(checkout.UUID())
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if !registered.HasTag("smoke") {
+		t.Errorf("expected tags to survive registration; got %v", registered.Metadata())
+	}
+
+	deleted, err := client.DeleteByTag("checkout-tests")
+	if err != nil {
+		t.Fatalf("DeleteByTag error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != checkout.UUID() || server.mappingCount() != 1 {
+		t.Errorf("expected only checkout stub to be deleted; got %v, %d left", deleted, server.mappingCount())
+	}
+}
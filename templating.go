@@ -0,0 +1,127 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/walkerus/go-wiremock/tmpl"
+)
+
+// WithTransformers adds response transformers and returns *StubRule
+func (s *StubRule) WithTransformers(transformers ...string) *StubRule {
+	for _, transformer := range transformers {
+		if !s.response.hasTransformer(transformer) {
+			s.response.transformers = append(s.response.transformers, transformer)
+		}
+	}
+
+	return s
+}
+
+// WithTransformerParameter sets the parameter passed to response transformers and returns *StubRule.
+// The value must be JSON serializable.
+func (s *StubRule) WithTransformerParameter(key string, value interface{}) *StubRule {
+	if s.response.transformerParameters == nil {
+		s.response.transformerParameters = map[string]interface{}{}
+	}
+	s.response.transformerParameters[key] = value
+
+	return s
+}
+
+// WithTransformerParameters sets parameters passed to response transformers and returns *StubRule
+func (s *StubRule) WithTransformerParameters(parameters map[string]interface{}) *StubRule {
+	for key, value := range parameters {
+		s.WithTransformerParameter(key, value)
+	}
+
+	return s
+}
+
+// WithResponseTemplating enables response templating of body and headers and returns *StubRule
+func (s *StubRule) WithResponseTemplating() *StubRule {
+	return s.WithTransformers(ResponseTemplateTransformer)
+}
+
+// WithGlobalResponseTemplating makes the client enable response templating of every stub it registers,
+// like WireMock started with --global-response-templating, which cannot be switched at runtime.
+// Registered stubs are sent with the transformer while the *StubRule values stay untouched.
+func WithGlobalResponseTemplating() ClientOption {
+	return func(c *Client) {
+		c.responseTemplating = true
+	}
+}
+
+// stubJSON gives the JSON representation of the stub registered by the client.
+func (c *Client) stubJSON(stubRule *StubRule) interface{} {
+	if c.responseTemplating && !stubRule.response.hasTransformer(ResponseTemplateTransformer) {
+		stubRule = stubRule.copy().WithResponseTemplating()
+	}
+
+	return stubRule.jsonValue()
+}
+
+// WillReturnTemplated sets response with templated body and headers, enables response templating and returns *StubRule.
+// Expressions can be built with the tmpl package.
+func (s *StubRule) WillReturnTemplated(body string, headers map[string]string, status int64) *StubRule {
+	return s.WillReturn(body, headers, status).WithResponseTemplating()
+}
+
+// WillEchoRequestBody sets 200 response repeating the request body and its Content-Type and returns *StubRule
+func (s *StubRule) WillEchoRequestBody() *StubRule {
+	return s.WillReturnTemplated(
+		tmpl.RequestBody().Raw(),
+		map[string]string{"Content-Type": tmpl.RequestHeader("Content-Type").Raw()},
+		http.StatusOK,
+	)
+}
+
+// PreviewResponse renders the templated response body and headers locally against the sample request
+// and returns the rendered copy of the response. The response is returned as is when templating is not enabled.
+// See tmpl.Render for the supported helpers.
+func (s *StubRule) PreviewResponse(request tmpl.Request) (*Response, error) {
+	preview := s.response.Clone()
+	if !preview.hasTransformer(ResponseTemplateTransformer) {
+		return preview, nil
+	}
+
+	if preview.jsonBody != nil {
+		body, err := json.Marshal(preview.jsonBody)
+		if err != nil {
+			return nil, fmt.Errorf("jsonBody: %s", err.Error())
+		}
+		rendered, err := tmpl.Render(string(body), request)
+		if err != nil {
+			return nil, fmt.Errorf("jsonBody: %w", err)
+		}
+		preview.jsonBody = nil
+		preview.body = &rendered
+	} else if preview.body != nil {
+		rendered, err := tmpl.Render(*preview.body, request)
+		if err != nil {
+			return nil, fmt.Errorf("body: %w", err)
+		}
+		preview.body = &rendered
+	}
+
+	for key, value := range preview.headers {
+		rendered, err := tmpl.Render(value, request)
+		if err != nil {
+			return nil, fmt.Errorf("headers[%s]: %w", key, err)
+		}
+		preview.headers[key] = rendered
+	}
+
+	return preview, nil
+}
+
+func (r *Response) hasTransformer(transformer string) bool {
+	for _, existing := range r.transformers {
+		if existing == transformer {
+			return true
+		}
+	}
+
+	return false
+}
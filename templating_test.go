@@ -0,0 +1,96 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock/tmpl"
+)
+
+func TestStubRule_WillEchoRequestBody(t *testing.T) {
+	stubRule := Post(URLPathEqualTo("/echo")).WillEchoRequestBody().WithResponseTemplating()
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	for _, expected := range []string{
+		`"body":"{{{request.body}}}"`,
+		`"headers":{"Content-Type":"{{{request.headers.Content-Type}}}"}`,
+		`"transformers":["response-template"]`,
+	} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected stub to contain %s; got %s", expected, raw)
+		}
+	}
+}
+
+func TestStubRule_PreviewResponse(t *testing.T) {
+	stubRule := Post(URLPathEqualTo("/echo")).WillEchoRequestBody()
+
+	preview, err := stubRule.PreviewResponse(tmpl.Request{
+		Method:  "POST",
+		URL:     "/echo",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    `{"id":1}`,
+	})
+	if err != nil {
+		t.Fatalf("PreviewResponse error: %v", err)
+	}
+	if preview.Body() != `{"id":1}` || preview.Headers()["Content-Type"] != "application/json" {
+		t.Errorf("unexpected preview %q %v", preview.Body(), preview.Headers())
+	}
+	if stubRule.Response().Body() != tmpl.RequestBody().Raw() {
+		t.Errorf("expected stub response to stay templated; got %q", stubRule.Response().Body())
+	}
+
+	_, err = Get(URLPathEqualTo("/broken")).WillReturnTemplated("{{#if request.body}}", nil, 200).PreviewResponse(tmpl.Request{})
+	if err == nil || !strings.HasPrefix(err.Error(), "body: ") {
+		t.Errorf("expected body error; got %v", err)
+	}
+}
+
+func TestStubRule_WithTransformerParameters(t *testing.T) {
+	stubRule := Get(URLPathEqualTo("/signed")).
+		WillReturn("ok", nil, 200).
+		WithTransformers("signer").
+		WithTransformerParameter("algorithm", "HS256").
+		WithTransformerParameters(map[string]interface{}{"ttl": 60})
+
+	raw, err := stubRule.MarshalJSON()
+	if err != nil {
+		t.Fatalf("StubRule MarshalJSON error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"transformerParameters":{"algorithm":"HS256","ttl":60}`) {
+		t.Errorf("expected transformer parameters; got %s", raw)
+	}
+
+	var decoded StubRule
+	if err := decoded.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("StubRule UnmarshalJSON error: %v", err)
+	}
+	if decoded.Response().TransformerParameters()["algorithm"] != "HS256" {
+		t.Errorf("expected decoded transformer parameters; got %v", decoded.Response().TransformerParameters())
+	}
+}
+
+func TestWithGlobalResponseTemplating(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL, WithGlobalResponseTemplating())
+
+	stubRule := Get(URLPathEqualTo("/hello")).WillReturn("hello {{request.query.name}}", nil, 200)
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+	if len(stubRule.Response().Transformers()) != 0 {
+		t.Errorf("expected stub to stay untouched; got %v", stubRule.Response().Transformers())
+	}
+
+	registered, err := client.GetStub(stubRule.UUID())
+	if err != nil {
+		t.Fatalf("GetStub error: %v", err)
+	}
+	if transformers := registered.Response().Transformers(); len(transformers) != 1 || transformers[0] != ResponseTemplateTransformer {
+		t.Errorf("expected registered stub templated; got %v", transformers)
+	}
+}
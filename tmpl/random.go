@@ -0,0 +1,103 @@
+package tmpl
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of randomValue helper.
+const (
+	RandomUUID         = "UUID"
+	RandomAlphanumeric = "ALPHANUMERIC"
+	RandomAlphabetic   = "ALPHABETIC"
+	RandomNumeric      = "NUMERIC"
+	RandomHexadecimal  = "HEXADECIMAL"
+	RandomAlphaSymbols = "ALPHANUMERIC_AND_SYMBOLS"
+)
+
+// UUID returns the randomValue helper generating random UUID.
+func UUID() Expr {
+	return RandomValue(RandomUUID)
+}
+
+// RandomString returns the randomValue helper generating string of kind and length.
+func RandomString(kind string, length int64, uppercase bool) Expr {
+	expr := RandomValue(kind).With("length", Int(length))
+	if uppercase {
+		expr = expr.With("uppercase", Bool(true))
+	}
+
+	return expr
+}
+
+// RandomInt returns the randomInt helper generating integer in [lower, upper].
+func RandomInt(lower, upper int64) Expr {
+	return Helper("randomInt").With("lower", Int(lower)).With("upper", Int(upper))
+}
+
+// RandomDecimal returns the randomDecimal helper generating decimal in [lower, upper].
+func RandomDecimal(lower, upper float64) Expr {
+	return Helper("randomDecimal").With("lower", Float(lower)).With("upper", Float(upper))
+}
+
+// PickRandom returns the pickRandom helper choosing one of values.
+func PickRandom(values ...string) Expr {
+	args := make([]Value, len(values))
+	for i, value := range values {
+		args[i] = String(value)
+	}
+
+	return Helper("pickRandom", args...)
+}
+
+// Faker returns the random helper of the WireMock faker extension, e.g. Faker("Name.firstName").
+// The extension must be loaded by the server.
+func Faker(expression string) Expr {
+	return Helper("random", String(expression))
+}
+
+// A DateExpr is the date rendering helper with date options.
+type DateExpr struct {
+	Expr
+}
+
+// Now returns the now helper rendering current date.
+func Now() DateExpr {
+	return DateExpr{Helper("now")}
+}
+
+// Date returns the date helper rendering the fixed date instead of the current one,
+// so templated dates are reproducible, e.g. Date(base).Offset("3 days").Format("yyyy-MM-dd").
+func Date(date time.Time) DateExpr {
+	return DateOf(ParseDate(String(date.UTC().Format(time.RFC3339))))
+}
+
+// DateOf returns the date helper rendering the date value, e.g. DateOf(ParseDate(Ref("request.headers.X-Since"))).
+func DateOf(date Value) DateExpr {
+	return DateExpr{Helper("date", date)}
+}
+
+// ParseDate returns the parseDate helper parsing the ISO 8601 date of value.
+func ParseDate(value Value) Expr {
+	return Helper("parseDate", value)
+}
+
+// Offset shifts the date by WireMock offset, e.g. "3 days" or "-24 seconds", and returns DateExpr
+func (d DateExpr) Offset(offset string) DateExpr {
+	return DateExpr{d.With("offset", String(offset))}
+}
+
+// OffsetBy shifts the date by offset rounded to seconds and returns DateExpr
+func (d DateExpr) OffsetBy(offset time.Duration) DateExpr {
+	return d.Offset(fmt.Sprintf("%d seconds", int64(offset.Round(time.Second)/time.Second)))
+}
+
+// Format sets Java date format, e.g. "yyyy-MM-dd", or "epoch" and "unix", and returns DateExpr
+func (d DateExpr) Format(format string) DateExpr {
+	return DateExpr{d.With("format", String(format))}
+}
+
+// Timezone sets timezone of the rendered date, e.g. "Europe/Berlin", and returns DateExpr
+func (d DateExpr) Timezone(timezone string) DateExpr {
+	return DateExpr{d.With("timezone", String(timezone))}
+}
@@ -0,0 +1,1065 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnsupported is returned by Render for templates using features which are not rendered locally,
+// e.g. the xPath helper or the each block.
+var ErrUnsupported = errors.New("unsupported by local rendering")
+
+// A Request is the sample request the template is rendered against.
+type Request struct {
+	Method string
+	// URL is the path with the query, e.g. /orders/1?expand=items.
+	URL     string
+	Headers map[string]string
+	Cookies map[string]string
+	Body    string
+}
+
+// Render renders template against request the way WireMock response templating does,
+// so template bugs are caught when the stub is built.
+// The request model and the helpers jsonPath, randomValue, randomInt, randomDecimal, pickRandom, now, date, parseDate,
+// eq, contains and the if and unless blocks are implemented, other helpers fail with ErrUnsupported.
+func Render(template string, request Request) (string, error) {
+	return renderer{now: time.Now}.render(template, request)
+}
+
+// RenderAt renders template like Render with the clock fixed at now, so the now helper renders reproducibly.
+func RenderAt(template string, request Request, now time.Time) (string, error) {
+	return renderer{now: func() time.Time { return now }}.render(template, request)
+}
+
+type renderer struct {
+	now func() time.Time
+}
+
+func (r renderer) render(template string, request Request) (string, error) {
+	nodes, err := parseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	model, err := requestModel(request)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := r.renderNodes(&sb, nodes, model); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// listValue is the multi-value request field, rendering its first value.
+type listValue []string
+
+// headersValue is the request headers map with case-insensitive lookup.
+type headersValue map[string]string
+
+func requestModel(request Request) (map[string]interface{}, error) {
+	parsed, err := url.Parse(request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request url: %s", err.Error())
+	}
+
+	segments := listValue{}
+	for _, segment := range strings.Split(strings.Trim(parsed.Path, "/"), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	query := map[string]interface{}{}
+	for key, values := range parsed.Query() {
+		query[key] = listValue(values)
+	}
+
+	headers := headersValue{}
+	for key, value := range request.Headers {
+		headers[strings.ToLower(key)] = value
+	}
+
+	cookies := map[string]interface{}{}
+	for key, value := range request.Cookies {
+		cookies[key] = value
+	}
+
+	method := request.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"url":          request.URL,
+			"path":         parsed.Path,
+			"pathSegments": segments,
+			"query":        query,
+			"method":       method,
+			"headers":      headers,
+			"cookies":      cookies,
+			"body":         request.Body,
+		},
+	}, nil
+}
+
+type node interface{}
+
+type textNode string
+
+type mustacheNode struct {
+	expr *exprNode
+	raw  bool
+}
+
+type blockNode struct {
+	expr      *exprNode
+	body      []node
+	otherwise []node
+}
+
+type exprNode struct {
+	params []paramNode
+	hash   map[string]paramNode
+}
+
+type paramNode interface{}
+
+type literalParam struct {
+	value interface{}
+}
+
+type pathParam []string
+
+func parseTemplate(template string) ([]node, error) {
+	p := &templateParser{template: template}
+	nodes, closing, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	if closing != "" {
+		return nil, fmt.Errorf("unexpected {{%s}}", closing)
+	}
+
+	return nodes, nil
+}
+
+type templateParser struct {
+	template string
+	pos      int
+}
+
+// parseNodes parses nodes up to the end of the template or the {{else}} or {{/...}} tag, which is returned.
+func (p *templateParser) parseNodes() ([]node, string, error) {
+	var nodes []node
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.template) {
+		rest := p.template[p.pos:]
+		if strings.HasPrefix(rest, `\{{`) {
+			text.WriteString("{{")
+			p.pos += 3
+			continue
+		}
+		if !strings.HasPrefix(rest, "{{") {
+			text.WriteByte(p.template[p.pos])
+			p.pos++
+			continue
+		}
+
+		flush()
+
+		if strings.HasPrefix(rest, "{{{") {
+			end := strings.Index(rest, "}}}")
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed {{{ at %d", p.pos)
+			}
+			expr, err := parseExpr(rest[3:end])
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, &mustacheNode{expr: expr, raw: true})
+			p.pos += end + 3
+			continue
+		}
+
+		if strings.HasPrefix(rest, "{{!--") {
+			end := strings.Index(rest, "--}}")
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed comment at %d", p.pos)
+			}
+			p.pos += end + 4
+			continue
+		}
+
+		end := strings.Index(rest, "}}")
+		if end < 0 {
+			return nil, "", fmt.Errorf("unclosed {{ at %d", p.pos)
+		}
+		content := strings.TrimSpace(rest[2:end])
+		p.pos += end + 2
+
+		switch {
+		case strings.HasPrefix(content, "!"):
+		case content == "else" || strings.HasPrefix(content, "/"):
+			return nodes, content, nil
+		case strings.HasPrefix(content, "#"):
+			block, err := p.parseBlock(content[1:])
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, block)
+		default:
+			expr, err := parseExpr(content)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, &mustacheNode{expr: expr})
+		}
+	}
+
+	flush()
+
+	return nodes, "", nil
+}
+
+func (p *templateParser) parseBlock(content string) (*blockNode, error) {
+	expr, err := parseExpr(content)
+	if err != nil {
+		return nil, err
+	}
+	name := helperName(expr)
+	if name == "" {
+		return nil, fmt.Errorf("{{#%s}}: block helper name expected", content)
+	}
+
+	block := &blockNode{expr: expr}
+	body, closing, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	block.body = body
+
+	if closing == "else" {
+		if block.otherwise, closing, err = p.parseNodes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if closing != "/"+name {
+		return nil, fmt.Errorf("{{#%s}}: {{/%s}} expected", content, name)
+	}
+
+	return block, nil
+}
+
+func parseExpr(content string) (*exprNode, error) {
+	tokens, err := tokenize(content)
+	if err != nil {
+		return nil, fmt.Errorf("{{%s}}: %s", content, err.Error())
+	}
+
+	expr, rest, err := parseTokens(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("{{%s}}: %s", content, err.Error())
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("{{%s}}: unexpected %s", content, rest[0])
+	}
+	if len(expr.params) == 0 {
+		return nil, fmt.Errorf("{{%s}}: empty expression", content)
+	}
+
+	return expr, nil
+}
+
+// tokenize splits expression into parens, quoted strings, key= prefixes and bare words.
+func tokenize(content string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'' || c == '"':
+			end := i + 1
+			for ; end < len(content) && content[end] != c; end++ {
+				if content[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(content) {
+				return nil, errors.New("unclosed string")
+			}
+			tokens = append(tokens, content[i:end+1])
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(content); end++ {
+				ch := content[end]
+				if ch == '[' {
+					closing := strings.IndexByte(content[end:], ']')
+					if closing < 0 {
+						return nil, errors.New("unclosed [")
+					}
+					end += closing
+					continue
+				}
+				if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '(' || ch == ')' {
+					break
+				}
+				if ch == '=' {
+					end++
+					break
+				}
+			}
+			tokens = append(tokens, content[i:end])
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+func parseTokens(tokens []string) (*exprNode, []string, error) {
+	expr := &exprNode{hash: map[string]paramNode{}}
+	for len(tokens) > 0 && tokens[0] != ")" {
+		key := ""
+		if strings.HasSuffix(tokens[0], "=") {
+			key = strings.TrimSuffix(tokens[0], "=")
+			tokens = tokens[1:]
+			if len(tokens) == 0 {
+				return nil, nil, fmt.Errorf("value of %s expected", key)
+			}
+		}
+
+		var param paramNode
+		var err error
+		param, tokens, err = parseParam(tokens)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if key != "" {
+			expr.hash[key] = param
+		} else {
+			expr.params = append(expr.params, param)
+		}
+	}
+
+	return expr, tokens, nil
+}
+
+func parseParam(tokens []string) (paramNode, []string, error) {
+	token := tokens[0]
+	switch {
+	case token == "(":
+		sub, rest, err := parseTokens(tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			return nil, nil, errors.New("unclosed (")
+		}
+		if len(sub.params) == 0 {
+			return nil, nil, errors.New("empty sub-expression")
+		}
+		return sub, rest[1:], nil
+	case token[0] == '\'' || token[0] == '"':
+		return literalParam{value: unquote(token)}, tokens[1:], nil
+	case token == "true" || token == "false":
+		return literalParam{value: token == "true"}, tokens[1:], nil
+	}
+
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return literalParam{value: number}, tokens[1:], nil
+	}
+
+	return splitPath(token), tokens[1:], nil
+}
+
+// splitPath splits dotted path into segments, keeping [] quoted segments whole.
+func splitPath(path string) pathParam {
+	var segments pathParam
+	for path != "" {
+		if strings.HasPrefix(path, "[") {
+			end := strings.IndexByte(path, ']')
+			segments = append(segments, path[1:end])
+			path = strings.TrimPrefix(path[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexByte(path, '.')
+		if end < 0 {
+			end = len(path)
+		}
+		segments = append(segments, path[:end])
+		path = strings.TrimPrefix(path[end:], ".")
+	}
+
+	return segments
+}
+
+func unquote(token string) string {
+	var sb strings.Builder
+	for i := 1; i < len(token)-1; i++ {
+		if token[i] == '\\' && i+1 < len(token)-1 {
+			i++
+		}
+		sb.WriteByte(token[i])
+	}
+
+	return sb.String()
+}
+
+// helperName returns name of the helper called by expression or empty string for the plain reference.
+func helperName(expr *exprNode) string {
+	path, ok := expr.params[0].(pathParam)
+	if !ok || len(path) != 1 {
+		return ""
+	}
+	if _, known := helpers[path[0]]; known || len(expr.params) > 1 || len(expr.hash) > 0 {
+		return path[0]
+	}
+	switch path[0] {
+	case "if", "unless", "each", "with":
+		return path[0]
+	}
+
+	return ""
+}
+
+func (r renderer) renderNodes(sb *strings.Builder, nodes []node, model map[string]interface{}) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			sb.WriteString(string(n))
+		case *mustacheNode:
+			value, err := r.evalExpr(n.expr, model)
+			if err != nil {
+				return err
+			}
+			text, err := format(value)
+			if err != nil {
+				return err
+			}
+			if !n.raw {
+				text = escapeHTML(text)
+			}
+			sb.WriteString(text)
+		case *blockNode:
+			if err := r.renderBlock(sb, n, model); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r renderer) renderBlock(sb *strings.Builder, block *blockNode, model map[string]interface{}) error {
+	name := helperName(block.expr)
+	if name != "if" && name != "unless" {
+		return fmt.Errorf("{{#%s}}: %w", name, ErrUnsupported)
+	}
+	if len(block.expr.params) != 2 {
+		return fmt.Errorf("{{#%s}}: exactly one condition expected", name)
+	}
+
+	condition, err := r.evalParam(block.expr.params[1], model)
+	if err != nil {
+		return err
+	}
+
+	if truthy(condition) == (name == "if") {
+		return r.renderNodes(sb, block.body, model)
+	}
+
+	return r.renderNodes(sb, block.otherwise, model)
+}
+
+func (r renderer) evalExpr(expr *exprNode, model map[string]interface{}) (interface{}, error) {
+	name := helperName(expr)
+	if name == "" {
+		if len(expr.params) > 1 || len(expr.hash) > 0 {
+			return nil, errors.New("helper name expected")
+		}
+		return r.evalParam(expr.params[0], model)
+	}
+
+	helper, ok := helpers[name]
+	if !ok {
+		return nil, fmt.Errorf("helper %s: %w", name, ErrUnsupported)
+	}
+
+	args := make([]interface{}, 0, len(expr.params)-1)
+	for _, param := range expr.params[1:] {
+		value, err := r.evalParam(param, model)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	hash := make(map[string]interface{}, len(expr.hash))
+	for key, param := range expr.hash {
+		value, err := r.evalParam(param, model)
+		if err != nil {
+			return nil, err
+		}
+		hash[key] = value
+	}
+
+	value, err := helper(r, args, hash)
+	if err != nil {
+		return nil, fmt.Errorf("helper %s: %s", name, err.Error())
+	}
+
+	return value, nil
+}
+
+func (r renderer) evalParam(param paramNode, model map[string]interface{}) (interface{}, error) {
+	switch param := param.(type) {
+	case literalParam:
+		return param.value, nil
+	case *exprNode:
+		return r.evalExpr(param, model)
+	case pathParam:
+		return lookup(model, param), nil
+	}
+
+	return nil, nil
+}
+
+func lookup(value interface{}, path []string) interface{} {
+	for _, segment := range path {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			value = current[segment]
+		case headersValue:
+			header, ok := current[strings.ToLower(segment)]
+			if !ok {
+				return nil
+			}
+			value = header
+		case listValue:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil
+			}
+			value = current[index]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil
+			}
+			value = current[index]
+		default:
+			return nil
+		}
+	}
+
+	return value
+}
+
+func format(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case time.Time:
+		return value.Format(time.RFC3339), nil
+	case listValue:
+		if len(value) == 0 {
+			return "", nil
+		}
+		return value[0], nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func truthy(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return false
+	case string:
+		return value != ""
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case int64:
+		return value != 0
+	case listValue:
+		return len(value) > 0
+	case []interface{}:
+		return len(value) > 0
+	}
+
+	return true
+}
+
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&#x27;",
+	"`", "&#x60;",
+	"=", "&#x3D;",
+)
+
+func escapeHTML(text string) string {
+	return htmlEscaper.Replace(text)
+}
+
+type helperFunc func(r renderer, args []interface{}, hash map[string]interface{}) (interface{}, error)
+
+var helpers = map[string]helperFunc{
+	"jsonPath":      jsonPathHelper,
+	"randomValue":   randomValueHelper,
+	"randomInt":     randomIntHelper,
+	"randomDecimal": randomDecimalHelper,
+	"pickRandom":    pickRandomHelper,
+	"now":           nowHelper,
+	"date":          dateHelper,
+	"parseDate":     parseDateHelper,
+	"eq":            eqHelper,
+	"contains":      containsHelper,
+}
+
+func stringArg(args []interface{}, index int) (string, error) {
+	if index >= len(args) {
+		return "", fmt.Errorf("argument %d expected", index+1)
+	}
+
+	return format(args[index])
+}
+
+func numberHash(hash map[string]interface{}, key string, fallback float64) (float64, error) {
+	value, ok := hash[key]
+	if !ok {
+		return fallback, nil
+	}
+	if number, ok := value.(float64); ok {
+		return number, nil
+	}
+
+	text, err := format(value)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: number expected, got %q", key, text)
+	}
+
+	return number, nil
+}
+
+func jsonPathHelper(_ renderer, args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	source, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	expression, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(source), &document); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err.Error())
+	}
+
+	return evalJSONPath(document, expression)
+}
+
+// evalJSONPath evaluates definite JSONPath of member and index steps, e.g. $.items[0]['unit price'].
+func evalJSONPath(document interface{}, expression string) (interface{}, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("%q: must start with $", expression)
+	}
+
+	value := document
+	for rest := expression[1:]; rest != ""; {
+		var segment string
+		switch {
+		case strings.HasPrefix(rest, ".."), strings.HasPrefix(rest, ".*"), strings.HasPrefix(rest, "[*"), strings.HasPrefix(rest, "[?"):
+			return nil, fmt.Errorf("%q: %w", expression, ErrUnsupported)
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			segment, rest = rest[1:end+1], rest[end+1:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%q: unclosed [", expression)
+			}
+			segment, rest = strings.Trim(rest[1:end], `'"`), rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%q: unexpected %q", expression, rest)
+		}
+
+		switch current := value.(type) {
+		case map[string]interface{}:
+			value = current[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, nil
+			}
+			if index < 0 {
+				index += len(current)
+			}
+			if index < 0 || index >= len(current) {
+				return nil, nil
+			}
+			value = current[index]
+		default:
+			return nil, nil
+		}
+	}
+
+	return value, nil
+}
+
+var randomAlphabets = map[string]string{
+	RandomAlphanumeric: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	RandomAlphabetic:   "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	RandomNumeric:      "0123456789",
+	RandomHexadecimal:  "0123456789abcdef",
+	RandomAlphaSymbols: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{};:,.<>/?",
+}
+
+func randomValueHelper(_ renderer, _ []interface{}, hash map[string]interface{}) (interface{}, error) {
+	kind, _ := hash["type"].(string)
+	if kind == RandomUUID {
+		return uuid.NewString(), nil
+	}
+
+	alphabet, ok := randomAlphabets[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", kind)
+	}
+
+	length, err := numberHash(hash, "length", 36)
+	if err != nil {
+		return nil, err
+	}
+
+	value := make([]byte, int(length))
+	for i := range value {
+		value[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+
+	if uppercase, _ := hash["uppercase"].(bool); uppercase {
+		return strings.ToUpper(string(value)), nil
+	}
+
+	return string(value), nil
+}
+
+func randomIntHelper(_ renderer, _ []interface{}, hash map[string]interface{}) (interface{}, error) {
+	lower, err := numberHash(hash, "lower", 0)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := numberHash(hash, "upper", 1<<31-1)
+	if err != nil {
+		return nil, err
+	}
+	if upper < lower {
+		return nil, fmt.Errorf("upper %v is less than lower %v", upper, lower)
+	}
+
+	return int64(lower) + rand.Int63n(int64(upper)-int64(lower)+1), nil
+}
+
+func randomDecimalHelper(_ renderer, _ []interface{}, hash map[string]interface{}) (interface{}, error) {
+	lower, err := numberHash(hash, "lower", 0)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := numberHash(hash, "upper", 1<<31-1)
+	if err != nil {
+		return nil, err
+	}
+	if upper < lower {
+		return nil, fmt.Errorf("upper %v is less than lower %v", upper, lower)
+	}
+
+	return lower + rand.Float64()*(upper-lower), nil
+}
+
+func pickRandomHelper(_ renderer, args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, errors.New("at least one value expected")
+	}
+
+	return args[rand.Intn(len(args))], nil
+}
+
+func nowHelper(r renderer, _ []interface{}, hash map[string]interface{}) (interface{}, error) {
+	return formatDate(r.now(), hash)
+}
+
+func dateHelper(_ renderer, args []interface{}, hash map[string]interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, errors.New("date expected")
+	}
+
+	date, ok := args[0].(time.Time)
+	if !ok {
+		text, err := format(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if date, err = time.Parse(time.RFC3339, text); err != nil {
+			return nil, fmt.Errorf("date %q: %s", text, err.Error())
+		}
+	}
+
+	return formatDate(date, hash)
+}
+
+func parseDateHelper(_ renderer, args []interface{}, hash map[string]interface{}) (interface{}, error) {
+	text, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := time.RFC3339
+	if javaLayout, ok := hash["format"].(string); ok {
+		if layout, err = javaDateLayout(javaLayout); err != nil {
+			return nil, err
+		}
+	}
+
+	date, err := time.Parse(layout, text)
+	if err != nil {
+		return nil, fmt.Errorf("parse date %q: %s", text, err.Error())
+	}
+
+	return date, nil
+}
+
+// formatDate renders the date with the offset, timezone and format options of the now and date helpers.
+func formatDate(date time.Time, hash map[string]interface{}) (interface{}, error) {
+	if offset, ok := hash["offset"]; ok {
+		text, err := format(offset)
+		if err != nil {
+			return nil, err
+		}
+		if date, err = applyDateOffset(date, text); err != nil {
+			return nil, err
+		}
+	}
+
+	location := time.UTC
+	if timezone, ok := hash["timezone"].(string); ok {
+		var err error
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("timezone: %s", err.Error())
+		}
+	}
+	date = date.In(location)
+
+	layout, _ := hash["format"].(string)
+	switch layout {
+	case "":
+		return date.Format(time.RFC3339), nil
+	case "epoch":
+		return date.UnixMilli(), nil
+	case "unix":
+		return date.Unix(), nil
+	}
+
+	goLayout, err := javaDateLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+
+	return date.Format(goLayout), nil
+}
+
+func applyDateOffset(date time.Time, offset string) (time.Time, error) {
+	fields := strings.Fields(offset)
+	if len(fields) != 2 {
+		return date, fmt.Errorf("offset %q: amount and unit expected", offset)
+	}
+
+	amount, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return date, fmt.Errorf("offset %q: invalid amount", offset)
+	}
+
+	switch strings.TrimSuffix(strings.ToLower(fields[1]), "s") {
+	case "millisecond":
+		return date.Add(time.Duration(amount) * time.Millisecond), nil
+	case "second":
+		return date.Add(time.Duration(amount) * time.Second), nil
+	case "minute":
+		return date.Add(time.Duration(amount) * time.Minute), nil
+	case "hour":
+		return date.Add(time.Duration(amount) * time.Hour), nil
+	case "day":
+		return date.AddDate(0, 0, amount), nil
+	case "week":
+		return date.AddDate(0, 0, 7*amount), nil
+	case "month":
+		return date.AddDate(0, amount, 0), nil
+	case "year":
+		return date.AddDate(amount, 0, 0), nil
+	}
+
+	return date, fmt.Errorf("offset %q: unknown unit", offset)
+}
+
+var javaDateTokens = []struct {
+	java string
+	goes string
+}{
+	{"yyyy", "2006"},
+	{"yy", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"dd", "02"},
+	{"d", "2"},
+	{"EEEE", "Monday"},
+	{"EEE", "Mon"},
+	{"HH", "15"},
+	{"hh", "03"},
+	{"mm", "04"},
+	{"ss", "05"},
+	{"SSS", "000"},
+	{"a", "PM"},
+	{"XXX", "Z07:00"},
+	{"Z", "-0700"},
+	{"z", "MST"},
+}
+
+// javaDateLayout converts the common Java date format letters to the Go layout.
+func javaDateLayout(format string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '\'' {
+			end := strings.IndexByte(format[i+1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("format %q: unclosed quote", format)
+			}
+			sb.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		matched := false
+		for _, token := range javaDateTokens {
+			if strings.HasPrefix(format[i:], token.java) {
+				sb.WriteString(token.goes)
+				i += len(token.java)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		c := format[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			return "", fmt.Errorf("format %q: letter %q: %w", format, c, ErrUnsupported)
+		}
+		sb.WriteByte(c)
+		i++
+	}
+
+	return sb.String(), nil
+}
+
+func eqHelper(_ renderer, args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	left, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	right, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return left == right, nil
+}
+
+func containsHelper(_ renderer, args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, errors.New("2 arguments expected")
+	}
+
+	needle, err := format(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch haystack := args[0].(type) {
+	case listValue:
+		for _, value := range haystack {
+			if value == needle {
+				return true, nil
+			}
+		}
+		return false, nil
+	case []interface{}:
+		for _, value := range haystack {
+			if text, _ := format(value); text == needle {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	haystack, err := format(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Contains(haystack, needle), nil
+}
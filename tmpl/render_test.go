@@ -0,0 +1,69 @@
+package tmpl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	r := renderer{now: func() time.Time { return time.Date(2024, 2, 28, 10, 30, 0, 0, time.UTC) }}
+	request := Request{
+		Method:  "POST",
+		URL:     "/orders/42?expand=items&expand=customer",
+		Headers: map[string]string{"X-Trace": "<abc>", "X-Since": "2024-01-01T12:00:00Z"},
+		Body:    `{"id": 7, "items": [{"unit price": 2.5}], "customer": {"name": "Ann"}}`,
+	}
+
+	for template, expected := range map[string]string{
+		RequestPath(1).Raw() + " " + Reference(Ref("request.method")).Raw():                            "42 POST",
+		RequestQuery("expand").Raw() + " " + Reference(Ref("request.query.expand.[1]")).Raw():          "items customer",
+		RequestHeader("x-trace").String() + RequestHeader("X-Trace").Raw():                             "&lt;abc&gt;<abc>",
+		JsonPath("request.body", "$.items[0]['unit price']").Raw():                                     "2.5",
+		JsonPath("request.body", "$.customer").Raw():                                                   `{"name":"Ann"}`,
+		JsonPath("request.body", "$.missing").Raw():                                                    "",
+		Now().Offset("1 days").Format("yyyy-MM-dd'T'HH:mm").Raw():                                      "2024-02-29T10:30",
+		Now().OffsetBy(-time.Hour).Raw() + " " + Now().Format("epoch").Raw():                           "2024-02-28T09:30:00Z 1709116200000",
+		Date(time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)).Offset("1 days").Format("yyyy-MM-dd").Raw(): "2030-02-01",
+		DateOf(ParseDate(RequestHeader("X-Since"))).Offset("-1 hours").Raw():                           "2024-01-01T11:00:00Z",
+		`{{#if (eq request.method 'POST')}}created{{else}}read{{/if}}`:                                 "created",
+		`{{#unless request.query.page}}first{{/unless}}`:                                               "first",
+		Escape("{{literal}}") + "{{! comment }}":                                                       "{{literal}}",
+	} {
+		rendered, err := r.render(template, request)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", template, err)
+			continue
+		}
+		if rendered != expected {
+			t.Errorf("%s: expected %q; got %q", template, expected, rendered)
+		}
+	}
+
+	rendered, err := RenderAt(Now().Format("yyyy-MM-dd").Raw(), request, time.Date(2031, 5, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil || rendered != "2031-05-06" {
+		t.Errorf("expected date of the fixed clock; got %q, error %v", rendered, err)
+	}
+
+	rendered, err = r.render(RandomString(RandomHexadecimal, 8, true).Raw()+" "+RandomInt(3, 3).Raw(), request)
+	if err != nil || len(rendered) != 10 || strings.ToUpper(rendered) != rendered || !strings.HasSuffix(rendered, " 3") {
+		t.Errorf("unexpected random values %q, error %v", rendered, err)
+	}
+
+	for template, expected := range map[string]string{
+		`{{jsonPath request.body '$.id'`:               "unclosed {{",
+		`{{#if request.body}}yes{{/unless}}`:           "{{/if}} expected",
+		JsonPath("request.headers.X-Trace", "$").Raw(): "invalid JSON",
+	} {
+		if _, err := r.render(template, request); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected error containing %q; got %v", template, expected, err)
+		}
+	}
+
+	for _, template := range []string{XPath("request.body", "/a").Raw(), `{{#each request.pathSegments}}x{{/each}}`} {
+		if _, err := r.render(template, request); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%s: expected ErrUnsupported; got %v", template, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package tmpl builds WireMock response templating (Handlebars) expressions.
+//
+// Expressions render with proper quoting and escaping, so they can be composed into templated bodies:
+//
+//	body := fmt.Sprintf(`{"id": "%s", "trace": "%s"}`,
+//		tmpl.JsonPath("request.body", "$.id").Raw(),
+//		tmpl.RandomValue("UUID").Raw(),
+//	)
+//
+// String renders the HTML escaping {{...}} form, Raw renders the {{{...}}} form, which is what JSON bodies usually need.
+package tmpl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A Value is the argument of the helper: a reference, a literal or a sub-expression.
+type Value interface {
+	argument() string
+}
+
+type reference string
+
+func (r reference) argument() string {
+	return string(r)
+}
+
+type literal string
+
+func (l literal) argument() string {
+	return string(l)
+}
+
+// identifier is the path segment usable without [] quoting.
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+// Ref returns reference to the template model by dotted path, e.g. "request.body".
+// The path is used as is, see Path for building it from arbitrary segments.
+func Ref(path string) Value {
+	return reference(path)
+}
+
+// Path returns reference from segments, quoting segments which are not plain identifiers,
+// e.g. Path("request", "headers", "X Trace") is request.headers.[X Trace].
+func Path(segments ...string) Value {
+	quoted := make([]string, len(segments))
+	for i, segment := range segments {
+		if identifier.MatchString(segment) {
+			quoted[i] = segment
+			continue
+		}
+		quoted[i] = "[" + strings.ReplaceAll(segment, "]", "") + "]"
+	}
+
+	return reference(strings.Join(quoted, "."))
+}
+
+// String returns string literal argument.
+func String(value string) Value {
+	return literal("'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'")
+}
+
+// Int returns number literal argument.
+func Int(value int64) Value {
+	return literal(strconv.FormatInt(value, 10))
+}
+
+// Float returns decimal number literal argument.
+func Float(value float64) Value {
+	return literal(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Bool returns boolean literal argument.
+func Bool(value bool) Value {
+	return literal(strconv.FormatBool(value))
+}
+
+type hashArgument struct {
+	key   string
+	value Value
+}
+
+// An Expr is the helper call or the reference rendered into the template.
+type Expr struct {
+	helper string
+	args   []Value
+	hash   []hashArgument
+}
+
+// Helper returns the call of the helper with positional args.
+func Helper(name string, args ...Value) Expr {
+	return Expr{helper: name, args: args}
+}
+
+// Reference returns expression rendering the value at path of the template model.
+func Reference(path Value) Expr {
+	return Expr{args: []Value{path}}
+}
+
+// With adds hash argument key=value and returns Expr
+func (e Expr) With(key string, value Value) Expr {
+	hash := make([]hashArgument, len(e.hash), len(e.hash)+1)
+	copy(hash, e.hash)
+	e.hash = append(hash, hashArgument{key: key, value: value})
+	return e
+}
+
+func (e Expr) body() string {
+	parts := make([]string, 0, 1+len(e.args)+len(e.hash))
+	if e.helper != "" {
+		parts = append(parts, e.helper)
+	}
+	for _, arg := range e.args {
+		parts = append(parts, arg.argument())
+	}
+	for _, hash := range e.hash {
+		parts = append(parts, hash.key+"="+hash.value.argument())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// argument renders expression as the sub-expression argument of another helper.
+func (e Expr) argument() string {
+	if e.helper == "" {
+		return e.body()
+	}
+
+	return "(" + e.body() + ")"
+}
+
+// String renders the HTML escaping {{...}} form.
+func (e Expr) String() string {
+	return "{{" + e.body() + "}}"
+}
+
+// Raw renders the not escaping {{{...}}} form.
+func (e Expr) Raw() string {
+	return "{{{" + e.body() + "}}}"
+}
+
+// Escape makes text render literally, escaping Handlebars mustaches.
+func Escape(text string) string {
+	return strings.ReplaceAll(text, "{{", `\{{`)
+}
+
+// JsonPath returns the jsonPath helper extracting expression from the JSON at source, e.g. "request.body".
+func JsonPath(source, expression string) Expr {
+	return Helper("jsonPath", Ref(source), String(expression))
+}
+
+// XPath returns the xPath helper extracting expression from the XML at source.
+func XPath(source, expression string) Expr {
+	return Helper("xPath", Ref(source), String(expression))
+}
+
+// RandomValue returns the randomValue helper generating value of kind,
+// e.g. UUID, ALPHANUMERIC, NUMERIC, ALPHABETIC, HEXADECIMAL.
+func RandomValue(kind string) Expr {
+	return Helper("randomValue").With("type", String(kind))
+}
+
+// RequestPath returns the reference to the request path, or its segment when index is given.
+func RequestPath(index ...int) Expr {
+	if len(index) > 0 {
+		return Reference(Path("request", "pathSegments", strconv.Itoa(index[0])))
+	}
+
+	return Reference(Ref("request.path"))
+}
+
+// RequestHeader returns the reference to the request header.
+func RequestHeader(name string) Expr {
+	return Reference(Path("request", "headers", name))
+}
+
+// RequestQuery returns the reference to the request query parameter.
+func RequestQuery(name string) Expr {
+	return Reference(Path("request", "query", name))
+}
+
+// RequestBody returns the reference to the request body.
+func RequestBody() Expr {
+	return Reference(Ref("request.body"))
+}
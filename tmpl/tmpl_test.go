@@ -0,0 +1,48 @@
+package tmpl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpr(t *testing.T) {
+	for expected, expr := range map[string]Expr{
+		`{{jsonPath request.body '$.id'}}`:                    JsonPath("request.body", "$.id"),
+		`{{{randomValue type='UUID'}}}`:                       RandomValue("UUID"),
+		`{{request.headers.[X Trace]}}`:                       RequestHeader("X Trace"),
+		`{{request.pathSegments.[1]}}`:                        RequestPath(1),
+		`{{jsonPath request.body '$[\'it\\\'s\']'}}`:          JsonPath("request.body", `$['it\'s']`),
+		`{{randomValue length=(size request.body) type='X'}}`: Helper("randomValue").With("length", Helper("size", Ref("request.body"))).With("type", String("X")),
+	} {
+		rendered := expr.String()
+		if expected[:3] == "{{{" {
+			rendered = expr.Raw()
+		}
+		if rendered != expected {
+			t.Errorf("expected %s; got %s", expected, rendered)
+		}
+	}
+
+	if Escape(`{{not a template}}`) != `\{{not a template}}` {
+		t.Errorf("expected escaped mustaches; got %s", Escape(`{{not a template}}`))
+	}
+}
+
+func TestRandomAndDates(t *testing.T) {
+	for expected, rendered := range map[string]string{
+		`{{randomValue type='UUID'}}`:                                 UUID().String(),
+		`{{randomValue type='NUMERIC' length=6 uppercase=true}}`:      RandomString(RandomNumeric, 6, true).String(),
+		`{{randomInt lower=1 upper=10}}`:                              RandomInt(1, 10).String(),
+		`{{randomDecimal lower=0.5 upper=2}}`:                         RandomDecimal(0.5, 2).String(),
+		`{{pickRandom 'new' 'paid'}}`:                                 PickRandom("new", "paid").String(),
+		`{{now offset='1 days' format='yyyy-MM-dd'}}`:                 Now().Offset("1 days").Format("yyyy-MM-dd").String(),
+		`{{now offset='-90 seconds' timezone='UTC'}}`:                 Now().OffsetBy(-90 * time.Second).Timezone("UTC").String(),
+		`{{jsonPath (now format='epoch') '$'}}`:                       Helper("jsonPath", Now().Format("epoch"), String("$")).String(),
+		`{{date (parseDate '2024-02-28T10:30:00Z') offset='1 days'}}`: Date(time.Date(2024, 2, 28, 11, 30, 0, 0, time.FixedZone("CET", 3600))).Offset("1 days").String(),
+		`{{date (parseDate request.headers.X-Since) format='unix'}}`:  DateOf(ParseDate(Ref("request.headers.X-Since"))).Format("unix").String(),
+	} {
+		if rendered != expected {
+			t.Errorf("expected %s; got %s", expected, rendered)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package wiremock
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetadataExpiresAt is the metadata key of the stub expiry time set by WithTTL.
+const MetadataExpiresAt = "expiresAt"
+
+// WithTTL records in metadata that the stub expires after ttl from now and returns *StubRule.
+// WireMock keeps serving expired stubs, they are removed by Client.CleanupExpired.
+func (s *StubRule) WithTTL(ttl time.Duration) *StubRule {
+	return s.WithMetadata(MetadataExpiresAt, time.Now().Add(ttl).UTC().Format(time.RFC3339Nano))
+}
+
+// ExpiresAt gives the stub expiry time set by WithTTL, the second result is false when it is not set.
+func (s *StubRule) ExpiresAt() (time.Time, bool) {
+	value, ok := s.metadata[MetadataExpiresAt].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return expiresAt, true
+}
+
+// CleanupExpired deletes stub mappings expired by WithTTL and returns their ids.
+// Run it periodically, e.g. at the start of every test run, on shared long-running servers.
+func (c *Client) CleanupExpired() ([]string, error) {
+	stubs, _, err := c.ListStubs(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cleanup expired: %w", err)
+	}
+
+	now := time.Now()
+	var expired []string
+	for _, stubRule := range stubs {
+		if expiresAt, ok := stubRule.ExpiresAt(); ok && !expiresAt.After(now) {
+			expired = append(expired, stubRule.UUID())
+		}
+	}
+
+	if err := c.DeleteStubs(expired...); err != nil {
+		return nil, fmt.Errorf("cleanup expired: %w", err)
+	}
+
+	return expired, nil
+}
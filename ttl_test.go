@@ -0,0 +1,43 @@
+package wiremock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClient_CleanupExpired(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	expired := Get(URLPathEqualTo("/expired")).WithTTL(-time.Minute)
+	alive := Get(URLPathEqualTo("/alive")).WithTTL(time.Hour)
+	permanent := Get(URLPathEqualTo("/permanent"))
+	for _, stubRule := range []*StubRule{expired, alive, permanent} {
+		if err := client.StubFor(stubRule); err != nil {
+			t.Fatalf("StubFor error: %v", err)
+		}
+	}
+
+	if expiresAt, ok := alive.ExpiresAt(); !ok || expiresAt.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("unexpected expiry %v, %v", expiresAt, ok)
+	}
+	if _, ok := permanent.ExpiresAt(); ok {
+		t.Error("expected no expiry for stub without TTL")
+	}
+
+	deleted, err := client.CleanupExpired()
+	if err != nil {
+		t.Fatalf("CleanupExpired error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != expired.UUID() || server.mappingCount() != 2 {
+		t.Errorf("expected only expired stub to be deleted; got %v, %d left", deleted, server.mappingCount())
+	}
+}
+
+func TestStubRule_WithTTLKeepsDeterministicID(t *testing.T) {
+	first := Get(URLPathEqualTo("/orders")).WithTTL(time.Minute).WithDeterministicID()
+	second := Get(URLPathEqualTo("/orders")).WithTTL(time.Hour).WithDeterministicID()
+	if first.UUID() != second.UUID() {
+		t.Errorf("expected equal ids of stubs differing in expiry; got %s and %s", first.UUID(), second.UUID())
+	}
+}
@@ -0,0 +1,158 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// MethodAny matches requests with any http method.
+const MethodAny = "ANY"
+
+var knownMethods = map[string]bool{
+	MethodAny:          true,
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// Validate checks StubRule locally and returns descriptive error for every problem found.
+func (s *StubRule) Validate() error {
+	if s.request == nil {
+		return errors.New("request: is required")
+	}
+	if s.idErr != nil {
+		return fmt.Errorf("id: %s", s.idErr.Error())
+	}
+
+	return s.request.Validate()
+}
+
+// Validate checks Request locally: the http method must be known, regular expressions must compile
+// and JSON values must be valid JSON. WireMock uses Java regular expressions, so constructs
+// that Go cannot parse (lookarounds, backreferences) are not reported.
+func (r *Request) Validate() error {
+	var errs []error
+
+	if !knownMethods[r.method] {
+		errs = append(errs, fmt.Errorf("method: unknown http method %q", r.method))
+	}
+
+	if r.rawQuery != nil {
+		if _, err := rawQueryURLMatcher(r.urlMatcher, *r.rawQuery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if r.urlMatcher != nil {
+		switch r.urlMatcher.Strategy() {
+		case URLPathMatchingRule, URLMatchingRule:
+			if err := validateRegexp(r.urlMatcher.Value()); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s", r.urlMatcher.Strategy(), err.Error()))
+			}
+		}
+	}
+
+	errs = append(errs, validateParamMatchers("headers", r.headers)...)
+	errs = append(errs, validateParamMatchers("queryParameters", r.queryParams)...)
+	errs = append(errs, validateParamMatchers("cookies", r.cookies)...)
+	for i, bodyPattern := range r.bodyPatterns {
+		if err := validateParamMatcher(bodyPattern); err != nil {
+			errs = append(errs, fmt.Errorf("bodyPatterns[%d]: %s", i, err.Error()))
+		}
+	}
+
+	for i, multipartPattern := range r.multipartPatterns {
+		if err := multipartPattern.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("multipartPatterns[%d]: %s", i, err.Error()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks MultipartPattern matchers locally.
+func (m *MultipartPattern) Validate() error {
+	errs := validateParamMatchers("headers", m.headers)
+	for i, bodyPattern := range m.bodyPatterns {
+		if err := validateParamMatcher(bodyPattern); err != nil {
+			errs = append(errs, fmt.Errorf("bodyPatterns[%d]: %s", i, err.Error()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateParamMatchers(field string, matchers map[string]ParamMatcherInterface) []error {
+	keys := make([]string, 0, len(matchers))
+	for key := range matchers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := validateParamMatcher(matchers[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s[%s]: %s", field, key, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateParamMatcher(matcher ParamMatcherInterface) error {
+	switch matcher.Strategy() {
+	case ParamMatches, ParamDoesNotMatch:
+		if err := validateRegexp(matcher.Value()); err != nil {
+			return fmt.Errorf("%s: %s", matcher.Strategy(), err.Error())
+		}
+	case ParamEqualToJson:
+		if !json.Valid([]byte(matcher.Value())) {
+			return fmt.Errorf("%s: invalid json %q", matcher.Strategy(), matcher.Value())
+		}
+	}
+
+	if paramMatcher, ok := matcher.(ParamMatcher); ok && paramMatcher.valueMatcher != nil {
+		if err := validateParamMatcher(*paramMatcher.valueMatcher); err != nil {
+			return fmt.Errorf("%s: %s", matcher.Strategy(), err.Error())
+		}
+	}
+	if paramMatcher, ok := matcher.(ParamMatcher); ok {
+		for i, valueMatcher := range paramMatcher.valueMatchers {
+			if err := validateParamMatcher(valueMatcher); err != nil {
+				return fmt.Errorf("%s[%d]: %s", matcher.Strategy(), i, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRegexp(pattern string) error {
+	_, err := regexp.Compile(pattern)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *syntax.Error
+	if errors.As(err, &syntaxErr) {
+		switch syntaxErr.Code {
+		case syntax.ErrInvalidPerlOp, syntax.ErrInvalidEscape, syntax.ErrInvalidRepeatOp:
+			// valid in Java, but not supported by Go regexp
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid regexp %q: %s", pattern, strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+}
@@ -0,0 +1,36 @@
+package wiremock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStubRule_Validate(t *testing.T) {
+	valid := Post(URLPathMatching("/orders/[0-9]+")).
+		WithHeader("X-Id", Matching("^(?!admin).*$")).
+		WithBodyPattern(EqualToJson(`{"id": 1}`))
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid stub; got %v", err)
+	}
+
+	invalid := NewStubRule("GTE", URLMatching("/orders/[0-9+")).
+		WithQueryParam("q", NotMatching("(a")).
+		WithBodyPattern(EqualToJson(`{"id": }`)).
+		WithMultipartPattern(NewMultipartPattern().WithHeader("Content-Type", Matching("[")))
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	for _, expected := range []string{
+		`method: unknown http method "GTE"`,
+		`urlPattern: invalid regexp "/orders/[0-9+"`,
+		`queryParameters[q]: doesNotMatch: invalid regexp "(a"`,
+		`bodyPatterns[0]: equalToJson: invalid json`,
+		`multipartPatterns[0]: headers[Content-Type]: matches: invalid regexp "["`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q; got %q", expected, err.Error())
+		}
+	}
+}
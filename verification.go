@@ -0,0 +1,480 @@
+package wiremock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RequestCriteria describes requests to verify. Both *Request and *StubRule are criteria,
+// so the stub matcher can be reused for verification.
+type RequestCriteria interface {
+	Criteria() *Request
+}
+
+// Criteria returns Request itself as verification criteria.
+func (r *Request) Criteria() *Request {
+	return r
+}
+
+// Criteria returns Request of StubRule as verification criteria.
+func (s *StubRule) Criteria() *Request {
+	return s.request
+}
+
+// A VerificationError describes requests count not matching expectation.
+type VerificationError struct {
+	Criteria    *Request
+	Expectation string
+	Actual      int64
+	// NearMisses are the logged requests closest to matching criteria, rendered with hints how to fix criteria.
+	NearMisses []NearMiss
+}
+
+// Error implements error.
+func (e *VerificationError) Error() string {
+	message := fmt.Sprintf("expected %s matching\n%s\nbut received %d", e.Expectation, e.Criteria, e.Actual)
+	if len(e.NearMisses) > 0 {
+		message += "\n\nclosest received requests:\n\n" + FormatNearMisses(e.NearMisses)
+	}
+
+	return message
+}
+
+// A VerificationResult is the report of a verification, for context-rich failure logs and aggregation.
+// Verifications fill it when asked to, see Verification.WithReport, and keep returning only the error,
+// so they compose with the assertion helpers and the gomega matchers.
+// Verification.Report, VerifyEventuallyReport and VerifyManyReport return it alongside the error.
+type VerificationResult struct {
+	Criteria    *Request
+	Expectation string
+	Actual      int64
+	Passed      bool
+	// Matched are the logged requests matching criteria.
+	Matched []LoggedRequest
+	// NearMisses are the logged requests closest to matching criteria, retrieved when the verification fails.
+	NearMisses []NearMiss
+}
+
+// String renders the result for test logs.
+func (r *VerificationResult) String() string {
+	status := "passed"
+	if !r.Passed {
+		status = "failed"
+	}
+
+	result := fmt.Sprintf("verification %s: expected %s matching\n%s\nreceived %d", status, r.Expectation, r.Criteria, r.Actual)
+	if len(r.NearMisses) > 0 {
+		result += "\n\nclosest received requests:\n\n" + FormatNearMisses(r.NearMisses)
+	}
+
+	return result
+}
+
+// A Verification checks count of requests matching criteria.
+type Verification struct {
+	client   *Client
+	criteria *Request
+	report   *VerificationResult
+}
+
+// VerifyThat starts verification of requests matching criteria.
+//
+//	err := client.VerifyThat(wiremock.Post(wiremock.URLPathEqualTo("/orders"))).Times(2)
+func (c *Client) VerifyThat(criteria RequestCriteria) *Verification {
+	return &Verification{
+		client:   c,
+		criteria: criteria.Criteria(),
+	}
+}
+
+// VerifyJSONBody starts verification of requests matching criteria whose JSON body has the value
+// selected by the JSONPath expression matching matcher, e.g.
+//
+//	err := client.VerifyJSONBody(wiremock.Post(wiremock.URLPathEqualTo("/orders")), "$.order.id", wiremock.EqualTo("42")).Once()
+//
+// The body is checked by WireMock, see MatchingJsonPathWith, criteria is not modified.
+func (c *Client) VerifyJSONBody(criteria RequestCriteria, expression string, matcher ParamMatcher) *Verification {
+	return c.VerifyThat(criteria.Criteria().Clone().WithBodyPattern(MatchingJsonPathWith(expression, matcher)))
+}
+
+// WithReport makes the verification fill result, whether it passes or not, and returns *Verification.
+// Reporting costs extra requests for matched requests and near misses, so it is opt-in;
+// the failures are reported by VerificationError all the same.
+//
+//	var result wiremock.VerificationResult
+//	err := client.VerifyThat(criteria).WithReport(&result).Times(2)
+func (v *Verification) WithReport(result *VerificationResult) *Verification {
+	v.report = result
+	return v
+}
+
+// Report runs check, e.g. (*Verification).Once, with the report filled and returns it alongside the error of check.
+//
+//	result, err := client.VerifyThat(criteria).Report(func(v *wiremock.Verification) error { return v.Times(2) })
+func (v *Verification) Report(check func(*Verification) error) (VerificationResult, error) {
+	var result VerificationResult
+	err := check(v.WithReport(&result))
+	return result, err
+}
+
+// Criteria returns the verified criteria.
+func (v *Verification) Criteria() *Request {
+	return v.criteria
+}
+
+// Count gives count of requests matching criteria.
+func (v *Verification) Count() (int64, error) {
+	return v.client.GetCountRequests(v.criteria)
+}
+
+// Client returns client the verification is made with.
+func (v *Verification) Client() *Client {
+	return v.client
+}
+
+// Times checks that exactly count requests were received.
+func (v *Verification) Times(count int64) error {
+	return v.check(fmt.Sprintf("exactly %d %s", count, requestsNoun(count)), func(actual int64) bool {
+		return actual == count
+	})
+}
+
+// Once checks that exactly one request was received.
+func (v *Verification) Once() error {
+	return v.Times(1)
+}
+
+// Never checks that no request was received.
+func (v *Verification) Never() error {
+	return v.Times(0)
+}
+
+// AtLeast checks that count or more requests were received.
+func (v *Verification) AtLeast(count int64) error {
+	return v.check(fmt.Sprintf("at least %d %s", count, requestsNoun(count)), func(actual int64) bool {
+		return actual >= count
+	})
+}
+
+// AtMost checks that count or less requests were received.
+func (v *Verification) AtMost(count int64) error {
+	return v.check(fmt.Sprintf("at most %d %s", count, requestsNoun(count)), func(actual int64) bool {
+		return actual <= count
+	})
+}
+
+// VerifyEventually polls count of requests matching criteria every interval
+// until it equals count or ctx is done, for asserting asynchronous calls without sleeps.
+// When no count was received before ctx is done, the error wraps ctx.Err() and the last request error.
+func (c *Client) VerifyEventually(ctx context.Context, criteria RequestCriteria, count int64, interval time.Duration) error {
+	return c.verifyEventually(ctx, criteria.Criteria(), count, interval, nil)
+}
+
+// VerifyEventuallyReport is VerifyEventually returning the report of the last received count alongside the error.
+// The result is empty when no count was received before ctx is done.
+func (c *Client) VerifyEventuallyReport(ctx context.Context, criteria RequestCriteria, count int64, interval time.Duration) (VerificationResult, error) {
+	var result VerificationResult
+	err := c.verifyEventually(ctx, criteria.Criteria(), count, interval, &result)
+	return result, err
+}
+
+func (c *Client) verifyEventually(ctx context.Context, request *Request, count int64, interval time.Duration, report *VerificationResult) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	actual := int64(-1)
+	for {
+		current, err := c.getCountRequests(ctx, request)
+		if err == nil && current == count {
+			if report != nil {
+				return c.fillReport(report, request, fmt.Sprintf("exactly %d %s", count, requestsNoun(count)), current, true)
+			}
+			return nil
+		}
+		if err == nil {
+			actual = current
+		} else if ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if actual < 0 && lastErr != nil {
+				return fmt.Errorf("verify eventually: no count of requests received before %w: %w", ctx.Err(), lastErr)
+			}
+			if actual < 0 {
+				return fmt.Errorf("verify eventually: no count of requests received before %w", ctx.Err())
+			}
+
+			verificationErr := &VerificationError{
+				Criteria:    request,
+				Expectation: fmt.Sprintf("exactly %d %s before %s", count, requestsNoun(count), ctx.Err()),
+				Actual:      actual,
+			}
+			if report != nil {
+				if err := c.fillReport(report, request, verificationErr.Expectation, actual, false); err != nil {
+					return err
+				}
+				verificationErr.NearMisses = report.NearMisses
+			}
+
+			return verificationErr
+		case <-ticker.C:
+		}
+	}
+}
+
+// VerifyMany checks that exactly the expected count of requests matching every criteria was received,
+// fetching the request journal once and matching the criteria locally, e.g. at the end of a big test:
+//
+//	err := client.VerifyMany(map[*wiremock.Request]int64{
+//		wiremock.NewRequest(http.MethodPost, wiremock.URLPathEqualTo("/orders")): 2,
+//		wiremock.NewRequest(http.MethodGet, wiremock.URLPathEqualTo("/users")):   0,
+//	})
+//
+// Criteria which cannot be matched locally, e.g. with matchesJsonPath, are counted by the server.
+// Every failed check is the joined *VerificationError.
+func (c *Client) VerifyMany(expected map[*Request]int64) error {
+	return c.verifyMany(expected, nil)
+}
+
+// VerifyManyReport is VerifyMany returning the report of every criteria, sorted as the failures,
+// alongside the joined error. The failed reports have the near misses found by the server.
+func (c *Client) VerifyManyReport(expected map[*Request]int64) ([]VerificationResult, error) {
+	results := make([]VerificationResult, 0, len(expected))
+	if err := c.verifyMany(expected, &results); err != nil {
+		var verificationErr *VerificationError
+		if !errors.As(err, &verificationErr) {
+			return nil, err
+		}
+
+		return results, err
+	}
+
+	return results, nil
+}
+
+func (c *Client) verifyMany(expected map[*Request]int64, reports *[]VerificationResult) error {
+	events, err := c.GetServeEvents(nil)
+	if err != nil {
+		return fmt.Errorf("verify many: %s", err.Error())
+	}
+
+	criteria := make([]*Request, 0, len(expected))
+	for request := range expected {
+		criteria = append(criteria, request)
+	}
+	// failures are reported in the same order every run
+	sort.Slice(criteria, func(i, j int) bool {
+		return criteria[i].String() < criteria[j].String()
+	})
+
+	var errs []error
+	for _, request := range criteria {
+		matched, known := matchingRequests(request, events)
+		actual := int64(len(matched))
+		if !known {
+			if actual, err = c.GetCountRequests(request); err != nil {
+				return fmt.Errorf("verify many: %s", err.Error())
+			}
+		}
+
+		count := expected[request]
+		expectation := fmt.Sprintf("exactly %d %s", count, requestsNoun(count))
+		var nearMisses []NearMiss
+		if reports != nil {
+			result := VerificationResult{
+				Criteria:    request,
+				Expectation: expectation,
+				Actual:      actual,
+				Passed:      actual == count,
+				Matched:     matched,
+			}
+			var reportErr error
+			if !known {
+				reportErr = c.fillReport(&result, request, expectation, actual, result.Passed)
+			} else if !result.Passed {
+				result.NearMisses, reportErr = c.FindNearMisses(request)
+			}
+			if reportErr != nil {
+				return fmt.Errorf("verify many: %s", reportErr.Error())
+			}
+			*reports = append(*reports, result)
+			nearMisses = result.NearMisses
+		}
+
+		if actual != count {
+			errs = append(errs, &VerificationError{
+				Criteria:    request,
+				Expectation: expectation,
+				Actual:      actual,
+				NearMisses:  nearMisses,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchingRequests gives the logged requests of events matching criteria, false when some of them cannot be matched locally.
+func matchingRequests(criteria *Request, events []ServeEvent) ([]LoggedRequest, bool) {
+	var requests []LoggedRequest
+	for i := range events {
+		matched, known := criteria.Match(&events[i].Request)
+		if !known {
+			return nil, false
+		}
+		if matched {
+			requests = append(requests, events[i].Request)
+		}
+	}
+
+	return requests, true
+}
+
+// awaitRequestInterval is the interval of polling the request journal by AwaitRequest.
+const awaitRequestInterval = 50 * time.Millisecond
+
+// AwaitRequest polls the request journal until a request matching criteria is received or ctx is done
+// and gives the first received one, for synchronizing tests with asynchronous calls:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	request, err := client.AwaitRequest(ctx, wiremock.Post(wiremock.URLPathEqualTo("/webhooks")))
+//
+// When ctx is done first the error wraps ctx.Err().
+func (c *Client) AwaitRequest(ctx context.Context, criteria RequestCriteria) (*LoggedRequest, error) {
+	request := criteria.Criteria()
+	ticker := time.NewTicker(awaitRequestInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		requests, err := c.findRequests(ctx, request)
+		if err == nil && len(requests) > 0 {
+			return &requests[0], nil
+		}
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("await request: %s: %w", lastErr.Error(), ctx.Err())
+			}
+
+			return nil, fmt.Errorf("await request: no request matching\n%s\nreceived before %w", request, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// VerifyOrder checks that requests matching every criteria were received in the given order:
+// a request matching criteria[i+1] was received after the one matching criteria[i].
+func (c *Client) VerifyOrder(criteria ...RequestCriteria) error {
+	var previous *LoggedRequest
+	used := map[string]bool{}
+	for i, criterion := range criteria {
+		requests, err := c.FindRequests(criterion)
+		if err != nil {
+			return fmt.Errorf("verify order: %s", err.Error())
+		}
+
+		var next *LoggedRequest
+		for j := range requests {
+			request := &requests[j]
+			if request.ID != "" && used[request.ID] {
+				continue
+			}
+			if previous != nil && request.LoggedDate.Before(previous.LoggedDate) {
+				continue
+			}
+			if next == nil || request.LoggedDate.Before(next.LoggedDate) {
+				next = request
+			}
+		}
+
+		if next == nil {
+			if previous == nil {
+				return fmt.Errorf("verify order: expected request #%d matching\n%s\nbut received none", i+1, criterion.Criteria())
+			}
+
+			return fmt.Errorf("verify order: expected request #%d matching\n%s\nafter %s %s at %s but received none",
+				i+1, criterion.Criteria(), previous.Method, previous.URL, previous.LoggedDate.Format(time.RFC3339Nano))
+		}
+
+		previous = next
+		used[next.ID] = true
+	}
+
+	return nil
+}
+
+func (v *Verification) check(expectation string, matches func(actual int64) bool) error {
+	actual, err := v.client.GetCountRequests(v.criteria)
+	if err != nil {
+		return err
+	}
+
+	if v.report != nil {
+		if err := v.client.fillReport(v.report, v.criteria, expectation, actual, matches(actual)); err != nil {
+			return err
+		}
+	}
+
+	if !matches(actual) {
+		verificationErr := &VerificationError{
+			Criteria:    v.criteria,
+			Expectation: expectation,
+			Actual:      actual,
+		}
+		if v.report != nil {
+			verificationErr.NearMisses = v.report.NearMisses
+		} else if nearMisses, err := v.client.FindNearMisses(v.criteria); err == nil {
+			// near misses only explain the failure, so failing to find them does not hide it
+			verificationErr.NearMisses = nearMisses
+		}
+
+		return verificationErr
+	}
+
+	return nil
+}
+
+func (c *Client) fillReport(report *VerificationResult, criteria *Request, expectation string, actual int64, passed bool) error {
+	*report = VerificationResult{
+		Criteria:    criteria,
+		Expectation: expectation,
+		Actual:      actual,
+		Passed:      passed,
+	}
+
+	matched, err := c.FindRequests(criteria)
+	if err != nil {
+		return fmt.Errorf("verification report: %s", err.Error())
+	}
+	report.Matched = matched
+
+	if !passed {
+		nearMisses, err := c.FindNearMisses(criteria)
+		if err != nil {
+			return fmt.Errorf("verification report: %s", err.Error())
+		}
+		report.NearMisses = nearMisses
+	}
+
+	return nil
+}
+
+func requestsNoun(count int64) string {
+	if count == 1 {
+		return "request"
+	}
+
+	return "requests"
+}
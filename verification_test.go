@@ -0,0 +1,358 @@
+package wiremock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestClient_VerifyThat(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Post(URLPathEqualTo("/orders")).WithHeader("X-Id", EqualTo("42"))
+	server.setCount(t, orders, 2)
+
+	if err := client.VerifyThat(orders).Times(2); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+	if err := client.VerifyThat(orders.Request()).AtLeast(1); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+	if err := client.VerifyThat(orders).AtMost(2); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+
+	err := client.VerifyThat(orders).Once()
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) {
+		t.Fatalf("expected VerificationError; got %v", err)
+	}
+	if verificationErr.Actual != 2 || !strings.Contains(err.Error(), "expected exactly 1 request matching") ||
+		!strings.Contains(err.Error(), `"urlPath": "/orders"`) {
+		t.Errorf("unexpected verification error %q", err.Error())
+	}
+
+	if err := client.VerifyThat(Get(URLPathEqualTo("/users"))).Never(); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+}
+
+func TestVerification_WithReport(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Post(URLPathEqualTo("/orders"))
+	server.setCount(t, orders, 1)
+	server.setFound(t, orders, map[string]interface{}{"id": "r1", "url": "/orders", "method": "POST"})
+	server.nearMisses = append(server.nearMisses, json.RawMessage(`{"request":{"url":"/order","method":"POST"},"matchResult":{"distance":0.1}}`))
+
+	var result VerificationResult
+	if err := client.VerifyThat(orders).WithReport(&result).Once(); err != nil {
+		t.Fatalf("expected verification to pass; got %v", err)
+	}
+	if !result.Passed || result.Actual != 1 || len(result.Matched) != 1 || result.Matched[0].ID != "r1" || result.NearMisses != nil {
+		t.Errorf("unexpected passed result %+v", result)
+	}
+
+	err := client.VerifyThat(orders).WithReport(&result).Times(2)
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) {
+		t.Fatalf("expected VerificationError; got %v", err)
+	}
+	if result.Passed || result.Expectation != "exactly 2 requests" || len(result.NearMisses) != 1 {
+		t.Errorf("unexpected failed result %+v", result)
+	}
+	if !strings.Contains(result.String(), "verification failed: expected exactly 2 requests") ||
+		!strings.Contains(result.String(), "POST /order") {
+		t.Errorf("unexpected result rendering %q", result.String())
+	}
+
+	result, err = client.VerifyThat(orders).Report((*Verification).Once)
+	if err != nil || !result.Passed || len(result.Matched) != 1 {
+		t.Errorf("expected passed report; got %+v, %v", result, err)
+	}
+	result, err = client.VerifyThat(orders).Report(func(v *Verification) error { return v.AtLeast(3) })
+	if !errors.As(err, &verificationErr) || result.Passed || result.Expectation != "at least 3 requests" {
+		t.Errorf("expected failed report; got %+v, %v", result, err)
+	}
+}
+
+func TestClient_VerifyEventuallyReport(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	callback := Post(URLPathEqualTo("/callback"))
+	server.setCount(t, callback, 1)
+	server.setFound(t, callback, map[string]interface{}{"id": "r1", "url": "/callback", "method": "POST"})
+	server.nearMisses = append(server.nearMisses, json.RawMessage(`{"request":{"url":"/callbacks","method":"POST"},"matchResult":{"distance":0.1}}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := client.VerifyEventuallyReport(ctx, callback, 1, 5*time.Millisecond)
+	if err != nil || !result.Passed || len(result.Matched) != 1 || result.Matched[0].ID != "r1" {
+		t.Errorf("expected passed report; got %+v, %v", result, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	result, err = client.VerifyEventuallyReport(ctx, callback, 2, 5*time.Millisecond)
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) || len(verificationErr.NearMisses) != 1 {
+		t.Errorf("expected VerificationError with near misses; got %v", err)
+	}
+	if result.Passed || result.Actual != 1 || len(result.NearMisses) != 1 {
+		t.Errorf("unexpected failed report %+v", result)
+	}
+}
+
+func TestClient_VerifyEventually(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	callback := Post(URLPathEqualTo("/callback"))
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		server.setCount(t, callback, 1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.VerifyEventually(ctx, callback, 1, 5*time.Millisecond); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err := client.VerifyEventually(ctx, callback, 2, 5*time.Millisecond)
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) || verificationErr.Actual != 1 {
+		t.Errorf("expected VerificationError with actual 1; got %v", err)
+	}
+
+	stopped := newFakeServer(t)
+	stopped.Close()
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err = NewClient(stopped.URL).VerifyEventually(ctx, callback, 1, 5*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "get count requests") || errors.As(err, &verificationErr) {
+		t.Errorf("expected deadline error with the request error; got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = client.VerifyEventually(ctx, callback, 1, 5*time.Millisecond)
+	if !errors.Is(err, context.Canceled) || errors.As(err, &verificationErr) {
+		t.Errorf("expected canceled error without the count; got %v", err)
+	}
+}
+
+func TestClient_VerifyOrder(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	create := Post(URLPathEqualTo("/orders"))
+	pay := Post(URLPathEqualTo("/payments"))
+	ship := Post(URLPathEqualTo("/shipments"))
+	server.setFound(t, create, map[string]interface{}{"id": "1", "method": "POST", "url": "/orders", "loggedDate": 1000})
+	server.setFound(t, pay,
+		map[string]interface{}{"id": "0", "method": "POST", "url": "/payments", "loggedDate": 500},
+		map[string]interface{}{"id": "2", "method": "POST", "url": "/payments", "loggedDate": 2000},
+	)
+
+	if err := client.VerifyOrder(create, pay); err != nil {
+		t.Errorf("expected order verification to pass; got %v", err)
+	}
+	if err := client.VerifyOrder(pay, create); err != nil {
+		t.Errorf("expected order verification to pass for the early payment; got %v", err)
+	}
+	if err := client.VerifyOrder(create, pay, create); err == nil {
+		t.Errorf("expected order verification to fail for the second order")
+	}
+	if err := client.VerifyOrder(create, ship); err == nil || !strings.Contains(err.Error(), "after POST /orders") {
+		t.Errorf("expected order verification to fail for shipment; got %v", err)
+	}
+}
+
+func TestRequestFromHTTP(t *testing.T) {
+	captured := httptest.NewRequest("POST", "http://example.com/orders?page=2", strings.NewReader(`{"id":1}`))
+	captured.Header.Set("Content-Type", "application/json")
+	captured.Header["Accept-Encoding"] = []string{"gzip", "br"}
+
+	request, err := RequestFromHTTP(captured)
+	if err != nil {
+		t.Fatalf("RequestFromHTTP error: %v", err)
+	}
+
+	raw, err := request.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Request MarshalJSON error: %v", err)
+	}
+	for _, expected := range []string{
+		`"method":"POST"`,
+		`"url":"/orders?page=2"`,
+		`"headers":{"Accept-Encoding":{"hasExactly":[{"equalTo":"gzip"},{"equalTo":"br"}]},"Content-Type":{"equalTo":"application/json"}}`,
+		`"bodyPatterns":[{"equalTo":"{\"id\":1}"}]`,
+	} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected request to contain %s; got %s", expected, raw)
+		}
+	}
+
+	if body, _ := io.ReadAll(captured.Body); string(body) != `{"id":1}` {
+		t.Errorf("expected body put back; got %q", body)
+	}
+
+	matched, known := request.Match(&LoggedRequest{
+		Method:  "POST",
+		URL:     "/orders?page=2",
+		Headers: map[string]string{"Content-Type": "application/json", "Accept-Encoding": "gzip, br"},
+		Body:    []byte(`{"id":1}`),
+	})
+	if matched || !known {
+		t.Errorf("expected the request of joined Accept-Encoding not matched; got %v, %v", matched, known)
+	}
+
+	var logged LoggedRequest
+	err = json.Unmarshal([]byte(`{"method":"POST","url":"/orders?page=2","body":"{\"id\":1}",
+		"headers":{"Content-Type":"application/json","Accept-Encoding":["br","gzip"]}}`), &logged)
+	if err != nil {
+		t.Fatalf("LoggedRequest json.Unmarshal error: %v", err)
+	}
+	if matched, known := request.Match(&logged); !matched || !known {
+		t.Errorf("expected the request matched; got %v, %v", matched, known)
+	}
+
+	broken := httptest.NewRequest("POST", "http://example.com/orders", iotest.ErrReader(errors.New("connection reset")))
+	if _, err := RequestFromHTTP(broken); err == nil || !strings.Contains(err.Error(), "read request body: connection reset") {
+		t.Errorf("expected body read error; got %v", err)
+	}
+}
+
+func TestClient_VerifyJSONBody(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Post(URLPathEqualTo("/orders"))
+	server.setCount(t, orders.Request().Clone().WithBodyPattern(MatchingJsonPathWith("$.order.id", EqualTo("42"))), 1)
+
+	if err := client.VerifyJSONBody(orders, "$.order.id", EqualTo("42")).Once(); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+	if err := client.VerifyJSONBody(orders, "$.order.id", EqualTo("43")).Once(); err == nil ||
+		!strings.Contains(err.Error(), `"expression": "$.order.id"`) {
+		t.Errorf("expected failure showing the JSONPath; got %v", err)
+	}
+	if len(orders.Request().BodyPatterns()) != 0 {
+		t.Errorf("expected criteria not modified; got %v", orders.Request().BodyPatterns())
+	}
+}
+
+func TestClient_AwaitRequest(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	callback := Post(URLPathEqualTo("/callback"))
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		server.setFound(t, callback, map[string]interface{}{"id": "r1", "url": "/callback", "method": "POST"})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	request, err := client.AwaitRequest(ctx, callback)
+	if err != nil || request.ID != "r1" {
+		t.Errorf("expected awaited request r1; got %+v, %v", request, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := client.AwaitRequest(ctx, Get(URLPathEqualTo("/never"))); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded; got %v", err)
+	}
+}
+
+func TestVerificationError_NearMisses(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	orders := Get(URLPathEqualTo("/orders")).WithHeader("X-Mode", EqualTo("fast"))
+	pattern, _ := orders.Request().MarshalJSON()
+	server.nearMisses = []json.RawMessage{json.RawMessage(`{"request":{"url":"/orders","method":"GET","headers":{"X-Mode":"FAST"}},` +
+		`"requestPattern":` + string(pattern) + `,"matchResult":{"distance":0.1}}`)}
+
+	err := client.VerifyThat(orders).Once()
+	if err == nil || !strings.Contains(err.Error(), "closest received requests") ||
+		!strings.Contains(err.Error(), `hint: the case differs, consider EqualToIgnoreCase("fast")`) {
+		t.Errorf("expected verification error with hint; got %v", err)
+	}
+}
+
+func TestClient_VerifyMany(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	for i, url := range []string{"/orders", "/orders", "/users"} {
+		server.logServeEvent(t, map[string]interface{}{
+			"id":      fmt.Sprintf("e%d", i),
+			"request": map[string]interface{}{"url": url, "method": "POST", "body": `{"id":1}`},
+		})
+	}
+	orders := NewRequest(http.MethodPost, URLPathEqualTo("/orders"))
+	users := NewRequest(http.MethodPost, URLPathEqualTo("/users"))
+	withID := NewRequest(http.MethodPost, URLPathEqualTo("/orders")).WithBodyPattern(MatchingJsonPath("$.id"))
+	server.setCount(t, withID, 2)
+
+	if err := client.VerifyMany(map[*Request]int64{orders: 2, users: 1, withID: 2}); err != nil {
+		t.Errorf("expected verification to pass; got %v", err)
+	}
+
+	err := client.VerifyMany(map[*Request]int64{orders: 1, users: 1, NewRequest(http.MethodGet, URLPathEqualTo("/orders")): 1})
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) || strings.Count(err.Error(), "expected exactly 1 request matching") != 2 {
+		t.Errorf("expected 2 failed verifications; got %v", err)
+	}
+	if len(server.queries) != 2 {
+		t.Errorf("expected the journal fetched once per call; got %d", len(server.queries))
+	}
+}
+
+func TestClient_VerifyManyReport(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	for i, url := range []string{"/orders", "/orders", "/users"} {
+		server.logServeEvent(t, map[string]interface{}{
+			"id":      fmt.Sprintf("e%d", i),
+			"request": map[string]interface{}{"id": fmt.Sprintf("r%d", i), "url": url, "method": "POST"},
+		})
+	}
+	orders := NewRequest(http.MethodPost, URLPathEqualTo("/orders"))
+	users := NewRequest(http.MethodPost, URLPathEqualTo("/users"))
+
+	results, err := client.VerifyManyReport(map[*Request]int64{orders: 2, users: 2})
+	var verificationErr *VerificationError
+	if !errors.As(err, &verificationErr) || verificationErr.Criteria != users {
+		t.Fatalf("expected users verification to fail; got %v", err)
+	}
+	if len(results) != 2 || results[0].Criteria != orders || !results[0].Passed || len(results[0].Matched) != 2 {
+		t.Errorf("unexpected orders report %+v", results)
+	}
+	if results[1].Passed || results[1].Actual != 1 || len(results[1].Matched) != 1 || results[1].Matched[0].ID != "r2" {
+		t.Errorf("unexpected users report %+v", results[1])
+	}
+
+	stopped := newFakeServer(t)
+	stopped.Close()
+	if results, err := NewClient(stopped.URL).VerifyManyReport(map[*Request]int64{orders: 2}); err == nil || results != nil {
+		t.Errorf("expected request error without reports; got %v, %v", results, err)
+	}
+}
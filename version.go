@@ -0,0 +1,78 @@
+package wiremock
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MetadataVersion is the metadata key of the stub version maintained by Client.UpdateStubVersioned.
+const MetadataVersion = "version"
+
+// ErrStubConflict is returned when the stub mapping was changed by someone else since it was read.
+var ErrStubConflict = errors.New("stub changed concurrently")
+
+// modifyStubAttempts is the number of read-modify-write attempts of ModifyStub.
+const modifyStubAttempts = 3
+
+// Version gives the stub version, zero for stubs never updated by Client.UpdateStubVersioned.
+func (s *StubRule) Version() int64 {
+	switch version := s.metadata[MetadataVersion].(type) {
+	case int64:
+		return version
+	case float64:
+		return int64(version)
+	}
+
+	return 0
+}
+
+// UpdateStubVersioned replaces the registered stub mapping with the id of stubRule, read by GetStub and modified
+// since, when the mapping is still at the version stubRule was read at, and increments the version.
+// Otherwise it returns ErrStubConflict, so concurrent editors of a shared stub do not silently clobber each other.
+// WireMock has no conditional updates, so the version is checked by the client right before the update:
+// it detects stale reads, not updates landing at the very same moment.
+func (c *Client) UpdateStubVersioned(stubRule *StubRule) error {
+	id := stubRule.UUID()
+
+	current, err := c.getStub(id)
+	if err != nil {
+		return fmt.Errorf("update stub versioned: %w", err)
+	}
+	if current.Version() != stubRule.Version() {
+		return fmt.Errorf("update stub %s: version %d, registered %d: %w", id, stubRule.Version(), current.Version(), ErrStubConflict)
+	}
+
+	stubRule.WithMetadata(MetadataVersion, stubRule.Version()+1)
+	if err := c.UpdateStub(id, stubRule); err != nil {
+		stubRule.WithMetadata(MetadataVersion, current.Version())
+		return err
+	}
+
+	return nil
+}
+
+// ModifyStub reads the stub mapping with id, applies modify and writes it back with UpdateStubVersioned,
+// starting over when the stub changed in between. It gives up with ErrStubConflict after a few attempts.
+func (c *Client) ModifyStub(id string, modify func(stubRule *StubRule)) error {
+	var err error
+	for attempt := 0; attempt < modifyStubAttempts; attempt++ {
+		var stubRule *StubRule
+		stubRule, err = c.getStub(id)
+		if err != nil {
+			return fmt.Errorf("modify stub: %w", err)
+		}
+
+		// modify must not change identity or version of the stub
+		version := stubRule.Version()
+		modify(stubRule)
+		stubRule.uuid = id
+		stubRule.WithMetadata(MetadataVersion, version)
+
+		err = c.UpdateStubVersioned(stubRule)
+		if !errors.Is(err, ErrStubConflict) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("modify stub: %w", err)
+}
@@ -0,0 +1,72 @@
+package wiremock
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClient_UpdateStubVersioned(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubRule := Get(URLPathEqualTo("/flags")).WillReturn(`{"beta":false}`, nil, 200)
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	first, _ := client.GetStub(stubRule.UUID())
+	second, _ := client.GetStub(stubRule.UUID())
+
+	if err := client.UpdateStubVersioned(first.WillReturn(`{"beta":true}`, nil, 200)); err != nil {
+		t.Fatalf("UpdateStubVersioned error: %v", err)
+	}
+	if first.Version() != 1 {
+		t.Errorf("expected version 1; got %d", first.Version())
+	}
+
+	err := client.UpdateStubVersioned(second.WillReturn(`{"beta":null}`, nil, 200))
+	if !errors.Is(err, ErrStubConflict) {
+		t.Fatalf("expected ErrStubConflict; got %v", err)
+	}
+
+	registered, _ := client.GetStub(stubRule.UUID())
+	if registered.Response().Body() != `{"beta":true}` || registered.Version() != 1 {
+		t.Errorf("expected the first update kept; got %s", registered)
+	}
+}
+
+func TestClient_ModifyStub(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.URL)
+
+	stubRule := Get(URLPathEqualTo("/flags")).WillReturn(`{"beta":false}`, nil, 200)
+	if err := client.StubFor(stubRule); err != nil {
+		t.Fatalf("StubFor error: %v", err)
+	}
+
+	calls := 0
+	err := client.ModifyStub(stubRule.UUID(), func(stubRule *StubRule) {
+		calls++
+		if calls == 1 {
+			// a concurrent editor updates the stub between the read and the write
+			concurrent, _ := client.GetStub(stubRule.UUID())
+			_ = client.UpdateStubVersioned(concurrent.AtPriority(1))
+		}
+		stubRule.WillReturn(`{"beta":true}`, nil, 200)
+	})
+	if err != nil {
+		t.Fatalf("ModifyStub error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected modify retried once; got %d calls", calls)
+	}
+
+	registered, _ := client.GetStub(stubRule.UUID())
+	if registered.Response().Body() != `{"beta":true}` || registered.Priority() != 1 || registered.Version() != 2 {
+		t.Errorf("expected both updates kept; got %s", registered)
+	}
+
+	if err := client.ModifyStub("9a1b0c33-6e4b-4d0e-8d3e-7e0c2b6a4f00", func(*StubRule) {}); !errors.Is(err, ErrStubNotFound) {
+		t.Errorf("expected ErrStubNotFound; got %v", err)
+	}
+}
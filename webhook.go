@@ -0,0 +1,236 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// webhookActionName is the name of the WireMock post serve action calling webhooks.
+const webhookActionName = "webhook"
+
+// A Webhook is the http request WireMock sends after serving the stub, e.g. to imitate asynchronous callbacks.
+// WireMock 3 serves webhooks out of the box, WireMock 2 needs the webhooks extension.
+type Webhook struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    *string
+	delay   time.Duration
+}
+
+// NewWebhook returns *Webhook sending request with method to url.
+// The url and the body may refer to the served request with response templating, e.g. {{originalRequest.body}}.
+func NewWebhook(method, url string) *Webhook {
+	return &Webhook{
+		method: method,
+		url:    url,
+	}
+}
+
+// WithURL sets url of the webhook request and returns *Webhook
+func (w *Webhook) WithURL(url string) *Webhook {
+	w.url = url
+	return w
+}
+
+// WithHeader sets header of the webhook request and returns *Webhook
+func (w *Webhook) WithHeader(header, value string) *Webhook {
+	if w.headers == nil {
+		w.headers = map[string]string{}
+	}
+
+	w.headers[header] = value
+	return w
+}
+
+// WithBody sets body of the webhook request and returns *Webhook
+func (w *Webhook) WithBody(body string) *Webhook {
+	w.body = &body
+	return w
+}
+
+// WithJSONBody sets JSON body and Content-Type of the webhook request and returns *Webhook
+func (w *Webhook) WithJSONBody(body interface{}) (*Webhook, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("webhook json body: %s", err.Error())
+	}
+
+	return w.WithHeader("Content-Type", ContentTypeJSON).WithBody(string(raw)), nil
+}
+
+// WithDelay sets delay of the webhook request after the response, sent to WireMock in whole milliseconds,
+// and returns *Webhook
+func (w *Webhook) WithDelay(delay time.Duration) *Webhook {
+	w.delay = delay
+	return w
+}
+
+// Method is getter for method
+func (w *Webhook) Method() string {
+	return w.method
+}
+
+// URL is getter for url
+func (w *Webhook) URL() string {
+	return w.url
+}
+
+// Headers is getter for headers
+func (w *Webhook) Headers() map[string]string {
+	return w.headers
+}
+
+// Body is getter for body
+func (w *Webhook) Body() string {
+	if w.body == nil {
+		return ""
+	}
+
+	return *w.body
+}
+
+// Delay is getter for delay
+func (w *Webhook) Delay() time.Duration {
+	return w.delay
+}
+
+// clone returns a copy of Webhook.
+func (w *Webhook) clone() *Webhook {
+	clone := *w
+	clone.body = cloneStringPtr(w.body)
+	if w.headers != nil {
+		clone.headers = make(map[string]string, len(w.headers))
+		for header, value := range w.headers {
+			clone.headers[header] = value
+		}
+	}
+
+	return &clone
+}
+
+// webhookDelay is the WireMock JSON of the fixed delay of the webhook.
+type webhookDelay struct {
+	Type         string `json:"type"`
+	Milliseconds int64  `json:"milliseconds"`
+}
+
+// webhookParameters is the WireMock JSON of the webhook.
+type webhookParameters struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    *string           `json:"body,omitempty"`
+	Delay   *webhookDelay     `json:"delay,omitempty"`
+}
+
+// postServeAction is the WireMock JSON of the action run after serving the stub.
+type postServeAction struct {
+	Name       string          `json:"name"`
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+// MarshalJSON gives the post serve action JSON of the webhook.
+func (w *Webhook) MarshalJSON() ([]byte, error) {
+	parameters := webhookParameters{
+		Method:  w.method,
+		URL:     w.url,
+		Headers: w.headers,
+		Body:    w.body,
+	}
+	if w.delay > 0 {
+		parameters.Delay = &webhookDelay{Type: "fixed", Milliseconds: w.delay.Milliseconds()}
+	}
+
+	raw, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(postServeAction{Name: webhookActionName, Parameters: raw})
+}
+
+// UnmarshalJSON fills Webhook from the post serve action JSON.
+// Random delays are not supported and are read as no delay.
+func (w *Webhook) UnmarshalJSON(data []byte) error {
+	var action postServeAction
+	if err := json.Unmarshal(data, &action); err != nil {
+		return err
+	}
+	if action.Name != webhookActionName {
+		return fmt.Errorf("post serve action %s is not webhook", action.Name)
+	}
+
+	var parameters webhookParameters
+	if err := json.Unmarshal(action.Parameters, &parameters); err != nil {
+		return fmt.Errorf("webhook parameters: %s", err.Error())
+	}
+
+	*w = Webhook{
+		method:  parameters.Method,
+		url:     parameters.URL,
+		headers: parameters.Headers,
+		body:    parameters.Body,
+	}
+	if parameters.Delay != nil && parameters.Delay.Type == "fixed" {
+		w.delay = time.Duration(parameters.Delay.Milliseconds) * time.Millisecond
+	}
+
+	return nil
+}
+
+// WithWebhook adds webhook called after serving the stub and returns *StubRule.
+// Webhooks are registered as post serve actions, which WireMock 3 may give back as serve event listeners,
+// both are read as webhooks.
+func (s *StubRule) WithWebhook(webhook *Webhook) *StubRule {
+	s.webhooks = append(s.webhooks, webhook)
+	return s
+}
+
+// Webhooks is getter for webhooks
+func (s *StubRule) Webhooks() []*Webhook {
+	return s.webhooks
+}
+
+// postServeActionsJSON gives the post serve actions of the stub, webhooks first, nil when there are none.
+func (s *StubRule) postServeActionsJSON() []json.RawMessage {
+	if len(s.webhooks) == 0 && len(s.postServeActions) == 0 {
+		return nil
+	}
+
+	actions := make([]json.RawMessage, 0, len(s.webhooks)+len(s.postServeActions))
+	for _, webhook := range s.webhooks {
+		raw, err := webhook.MarshalJSON()
+		if err != nil {
+			continue
+		}
+		actions = append(actions, raw)
+	}
+
+	return append(actions, s.postServeActions...)
+}
+
+// readWebhooks adds webhooks of the post serve actions or of the serve event listeners to the stub
+// and gives the other actions, kept as they are.
+func (s *StubRule) readWebhooks(actions []json.RawMessage) ([]json.RawMessage, error) {
+	var others []json.RawMessage
+	for _, raw := range actions {
+		var action postServeAction
+		if err := json.Unmarshal(raw, &action); err != nil {
+			return nil, fmt.Errorf("post serve action: %s", err.Error())
+		}
+		if action.Name != webhookActionName {
+			others = append(others, raw)
+			continue
+		}
+
+		webhook := &Webhook{}
+		if err := webhook.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		s.webhooks = append(s.webhooks, webhook)
+	}
+
+	return others, nil
+}
@@ -0,0 +1,59 @@
+package wiremock
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStubRule_WithWebhook(t *testing.T) {
+	stubRule := Post(URLPathEqualTo("/payments")).
+		WithWebhook(NewWebhook(http.MethodPost, "http://callbacks/payments").
+			WithHeader("Content-Type", ContentTypeJSON).
+			WithBody(`{"id":"{{jsonPath originalRequest.body '$.id'}}"}`).
+			WithDelay(time.Second))
+
+	raw, err := json.Marshal(stubRule)
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+	expected := `"postServeActions":[{"name":"webhook","parameters":{"method":"POST","url":"http://callbacks/payments",` +
+		`"headers":{"Content-Type":"application/json"},"body":"{\"id\":\"{{jsonPath originalRequest.body '$.id'}}\"}",` +
+		`"delay":{"type":"fixed","milliseconds":1000}}}]`
+	if !strings.Contains(string(raw), expected) {
+		t.Errorf("expected webhook %s; got %s", expected, raw)
+	}
+
+	var decoded StubRule
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("StubRule json.Unmarshal error: %v", err)
+	}
+	if webhooks := decoded.Webhooks(); len(webhooks) != 1 || webhooks[0].URL() != "http://callbacks/payments" ||
+		webhooks[0].Delay() != time.Second || webhooks[0].Headers()["Content-Type"] != ContentTypeJSON {
+		t.Errorf("unexpected decoded webhooks %+v", webhooks)
+	}
+}
+
+func TestStubRule_ServeEventListeners(t *testing.T) {
+	var stubRule StubRule
+	err := json.Unmarshal([]byte(`{"request":{"method":"POST"},"serveEventListeners":[
+		{"name":"webhook","parameters":{"method":"GET","url":"http://callbacks/ping"}},
+		{"name":"audit","parameters":{"level":"info"}}
+	]}`), &stubRule)
+	if err != nil {
+		t.Fatalf("StubRule json.Unmarshal error: %v", err)
+	}
+	if webhooks := stubRule.Webhooks(); len(webhooks) != 1 || webhooks[0].Method() != http.MethodGet {
+		t.Errorf("expected webhook of serve event listeners; got %+v", webhooks)
+	}
+
+	raw, err := json.Marshal(stubRule.Clone())
+	if err != nil {
+		t.Fatalf("StubRule json.Marshal error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"serveEventListeners":[{"name":"audit","parameters":{"level":"info"}}]`) {
+		t.Errorf("expected other listener kept; got %s", raw)
+	}
+}
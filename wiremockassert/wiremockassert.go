@@ -0,0 +1,90 @@
+// Package wiremockassert provides assertions on requests received by WireMock.
+//
+// Assertions accept any TestingT, so they work with *testing.T as well as with testify or Ginkgo test doubles.
+// On failure they report the criteria and the diff of the closest received requests.
+//
+//	wiremockassert.Called(t, client, wiremock.Post(wiremock.URLPathEqualTo("/orders")), 1)
+package wiremockassert
+
+import (
+	"fmt"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// TestingT is the part of *testing.T used by assertions. It is the same as testify assert.TestingT.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Called asserts that exactly times requests matching criteria were received.
+func Called(t TestingT, client *wiremock.Client, criteria wiremock.RequestCriteria, times int64, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return check(t, client.VerifyThat(criteria).Times(times), msgAndArgs)
+}
+
+// CalledAtLeast asserts that times or more requests matching criteria were received.
+func CalledAtLeast(t TestingT, client *wiremock.Client, criteria wiremock.RequestCriteria, times int64, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return check(t, client.VerifyThat(criteria).AtLeast(times), msgAndArgs)
+}
+
+// NotCalled asserts that no request matching criteria was received.
+func NotCalled(t TestingT, client *wiremock.Client, criteria wiremock.RequestCriteria, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return check(t, client.VerifyThat(criteria).Never(), msgAndArgs)
+}
+
+// CalledInOrder asserts that requests matching every criteria were received in the given order.
+func CalledInOrder(t TestingT, client *wiremock.Client, criteria []wiremock.RequestCriteria, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if err := client.VerifyOrder(criteria...); err != nil {
+		t.Errorf("%s%s", err.Error(), message(msgAndArgs))
+		return false
+	}
+
+	return true
+}
+
+// check reports the failed verification. Its *wiremock.VerificationError renders the closest received requests already.
+func check(t TestingT, err error, msgAndArgs []interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if err == nil {
+		return true
+	}
+
+	t.Errorf("%s%s", err.Error(), message(msgAndArgs))
+	return false
+}
+
+// message formats testify style msgAndArgs.
+func message(msgAndArgs []interface{}) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+
+	if format, ok := msgAndArgs[0].(string); ok {
+		return "\n\n" + fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+
+	return "\n\n" + fmt.Sprint(msgAndArgs...)
+}
@@ -0,0 +1,65 @@
+package wiremockassert
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCalled(t *testing.T) {
+	var nearMissRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/__admin/near-misses/request-pattern" {
+			nearMissRequests++
+		}
+		switch r.URL.Path {
+		case "/__admin/requests/count":
+			_, _ = w.Write([]byte(`{"count": 1}`))
+		case "/__admin/near-misses/request-pattern":
+			_, _ = w.Write([]byte(`{"nearMisses": [{
+				"request": {"method": "POST", "url": "/orders", "headers": {"X-Id": "41"}},
+				"requestPattern": {"method": "POST", "urlPath": "/orders", "headers": {"X-Id": {"equalTo": "42"}}},
+				"matchResult": {"distance": 0.1}
+			}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := wiremock.NewClient(server.URL)
+	criteria := wiremock.Post(wiremock.URLPathEqualTo("/orders")).WithHeader("X-Id", wiremock.EqualTo("42"))
+
+	recorder := &recordingT{}
+	if !Called(recorder, client, criteria, 1) || len(recorder.errors) != 0 {
+		t.Errorf("expected assertion to pass; got %v", recorder.errors)
+	}
+
+	if Called(recorder, client, criteria, 2, "order %s", "42") || len(recorder.errors) != 1 {
+		t.Fatalf("expected assertion to fail")
+	}
+	for _, expected := range []string{
+		"expected exactly 2 requests matching",
+		"closest received requests:",
+		"- header X-Id equalTo: 42\n+ 41",
+		"order 42",
+	} {
+		if !strings.Contains(recorder.errors[0], expected) {
+			t.Errorf("expected failure to contain %q; got\n%s", expected, recorder.errors[0])
+		}
+	}
+	if count := strings.Count(recorder.errors[0], "closest received requests:"); count != 1 || nearMissRequests != 1 {
+		t.Errorf("expected near misses found and rendered once; got %d requests and %d blocks", nearMissRequests, count)
+	}
+}
@@ -0,0 +1,566 @@
+// Package wiremockbinary encodes Go values to CBOR and MessagePack and builds body matchers and responses of them,
+// for mocking the APIs which do not speak JSON, e.g. of IoT devices:
+//
+//	matcher, err := wiremockbinary.CBOR.Matcher(Reading{Sensor: "t1", Value: 21.5})
+//	// ...
+//	stubRule := wiremock.Post(wiremock.URLPathEqualTo("/readings")).WithBodyPattern(matcher)
+//	_, err = wiremockbinary.MessagePack.WillReturn(stubRule, Ack{OK: true}, nil, http.StatusCreated)
+//
+// Values are encoded the way JSON is: nil pointers, slices and maps are null, structs are maps of the exported fields
+// named by the format tag, the json tag or the field name, "-" skips the field and omitempty skips the empty one.
+// The fields of embedded structs conflicting by name are resolved by the encoding/json rules.
+// Map keys and struct fields are sorted by their encoded bytes, shorter first, and numbers take the shortest form
+// keeping the value, so the encoding is deterministic. The binaryEqualTo matcher compares bytes, so it matches the bodies
+// of the services encoding the same way, e.g. with the canonical CBOR options.
+package wiremockbinary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// Format is enum of binary formats, its value is the content type of the format.
+type Format string
+
+// Binary formats.
+const (
+	CBOR        Format = "application/cbor"
+	MessagePack Format = "application/msgpack"
+)
+
+// ContentType gives the content type of the format bodies.
+func (f Format) ContentType() string {
+	return string(f)
+}
+
+// tag gives the struct tag of the format field names.
+func (f Format) tag() string {
+	if f == CBOR {
+		return "cbor"
+	}
+
+	return "msgpack"
+}
+
+// Marshal encodes v in the format.
+func (f Format) Marshal(v interface{}) ([]byte, error) {
+	if f != CBOR && f != MessagePack {
+		return nil, fmt.Errorf("unsupported binary format %q", string(f))
+	}
+
+	var buf bytes.Buffer
+	if err := f.encode(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, fmt.Errorf("marshal %T to %s: %s", v, string(f), err.Error())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Matcher returns binaryEqualTo matcher of v encoded in the format.
+func (f Format) Matcher(v interface{}) (wiremock.ParamMatcher, error) {
+	body, err := f.Marshal(v)
+	if err != nil {
+		return wiremock.ParamMatcher{}, err
+	}
+
+	return wiremock.BinaryEqualTo(body), nil
+}
+
+// Request returns *wiremock.Request of the method and the url with the body of v encoded in the format,
+// the criteria of the request for verification.
+func (f Format) Request(method string, urlMatcher wiremock.URLMatcherInterface, v interface{}) (*wiremock.Request, error) {
+	matcher, err := f.Matcher(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return wiremock.NewRequest(method, urlMatcher).WithBodyPattern(matcher), nil
+}
+
+// WillReturn sets the response of stubRule with v encoded in the format and returns *wiremock.StubRule.
+// Content-Type is the content type of the format unless headers set it.
+func (f Format) WillReturn(stubRule *wiremock.StubRule, v interface{}, headers map[string]string, status int64) (*wiremock.StubRule, error) {
+	body, err := f.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	responseHeaders := map[string]string{"Content-Type": f.ContentType()}
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			delete(responseHeaders, "Content-Type")
+		}
+		responseHeaders[key] = value
+	}
+
+	return stubRule.WillReturnBinary(body, responseHeaders, status), nil
+}
+
+func (f Format) encode(buf *bytes.Buffer, v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			f.writeNil(buf)
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		f.writeNil(buf)
+	case reflect.Bool:
+		f.writeBool(buf, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.writeInt(buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.writeUint(buf, v.Uint())
+	case reflect.Float32:
+		f.writeFloat32(buf, float32(v.Float()))
+	case reflect.Float64:
+		f.writeFloat64(buf, v.Float())
+	case reflect.String:
+		f.writeString(buf, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			f.writeNil(buf)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			f.writeBytes(buf, data)
+			return nil
+		}
+
+		f.writeArrayHeader(buf, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := f.encode(buf, v.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %s", i, err.Error())
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			f.writeNil(buf)
+			return nil
+		}
+
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var key bytes.Buffer
+			if err := f.encode(&key, iter.Key()); err != nil {
+				return fmt.Errorf("key %v: %s", iter.Key(), err.Error())
+			}
+			entries = append(entries, entry{key: key.Bytes(), value: iter.Value(), name: fmt.Sprint(iter.Key())})
+		}
+
+		return f.writeEntries(buf, entries)
+	case reflect.Struct:
+		return f.writeEntries(buf, f.structEntries(v))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// An entry is the encoded key and the value of map or struct.
+type entry struct {
+	key   []byte
+	value reflect.Value
+	// name is the key in errors
+	name string
+}
+
+// writeEntries writes the entries sorted by their encoded keys, shorter first.
+func (f Format) writeEntries(buf *bytes.Buffer, entries []entry) error {
+	sort.Slice(entries, func(i, j int) bool {
+		if len(entries[i].key) != len(entries[j].key) {
+			return len(entries[i].key) < len(entries[j].key)
+		}
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	f.writeMapHeader(buf, len(entries))
+	for _, e := range entries {
+		buf.Write(e.key)
+		if err := f.encode(buf, e.value); err != nil {
+			return fmt.Errorf("%s: %s", e.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// A structField is the field of the struct or of its embedded structs, with its value when it is encoded.
+type structField struct {
+	name   string
+	value  reflect.Value
+	depth  int
+	tagged bool
+	// omitted is the empty omitempty field or the field of the nil embedded pointer,
+	// it still hides the fields of the same name of the deeper embedded structs
+	omitted bool
+}
+
+// structEntries gives the entries of the exported fields of the struct, as encoding/json names them.
+// Of the fields of the same name the shallowest one is encoded, the tagged one among the shallowest,
+// and none when that leaves several.
+func (f Format) structEntries(v reflect.Value) []entry {
+	var fields []structField
+	f.appendFields(&fields, v, 0, false, map[reflect.Type]bool{v.Type(): true})
+
+	byName := map[string][]structField{}
+	var names []string
+	for _, field := range fields {
+		if _, ok := byName[field.name]; !ok {
+			names = append(names, field.name)
+		}
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	entries := make([]entry, 0, len(names))
+	for _, name := range names {
+		field, ok := dominantField(byName[name])
+		if !ok || field.omitted {
+			continue
+		}
+
+		var key bytes.Buffer
+		f.writeString(&key, name)
+		entries = append(entries, entry{key: key.Bytes(), value: field.value, name: name})
+	}
+
+	return entries
+}
+
+// appendFields appends the exported fields of the struct at depth, with the fields of the embedded structs
+// without names one level deeper. The fields of nil embedded pointers are omitted, and the structs embedding
+// themselves are visited once.
+func (f Format) appendFields(fields *[]structField, v reflect.Value, depth int, omitted bool, visited map[reflect.Type]bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty := fieldName(field, f.tag())
+		if name == "-" {
+			continue
+		}
+
+		var value reflect.Value
+		if !omitted {
+			value = v.Field(i)
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if field.Type.Kind() == reflect.Pointer && !field.IsExported() {
+				continue
+			}
+			if embedded.Kind() == reflect.Struct {
+				if visited[embedded] {
+					continue
+				}
+				embeddedOmitted := omitted
+				if !omitted && value.Kind() == reflect.Pointer {
+					if value.IsNil() {
+						embeddedOmitted = true
+					} else {
+						value = value.Elem()
+					}
+				}
+				if embeddedOmitted {
+					value = reflect.Zero(embedded)
+				}
+				visited[embedded] = true
+				f.appendFields(fields, value, depth+1, embeddedOmitted, visited)
+				delete(visited, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = field.Name
+		}
+		*fields = append(*fields, structField{
+			name:    name,
+			value:   value,
+			depth:   depth,
+			tagged:  tagged,
+			omitted: omitted || (omitEmpty && value.IsZero()),
+		})
+	}
+}
+
+// dominantField gives the field encoded of the fields of the same name, false when they conflict.
+func dominantField(fields []structField) (structField, bool) {
+	depth := fields[0].depth
+	for _, field := range fields {
+		if field.depth < depth {
+			depth = field.depth
+		}
+	}
+
+	var shallowest, tagged []structField
+	for _, field := range fields {
+		if field.depth != depth {
+			continue
+		}
+		shallowest = append(shallowest, field)
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+
+	return structField{}, false
+}
+
+// fieldName gives the name of the field set by the format tag or the json tag, empty when not set.
+func fieldName(field reflect.StructField, formatTag string) (string, bool) {
+	tag, ok := field.Tag.Lookup(formatTag)
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "-", false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+
+	return name, false
+}
+
+func (f Format) writeNil(buf *bytes.Buffer) {
+	if f == CBOR {
+		buf.WriteByte(0xf6)
+		return
+	}
+
+	buf.WriteByte(0xc0)
+}
+
+func (f Format) writeBool(buf *bytes.Buffer, value bool) {
+	switch {
+	case f == CBOR && value:
+		buf.WriteByte(0xf5)
+	case f == CBOR:
+		buf.WriteByte(0xf4)
+	case value:
+		buf.WriteByte(0xc3)
+	default:
+		buf.WriteByte(0xc2)
+	}
+}
+
+func (f Format) writeInt(buf *bytes.Buffer, value int64) {
+	if value >= 0 {
+		f.writeUint(buf, uint64(value))
+		return
+	}
+
+	if f == CBOR {
+		writeCBORHead(buf, 1, uint64(-1-value))
+		return
+	}
+
+	switch {
+	case value >= -32:
+		buf.WriteByte(byte(value))
+	case value >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(value)})
+	case value >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(value)))
+	case value >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(value)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(value)))
+	}
+}
+
+func (f Format) writeUint(buf *bytes.Buffer, value uint64) {
+	if f == CBOR {
+		writeCBORHead(buf, 0, value)
+		return
+	}
+
+	if value < 0x80 {
+		buf.WriteByte(byte(value))
+		return
+	}
+	writeMessagePackLength(buf, value, 0xcc, 0xcd, 0xce, 0xcf)
+}
+
+// writeFloat32 writes value as CBOR half precision float when it keeps the value.
+func (f Format) writeFloat32(buf *bytes.Buffer, value float32) {
+	if f == CBOR {
+		if half, ok := float16Bits(value); ok {
+			buf.WriteByte(0xf9)
+			buf.Write(binary.BigEndian.AppendUint16(nil, half))
+			return
+		}
+		buf.WriteByte(0xfa)
+	} else {
+		buf.WriteByte(0xca)
+	}
+	buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(value)))
+}
+
+// writeFloat64 writes value as the shorter float when it keeps the value.
+func (f Format) writeFloat64(buf *bytes.Buffer, value float64) {
+	if math.IsNaN(value) || float64(float32(value)) == value {
+		f.writeFloat32(buf, float32(value))
+		return
+	}
+
+	if f == CBOR {
+		buf.WriteByte(0xfb)
+	} else {
+		buf.WriteByte(0xcb)
+	}
+	buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(value)))
+}
+
+// float16Bits gives the half precision bits of value, false when they do not keep the value.
+// NaN is the canonical quiet NaN.
+func float16Bits(value float32) (uint16, bool) {
+	bits := math.Float32bits(value)
+	sign := uint16(bits>>16) & 0x8000
+	exponent := int(bits>>23&0xff) - 127
+	mantissa := bits & 0x7fffff
+
+	switch {
+	case value != value:
+		return 0x7e00, true
+	case math.IsInf(float64(value), 0):
+		return sign | 0x7c00, true
+	case bits&0x7fffffff == 0:
+		return sign, true
+	case exponent == -127:
+		// float32 subnormals are below the half precision range
+		return 0, false
+	case exponent >= -14 && exponent <= 15:
+		if mantissa&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exponent+15)<<10 | uint16(mantissa>>13), true
+	case exponent >= -24 && exponent < -14:
+		// half precision subnormal of the significand shifted right
+		significand := mantissa | 0x800000
+		shift := uint(-exponent - 1)
+		if significand&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(significand>>shift), true
+	}
+
+	return 0, false
+}
+
+func (f Format) writeString(buf *bytes.Buffer, value string) {
+	switch {
+	case f == CBOR:
+		writeCBORHead(buf, 3, uint64(len(value)))
+	case len(value) < 32:
+		buf.WriteByte(0xa0 | byte(len(value)))
+	default:
+		writeMessagePackLength(buf, uint64(len(value)), 0xd9, 0xda, 0xdb, 0)
+	}
+	buf.WriteString(value)
+}
+
+func (f Format) writeBytes(buf *bytes.Buffer, value []byte) {
+	if f == CBOR {
+		writeCBORHead(buf, 2, uint64(len(value)))
+	} else {
+		writeMessagePackLength(buf, uint64(len(value)), 0xc4, 0xc5, 0xc6, 0)
+	}
+	buf.Write(value)
+}
+
+func (f Format) writeArrayHeader(buf *bytes.Buffer, length int) {
+	switch {
+	case f == CBOR:
+		writeCBORHead(buf, 4, uint64(length))
+	case length < 16:
+		buf.WriteByte(0x90 | byte(length))
+	default:
+		writeMessagePackLength(buf, uint64(length), 0, 0xdc, 0xdd, 0)
+	}
+}
+
+func (f Format) writeMapHeader(buf *bytes.Buffer, length int) {
+	switch {
+	case f == CBOR:
+		writeCBORHead(buf, 5, uint64(length))
+	case length < 16:
+		buf.WriteByte(0x80 | byte(length))
+	default:
+		writeMessagePackLength(buf, uint64(length), 0, 0xde, 0xdf, 0)
+	}
+}
+
+// writeCBORHead writes the initial byte of the major type with the shortest argument of value.
+func writeCBORHead(buf *bytes.Buffer, major byte, value uint64) {
+	major <<= 5
+	switch {
+	case value < 24:
+		buf.WriteByte(major | byte(value))
+	case value <= math.MaxUint8:
+		buf.Write([]byte{major | 24, byte(value)})
+	case value <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(value)))
+	case value <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(value)))
+	default:
+		buf.WriteByte(major | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, value))
+	}
+}
+
+// writeMessagePackLength writes the shortest of the 8, 16, 32 and 64 bit forms of value,
+// the zero type byte marks the form the type does not have.
+func writeMessagePackLength(buf *bytes.Buffer, value uint64, type8, type16, type32, type64 byte) {
+	switch {
+	case value <= math.MaxUint8 && type8 != 0:
+		buf.Write([]byte{type8, byte(value)})
+	case value <= math.MaxUint16:
+		buf.WriteByte(type16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(value)))
+	case value <= math.MaxUint32 || type64 == 0:
+		buf.WriteByte(type32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(value)))
+	default:
+		buf.WriteByte(type64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, value))
+	}
+}
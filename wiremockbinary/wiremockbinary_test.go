@@ -0,0 +1,121 @@
+package wiremockbinary
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+type reading struct {
+	Sensor string `cbor:"s" msgpack:"sensor"`
+	Raw    []byte `json:"raw,omitempty"`
+	Secret string `json:"-"`
+}
+
+type sensor struct {
+	ID   string `json:"id"`
+	Name string
+}
+
+type label struct {
+	Name string
+}
+
+type device struct {
+	sensor
+	label
+	Version int    `json:"v"`
+	ID      string `json:"id"`
+}
+
+func TestFormat_Marshal(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       interface{}
+		cbor        string
+		messagePack string
+	}{
+		{
+			name:        "map sorted by keys",
+			value:       map[string]interface{}{"b": []int{2, 3}, "a": 1},
+			cbor:        "a2" + "6161" + "01" + "6162" + "820203",
+			messagePack: "82" + "a161" + "01" + "a162" + "920203",
+		},
+		{
+			name:        "integers of the shortest form",
+			value:       []int64{-1, -100, 500},
+			cbor:        "83" + "20" + "3863" + "1901f4",
+			messagePack: "93" + "ff" + "d09c" + "cd01f4",
+		},
+		{
+			name:        "struct fields named by tags",
+			value:       &reading{Sensor: "t", Secret: "x"},
+			cbor:        "a1" + "6173" + "6174",
+			messagePack: "81" + "a673656e736f72" + "a174",
+		},
+		{
+			name:        "bytes, null and bool",
+			value:       []interface{}{[]byte{1}, nil, true, 1.5},
+			cbor:        "84" + "4101" + "f6" + "f5" + "f93e00",
+			messagePack: "94" + "c40101" + "c0" + "c3" + "ca3fc00000",
+		},
+		{
+			name:        "floats of the shortest form",
+			value:       []interface{}{float32(1.5), 100000.0, math.Ldexp(1, -24), 0.1},
+			cbor:        "84" + "f93e00" + "fa47c35000" + "f90001" + "fb3fb999999999999a",
+			messagePack: "94" + "ca3fc00000" + "ca47c35000" + "ca33800000" + "cb3fb999999999999a",
+		},
+		{
+			name:        "struct fields sorted and resolved as encoding/json does",
+			value:       device{sensor: sensor{ID: "s", Name: "x"}, label: label{Name: "y"}, Version: 2, ID: "d"},
+			cbor:        "a2" + "6176" + "02" + "626964" + "6164",
+			messagePack: "82" + "a176" + "02" + "a26964" + "a164",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for format, expected := range map[Format]string{CBOR: tc.cbor, MessagePack: tc.messagePack} {
+				body, err := format.Marshal(tc.value)
+				if err != nil {
+					t.Fatalf("%s Marshal error: %v", format, err)
+				}
+				if hex.EncodeToString(body) != expected {
+					t.Errorf("expected %s %s; got %x", format, expected, body)
+				}
+			}
+		})
+	}
+
+	if _, err := CBOR.Marshal(map[string]interface{}{"callback": func() {}}); err == nil {
+		t.Error("expected error of unsupported type")
+	}
+}
+
+func TestFormat_Matcher(t *testing.T) {
+	criteria, err := MessagePack.Request("POST", wiremock.URLPathEqualTo("/readings"), reading{Sensor: "t"})
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+
+	body, _ := hex.DecodeString("81a673656e736f72a174")
+	if matched, known := criteria.Match(&wiremock.LoggedRequest{Method: "POST", URL: "/readings", Body: body}); !matched || !known {
+		t.Errorf("expected the request matched; got %v, %v", matched, known)
+	}
+
+	stubRule, err := CBOR.WillReturn(wiremock.Get(wiremock.URLPathEqualTo("/readings/1")), reading{Sensor: "t"}, map[string]string{"X-Id": "1"}, 200)
+	if err != nil {
+		t.Fatalf("WillReturn error: %v", err)
+	}
+	raw, err := json.Marshal(stubRule.Response())
+	if err != nil {
+		t.Fatalf("Response json.Marshal error: %v", err)
+	}
+	expected := `{"base64Body":"oWFzYXQ=","headers":{"Content-Type":"application/cbor","X-Id":"1"},"status":200}`
+	if string(raw) != expected {
+		t.Errorf("expected response %s; got %s", expected, raw)
+	}
+}
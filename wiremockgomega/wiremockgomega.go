@@ -0,0 +1,105 @@
+// Package wiremockgomega provides Gomega matchers for requests received by WireMock.
+//
+// The matchers implement types.GomegaMatcher and query the server on every match, so they work with Eventually:
+//
+//	Expect(client).To(wiremockgomega.HaveReceivedRequest(wiremock.Get(wiremock.URLPathEqualTo("/orders"))))
+//	Eventually(client.VerifyThat(callback)).Should(wiremockgomega.HaveBeenCalledTimes(1))
+package wiremockgomega
+
+import (
+	"fmt"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// HaveReceivedRequest succeeds when the actual *wiremock.Client received at least one request matching criteria.
+func HaveReceivedRequest(criteria wiremock.RequestCriteria) *ReceivedRequestMatcher {
+	return &ReceivedRequestMatcher{criteria: criteria.Criteria()}
+}
+
+// A ReceivedRequestMatcher matches *wiremock.Client which received requests matching criteria.
+type ReceivedRequestMatcher struct {
+	criteria *wiremock.Request
+	actual   int64
+}
+
+// Match implements types.GomegaMatcher.
+func (m *ReceivedRequestMatcher) Match(actual interface{}) (bool, error) {
+	client, ok := actual.(*wiremock.Client)
+	if !ok {
+		return false, fmt.Errorf("HaveReceivedRequest matcher expects *wiremock.Client; got %T", actual)
+	}
+
+	count, err := client.GetCountRequests(m.criteria)
+	if err != nil {
+		return false, err
+	}
+	m.actual = count
+
+	return count > 0, nil
+}
+
+// FailureMessage implements types.GomegaMatcher.
+func (m *ReceivedRequestMatcher) FailureMessage(interface{}) string {
+	return fmt.Sprintf("Expected WireMock to receive request matching\n%s\nbut received none", m.criteria)
+}
+
+// NegatedFailureMessage implements types.GomegaMatcher.
+func (m *ReceivedRequestMatcher) NegatedFailureMessage(interface{}) string {
+	return fmt.Sprintf("Expected WireMock not to receive request matching\n%s\nbut received %d", m.criteria, m.actual)
+}
+
+// HaveBeenCalledTimes succeeds when the actual *wiremock.Verification counts exactly times requests.
+func HaveBeenCalledTimes(times int64) *CalledTimesMatcher {
+	return &CalledTimesMatcher{times: times}
+}
+
+// A CalledTimesMatcher matches *wiremock.Verification counting exactly expected requests.
+type CalledTimesMatcher struct {
+	times      int64
+	actual     int64
+	nearMisses string
+}
+
+// Match implements types.GomegaMatcher.
+func (m *CalledTimesMatcher) Match(actual interface{}) (bool, error) {
+	verification, ok := actual.(*wiremock.Verification)
+	if !ok {
+		return false, fmt.Errorf("HaveBeenCalledTimes matcher expects *wiremock.Verification; got %T", actual)
+	}
+
+	count, err := verification.Count()
+	if err != nil {
+		return false, err
+	}
+	m.actual = count
+
+	if count == m.times {
+		return true, nil
+	}
+
+	m.nearMisses = ""
+	if misses, err := verification.Client().FindNearMisses(verification); err == nil && len(misses) > 0 {
+		m.nearMisses = "\n\nclosest received requests:\n\n" + wiremock.FormatNearMisses(misses)
+	}
+
+	return false, nil
+}
+
+// FailureMessage implements types.GomegaMatcher.
+func (m *CalledTimesMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected %d request(s) matching\n%s\nbut received %d%s", m.times, criteriaOf(actual), m.actual, m.nearMisses)
+}
+
+// NegatedFailureMessage implements types.GomegaMatcher.
+func (m *CalledTimesMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected other than %d request(s) matching\n%s", m.times, criteriaOf(actual))
+}
+
+func criteriaOf(actual interface{}) string {
+	if verification, ok := actual.(*wiremock.Verification); ok {
+		return verification.Criteria().String()
+	}
+
+	return fmt.Sprint(actual)
+}
@@ -0,0 +1,48 @@
+package wiremockgomega
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+func TestMatchers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/__admin/requests/count":
+			_, _ = w.Write([]byte(`{"count": 1}`))
+		case "/__admin/near-misses/request-pattern":
+			_, _ = w.Write([]byte(`{"nearMisses": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := wiremock.NewClient(server.URL)
+	criteria := wiremock.Get(wiremock.URLPathEqualTo("/orders"))
+
+	received := HaveReceivedRequest(criteria)
+	if ok, err := received.Match(client); !ok || err != nil {
+		t.Errorf("expected HaveReceivedRequest to match; got %v, %v", ok, err)
+	}
+	if _, err := received.Match("client"); err == nil {
+		t.Errorf("expected error for non client actual")
+	}
+
+	verification := client.VerifyThat(criteria)
+	if ok, err := HaveBeenCalledTimes(1).Match(verification); !ok || err != nil {
+		t.Errorf("expected HaveBeenCalledTimes(1) to match; got %v, %v", ok, err)
+	}
+
+	twice := HaveBeenCalledTimes(2)
+	if ok, err := twice.Match(verification); ok || err != nil {
+		t.Errorf("expected HaveBeenCalledTimes(2) not to match; got %v, %v", ok, err)
+	}
+	message := twice.FailureMessage(verification)
+	if !strings.Contains(message, "Expected 2 request(s) matching") || !strings.Contains(message, "but received 1") {
+		t.Errorf("unexpected failure message %q", message)
+	}
+}
@@ -0,0 +1,150 @@
+// Package wiremockmetrics exposes Prometheus metrics of requests served by WireMock.
+//
+// The collector scrapes the request journal periodically and serves the metrics in the Prometheus text format,
+// so WireMock backing a long-running performance environment can be monitored without extensions:
+//
+//	collector := wiremockmetrics.NewCollector(client)
+//	go collector.Run(ctx, 15*time.Second)
+//	http.Handle("/metrics", collector)
+//
+// The journal must be enabled and large enough to keep the requests received between scrapes.
+package wiremockmetrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// A Collector counts serve events of the WireMock request journal.
+type Collector struct {
+	client *wiremock.Client
+
+	mu sync.Mutex
+	// since is the logged date of the most recent scraped event
+	since time.Time
+	// seen are ids of events logged close enough to since to be returned again
+	seen         map[string]time.Time
+	requests     map[string]float64
+	addedDelay   map[string]float64
+	unmatched    float64
+	scrapeErrors float64
+}
+
+// NewCollector returns *Collector of the WireMock server of client.
+func NewCollector(client *wiremock.Client) *Collector {
+	return &Collector{
+		client:     client,
+		seen:       map[string]time.Time{},
+		requests:   map[string]float64{},
+		addedDelay: map[string]float64{},
+	}
+}
+
+// Run scrapes the journal every interval until ctx is done.
+// Scrape errors are counted by the wiremock_scrape_errors_total metric.
+func (c *Collector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = c.Scrape()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scrape counts serve events logged since the previous scrape.
+func (c *Collector) Scrape() error {
+	query := wiremock.NewServeEventQuery()
+	c.mu.Lock()
+	if !c.since.IsZero() {
+		// the journal keeps milliseconds, so events of the last scraped millisecond may still be arriving
+		query.WithSince(c.since.Add(-time.Millisecond))
+	}
+	c.mu.Unlock()
+
+	events, err := c.client.GetServeEvents(query)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.scrapeErrors++
+		return fmt.Errorf("scrape: %s", err.Error())
+	}
+
+	for _, event := range events {
+		if _, ok := c.seen[event.ID]; ok {
+			continue
+		}
+		c.seen[event.ID] = event.Request.LoggedDate
+		if event.Request.LoggedDate.After(c.since) {
+			c.since = event.Request.LoggedDate
+		}
+
+		if !event.WasMatched {
+			c.unmatched++
+			continue
+		}
+		stub := event.StubID()
+		c.requests[stub]++
+		c.addedDelay[stub] += event.Timing.AddedDelay.Seconds()
+	}
+
+	for id, loggedDate := range c.seen {
+		if loggedDate.Before(c.since.Add(-time.Millisecond)) {
+			delete(c.seen, id)
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeMetric(w, "wiremock_requests_total", "Requests matched by the stub.", "stub", c.requests)
+	writeMetric(w, "wiremock_added_delay_seconds_total", "Delay injected into responses of the stub.", "stub", c.addedDelay)
+	writeMetric(w, "wiremock_unmatched_requests_total", "Requests matched by no stub.", "", map[string]float64{"": c.unmatched})
+	writeMetric(w, "wiremock_scrape_errors_total", "Failed scrapes of the request journal.", "", map[string]float64{"": c.scrapeErrors})
+}
+
+func writeMetric(w io.Writer, name, help, label string, values map[string]float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if label == "" {
+			fmt.Fprintf(w, "%s %g\n", name, values[key])
+			continue
+		}
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %g\n", name, label, escapeLabel(key), values[key])
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
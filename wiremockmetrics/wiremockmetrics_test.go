@@ -0,0 +1,71 @@
+package wiremockmetrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+func TestCollector(t *testing.T) {
+	var mu sync.Mutex
+	var events []map[string]interface{}
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		sinces = append(sinces, r.URL.Query().Get("since"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"requests": events})
+	}))
+	defer server.Close()
+
+	logEvent := func(id string, loggedDate int64, stubID string, addedDelay int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		event := map[string]interface{}{
+			"id":         id,
+			"request":    map[string]interface{}{"url": "/orders", "method": "GET", "loggedDate": loggedDate},
+			"wasMatched": stubID != "",
+			"timing":     map[string]interface{}{"addedDelay": addedDelay, "totalTime": addedDelay + 2},
+		}
+		if stubID != "" {
+			event["stubMapping"] = map[string]interface{}{"id": stubID}
+		}
+		events = append([]map[string]interface{}{event}, events...)
+	}
+
+	collector := NewCollector(wiremock.NewClient(server.URL))
+	logEvent("e1", 1700000000000, "orders", 250)
+	logEvent("e2", 1700000000000, "", 0)
+	if err := collector.Scrape(); err != nil {
+		t.Fatalf("Scrape error: %v", err)
+	}
+
+	// the fake journal ignores since, so already counted events come again and must be skipped
+	logEvent("e3", 1700000000001, "orders", 250)
+	if err := collector.Scrape(); err != nil {
+		t.Fatalf("Scrape error: %v", err)
+	}
+	if sinces[0] != "" || sinces[1] != "2023-11-14T22:13:19.999Z" {
+		t.Errorf("unexpected since of scrapes %q", sinces)
+	}
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	for _, expected := range []string{
+		"# TYPE wiremock_requests_total counter\nwiremock_requests_total{stub=\"orders\"} 2\n",
+		"wiremock_added_delay_seconds_total{stub=\"orders\"} 0.5\n",
+		"wiremock_unmatched_requests_total 1\n",
+		"wiremock_scrape_errors_total 0\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metrics to contain %q; got\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package wiremockoidc sets up a fake OAuth2/OpenID Connect identity provider on WireMock:
+// the discovery document, the JWKS endpoint with a generated RSA key, the token endpoint
+// issuing RS256 signed JWTs, the authorization endpoint and the userinfo endpoint.
+//
+//	provider, err := wiremockoidc.NewProvider("http://localhost:8080/idp")
+//	// ...
+//	err = provider.WithClaim("roles", []string{"admin"}).Register(client)
+//
+// WireMock cannot sign tokens per request, so the token endpoint returns a token signed
+// when the stubs are built. Use IssueToken to sign tokens in the test itself.
+package wiremockoidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	jwksPath      = "/.well-known/jwks.json"
+	tokenPath     = "/token"
+	authorizePath = "/authorize"
+	userinfoPath  = "/userinfo"
+)
+
+// AuthorizationCode is the code the authorization endpoint redirects with.
+// The token endpoint accepts any code, so it only has to be passed through.
+const AuthorizationCode = "test-authorization-code"
+
+// A Provider is the fake identity provider served by WireMock under the issuer path.
+type Provider struct {
+	issuer     string
+	pathPrefix string
+	key        *rsa.PrivateKey
+	keyID      string
+	claims     map[string]interface{}
+	tokenTTL   time.Duration
+	now        func() time.Time
+}
+
+// NewProvider returns *Provider with a newly generated RSA key.
+// The issuer is the URL of WireMock as seen by the tested service, its path is the prefix of the provider endpoints.
+func NewProvider(issuer string) (*Provider, error) {
+	issuerURL, err := url.Parse(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("parse issuer: %s", err.Error())
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %s", err.Error())
+	}
+
+	keyHash := sha256.Sum256(key.PublicKey.N.Bytes())
+
+	return &Provider{
+		issuer:     strings.TrimSuffix(issuer, "/"),
+		pathPrefix: strings.TrimSuffix(issuerURL.Path, "/"),
+		key:        key,
+		keyID:      base64.RawURLEncoding.EncodeToString(keyHash[:12]),
+		claims: map[string]interface{}{
+			"sub": "test-user",
+		},
+		tokenTTL: 24 * time.Hour,
+		now:      time.Now,
+	}, nil
+}
+
+// WithClaim adds claim to every issued token and returns *Provider
+func (p *Provider) WithClaim(key string, value interface{}) *Provider {
+	p.claims[key] = value
+	return p
+}
+
+// WithTokenTTL sets lifetime of issued tokens and returns *Provider
+func (p *Provider) WithTokenTTL(ttl time.Duration) *Provider {
+	p.tokenTTL = ttl
+	return p
+}
+
+// Issuer is getter for issuer
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// TokenEndpoint gives URL of the token endpoint.
+func (p *Provider) TokenEndpoint() string {
+	return p.issuer + tokenPath
+}
+
+// PublicKey gives the key verifying issued tokens.
+func (p *Provider) PublicKey() *rsa.PublicKey {
+	return &p.key.PublicKey
+}
+
+// IssueToken signs JWT with provider claims overridden by claims.
+func (p *Provider) IssueToken(claims map[string]interface{}) (string, error) {
+	now := p.now()
+	payload := map[string]interface{}{
+		"iss": p.issuer,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(p.tokenTTL).Unix(),
+	}
+	for key, value := range p.claims {
+		payload[key] = value
+	}
+	for key, value := range claims {
+		payload[key] = value
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": p.keyID})
+	if err != nil {
+		return "", fmt.Errorf("issue token: %s", err.Error())
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("issue token: %s", err.Error())
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("issue token: %s", err.Error())
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DiscoveryStub returns stub of the OpenID Connect discovery document.
+func (p *Provider) DiscoveryStub() *wiremock.StubRule {
+	return wiremock.Get(wiremock.URLPathEqualTo(p.pathPrefix+discoveryPath)).
+		WillReturnJSON(
+			map[string]interface{}{
+				"issuer":                                p.issuer,
+				"authorization_endpoint":                p.issuer + authorizePath,
+				"token_endpoint":                        p.TokenEndpoint(),
+				"userinfo_endpoint":                     p.issuer + userinfoPath,
+				"jwks_uri":                              p.issuer + jwksPath,
+				"response_types_supported":              []string{"code", "token", "id_token"},
+				"subject_types_supported":               []string{"public"},
+				"id_token_signing_alg_values_supported": []string{"RS256"},
+				"grant_types_supported":                 []string{"authorization_code", "client_credentials", "refresh_token", "password"},
+				"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+			},
+			map[string]string{"Content-Type": "application/json"},
+			http.StatusOK,
+		)
+}
+
+// JWKSStub returns stub of the JSON Web Key Set with the provider public key.
+func (p *Provider) JWKSStub() *wiremock.StubRule {
+	return wiremock.Get(wiremock.URLPathEqualTo(p.pathPrefix+jwksPath)).
+		WillReturnJSON(
+			map[string]interface{}{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"use": "sig",
+					"alg": "RS256",
+					"kid": p.keyID,
+					"n":   base64.RawURLEncoding.EncodeToString(p.key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.PublicKey.E)).Bytes()),
+				}},
+			},
+			map[string]string{"Content-Type": "application/json"},
+			http.StatusOK,
+		)
+}
+
+// TokenStub returns stub of the token endpoint answering with access and id tokens signed with claims.
+// Add request matchers (e.g. on client_id in the body) and priority to serve different tokens to different clients.
+func (p *Provider) TokenStub(claims map[string]interface{}) (*wiremock.StubRule, error) {
+	token, err := p.IssueToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return wiremock.Post(wiremock.URLPathEqualTo(p.pathPrefix+tokenPath)).
+		WillReturnJSON(
+			map[string]interface{}{
+				"access_token": token,
+				"id_token":     token,
+				"token_type":   "Bearer",
+				"expires_in":   int64(p.tokenTTL / time.Second),
+			},
+			map[string]string{"Content-Type": "application/json", "Cache-Control": "no-store"},
+			http.StatusOK,
+		), nil
+}
+
+// AuthorizeStub returns stub of the authorization endpoint.
+// It skips the login and redirects to redirect_uri with AuthorizationCode and the state of the request.
+func (p *Provider) AuthorizeStub() *wiremock.StubRule {
+	return wiremock.Get(wiremock.URLPathEqualTo(p.pathPrefix+authorizePath)).
+		WithQueryParam("redirect_uri", wiremock.Matching(".+")).
+		WillReturnRedirect(
+			"{{request.query.redirect_uri}}?code="+AuthorizationCode+"{{#if request.query.state}}&state={{request.query.state}}{{/if}}",
+			http.StatusFound,
+		).
+		WithResponseTemplating()
+}
+
+// UserinfoStub returns stub of the userinfo endpoint answering with the provider claims.
+func (p *Provider) UserinfoStub() *wiremock.StubRule {
+	claims := make(map[string]interface{}, len(p.claims))
+	for key, value := range p.claims {
+		claims[key] = value
+	}
+
+	return wiremock.Get(wiremock.URLPathEqualTo(p.pathPrefix+userinfoPath)).
+		WillReturnJSON(
+			claims,
+			map[string]string{"Content-Type": "application/json"},
+			http.StatusOK,
+		)
+}
+
+// Stubs returns discovery, JWKS, token, authorization and userinfo endpoint stubs.
+func (p *Provider) Stubs() ([]*wiremock.StubRule, error) {
+	tokenStub, err := p.TokenStub(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*wiremock.StubRule{p.DiscoveryStub(), p.JWKSStub(), tokenStub, p.AuthorizeStub(), p.UserinfoStub()}, nil
+}
+
+// Register registers provider stubs.
+func (p *Provider) Register(client *wiremock.Client) error {
+	stubs, err := p.Stubs()
+	if err != nil {
+		return err
+	}
+
+	for _, stubRule := range stubs {
+		if err := client.StubFor(stubRule); err != nil {
+			return fmt.Errorf("register identity provider: %s", err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package wiremockoidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+func TestProvider_IssueToken(t *testing.T) {
+	provider, err := NewProvider("http://localhost:8080/idp/")
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	provider.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	token, err := provider.WithClaim("aud", "orders").WithTokenTTL(time.Hour).IssueToken(map[string]interface{}{"sub": "alice"})
+	if err != nil {
+		t.Fatalf("IssueToken error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected JWT of 3 parts; got %q", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("signature decode error: %v", err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(provider.PublicKey(), crypto.SHA256, hash[:], signature); err != nil {
+		t.Errorf("expected valid signature; got %v", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("payload decode error: %v", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("payload json.Unmarshal error: %v", err)
+	}
+	if claims["iss"] != "http://localhost:8080/idp" || claims["sub"] != "alice" || claims["aud"] != "orders" || claims["exp"] != float64(1700003600) {
+		t.Errorf("unexpected claims %v", claims)
+	}
+}
+
+func TestProvider_Register(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var mapping struct {
+			Request struct {
+				URLPath string `json:"urlPath"`
+			} `json:"request"`
+		}
+		_ = json.Unmarshal(body, &mapping)
+
+		mu.Lock()
+		paths = append(paths, mapping.Request.URLPath)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("http://localhost:8080/idp")
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	if err := provider.Register(wiremock.NewClient(server.URL)); err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+
+	expected := []string{"/idp/.well-known/openid-configuration", "/idp/.well-known/jwks.json", "/idp/token", "/idp/authorize", "/idp/userinfo"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected stubs %v; got %v", expected, paths)
+	}
+}
+
+func TestProvider_AuthorizeStub(t *testing.T) {
+	provider, err := NewProvider("http://localhost:8080/idp")
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+
+	body, err := json.Marshal(provider.AuthorizeStub())
+	if err != nil {
+		t.Fatalf("AuthorizeStub json.Marshal error: %v", err)
+	}
+
+	var mapping struct {
+		Request struct {
+			URLPath string `json:"urlPath"`
+		} `json:"request"`
+		Response struct {
+			Status       int64             `json:"status"`
+			Headers      map[string]string `json:"headers"`
+			Transformers []string          `json:"transformers"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &mapping); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+
+	if mapping.Request.URLPath != "/idp/authorize" || mapping.Response.Status != http.StatusFound {
+		t.Errorf("unexpected authorize stub %s", body)
+	}
+	if location := mapping.Response.Headers["Location"]; !strings.HasPrefix(location, "{{request.query.redirect_uri}}?code="+AuthorizationCode) {
+		t.Errorf("expected redirect with code; got %q", location)
+	}
+	if len(mapping.Response.Transformers) != 1 || mapping.Response.Transformers[0] != wiremock.ResponseTemplateTransformer {
+		t.Errorf("expected response templating; got %v", mapping.Response.Transformers)
+	}
+}
+
+func TestProvider_UserinfoStub(t *testing.T) {
+	provider, err := NewProvider("http://localhost:8080/idp")
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+
+	stubRule := provider.WithClaim("email", "alice@example.com").UserinfoStub()
+	provider.WithClaim("roles", []string{"admin"})
+
+	body, err := json.Marshal(stubRule)
+	if err != nil {
+		t.Fatalf("UserinfoStub json.Marshal error: %v", err)
+	}
+
+	var mapping struct {
+		Response struct {
+			JSONBody map[string]interface{} `json:"jsonBody"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &mapping); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+
+	claims := mapping.Response.JSONBody
+	if len(claims) != 2 || claims["sub"] != "test-user" || claims["email"] != "alice@example.com" {
+		t.Errorf("unexpected userinfo claims %v", claims)
+	}
+}
@@ -0,0 +1,118 @@
+// Package wiremockproto builds body matchers of protobuf messages, so gRPC-gateway and HTTP APIs
+// sending application/x-protobuf payloads can be stubbed and verified without wrangling bytes.
+//
+// The package does not depend on a protobuf runtime, messages are serialized by the functions of the runtime
+// the tests use, e.g. of google.golang.org/protobuf:
+//
+//	codec := wiremockproto.NewCodec(func(message interface{}) ([]byte, error) {
+//		return proto.MarshalOptions{Deterministic: true}.Marshal(message.(proto.Message))
+//	})
+//	matcher, err := codec.Matcher(&orderpb.CreateOrderRequest{Id: 42})
+//	// ...
+//	stubRule := wiremock.Post(wiremock.URLPathEqualTo("/orders")).WithBodyPattern(matcher)
+//
+// The binaryEqualTo matcher compares bytes, so the message must be serialized deterministically
+// and the same way the tested service does, unknown fields and field order included.
+// When the server runs an extension converting protobuf bodies to JSON with the descriptors of the messages,
+// WithJSONTransform matches the JSON form instead, which does not depend on the wire encoding.
+package wiremockproto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// ContentType is the content type of protobuf bodies.
+const ContentType = "application/x-protobuf"
+
+// MarshalFunc serializes the protobuf message, e.g. with proto.Marshal or protojson.Marshal.
+type MarshalFunc func(message interface{}) ([]byte, error)
+
+// A Codec builds body matchers of protobuf messages.
+type Codec struct {
+	marshal MarshalFunc
+	// marshalJSON is set when the server matches the JSON form of the messages
+	marshalJSON MarshalFunc
+	jsonFlags   []wiremock.EqualFlag
+}
+
+// NewCodec returns *Codec serializing messages to the wire format with marshal.
+func NewCodec(marshal MarshalFunc) *Codec {
+	return &Codec{marshal: marshal}
+}
+
+// WithJSONTransform makes the matchers compare the JSON form of the messages serialized with marshalJSON,
+// e.g. with protojson.Marshal, and returns *Codec.
+// Use it only when the server has the extension converting protobuf bodies to JSON with the message descriptors,
+// WireMock itself matches the bodies as they are sent.
+func (c *Codec) WithJSONTransform(marshalJSON MarshalFunc, flags ...wiremock.EqualFlag) *Codec {
+	c.marshalJSON = marshalJSON
+	c.jsonFlags = flags
+	return c
+}
+
+// Marshal serializes the message to the wire format.
+func (c *Codec) Marshal(message interface{}) ([]byte, error) {
+	if c.marshal == nil {
+		return nil, fmt.Errorf("marshal protobuf message %T: marshal function is not set", message)
+	}
+
+	body, err := c.marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("marshal protobuf message %T: %s", message, err.Error())
+	}
+
+	return body, nil
+}
+
+// Matcher returns the body matcher of the message: binaryEqualTo of its wire format,
+// or equalToJson of its JSON form with WithJSONTransform.
+func (c *Codec) Matcher(message interface{}) (wiremock.ParamMatcher, error) {
+	if c.marshalJSON == nil {
+		body, err := c.Marshal(message)
+		if err != nil {
+			return wiremock.ParamMatcher{}, err
+		}
+
+		return wiremock.BinaryEqualTo(body), nil
+	}
+
+	body, err := c.marshalJSON(message)
+	if err != nil {
+		return wiremock.ParamMatcher{}, fmt.Errorf("marshal protobuf message %T to json: %s", message, err.Error())
+	}
+
+	return wiremock.EqualToJson(string(body), c.jsonFlags...), nil
+}
+
+// Request returns *wiremock.Request of the method and the url with the body of the message,
+// the criteria of the protobuf request for verification.
+func (c *Codec) Request(method string, urlMatcher wiremock.URLMatcherInterface, message interface{}) (*wiremock.Request, error) {
+	matcher, err := c.Matcher(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return wiremock.NewRequest(method, urlMatcher).WithBodyPattern(matcher), nil
+}
+
+// WillReturnMessage sets the response of stubRule with the wire format of the message and returns *wiremock.StubRule.
+// Content-Type is ContentType unless headers set it.
+func (c *Codec) WillReturnMessage(stubRule *wiremock.StubRule, message interface{}, headers map[string]string, status int64) (*wiremock.StubRule, error) {
+	body, err := c.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	responseHeaders := map[string]string{"Content-Type": ContentType}
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			delete(responseHeaders, "Content-Type")
+		}
+		responseHeaders[key] = value
+	}
+
+	return stubRule.WillReturnBinary(body, responseHeaders, status), nil
+}
@@ -0,0 +1,86 @@
+package wiremockproto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// order is the message of field 1 id, serialized by hand as protobuf runtimes do.
+type order struct {
+	ID int `json:"id"`
+}
+
+func marshalOrder(message interface{}) ([]byte, error) {
+	o, ok := message.(*order)
+	if !ok {
+		return nil, errors.New("not an order")
+	}
+
+	return []byte{0x08, byte(o.ID)}, nil
+}
+
+func marshalOrderJSON(message interface{}) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+func TestCodec_Matcher(t *testing.T) {
+	codec := NewCodec(marshalOrder)
+
+	criteria, err := codec.Request("POST", wiremock.URLPathEqualTo("/orders"), &order{ID: 42})
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+	raw, err := criteria.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Request MarshalJSON error: %v", err)
+	}
+	if expected := `"bodyPatterns":[{"binaryEqualTo":"CCo="}]`; !strings.Contains(string(raw), expected) {
+		t.Errorf("expected request to contain %s; got %s", expected, raw)
+	}
+
+	for _, tc := range []struct {
+		body     []byte
+		expected bool
+	}{
+		{body: []byte{0x08, 42}, expected: true},
+		{body: []byte{0x08, 43}, expected: false},
+	} {
+		matched, known := criteria.Match(&wiremock.LoggedRequest{Method: "POST", URL: "/orders", Body: tc.body})
+		if matched != tc.expected || !known {
+			t.Errorf("expected body %v matched %v; got %v, %v", tc.body, tc.expected, matched, known)
+		}
+	}
+
+	if _, err := codec.Matcher("not an order"); err == nil || !strings.Contains(err.Error(), "not an order") {
+		t.Errorf("expected marshal error; got %v", err)
+	}
+
+	matcher, err := codec.WithJSONTransform(marshalOrderJSON, wiremock.IgnoreExtraElements).Matcher(&order{ID: 42})
+	if err != nil {
+		t.Fatalf("Matcher error: %v", err)
+	}
+	if matcher.Strategy() != wiremock.ParamEqualToJson || matcher.Value() != `{"id":42}` || !matcher.Flags()[string(wiremock.IgnoreExtraElements)] {
+		t.Errorf("expected equalToJson of the message; got %s %s %v", matcher.Strategy(), matcher.Value(), matcher.Flags())
+	}
+}
+
+func TestCodec_WillReturnMessage(t *testing.T) {
+	stubRule, err := NewCodec(marshalOrder).WillReturnMessage(wiremock.Get(wiremock.URLPathEqualTo("/orders/42")), &order{ID: 42}, nil, 200)
+	if err != nil {
+		t.Fatalf("WillReturnMessage error: %v", err)
+	}
+
+	raw, err := json.Marshal(stubRule.Response())
+	if err != nil {
+		t.Fatalf("Response json.Marshal error: %v", err)
+	}
+	expected := fmt.Sprintf(`{"base64Body":"CCo=","headers":{"Content-Type":"%s"},"status":200}`, ContentType)
+	if string(raw) != expected {
+		t.Errorf("expected response %s; got %s", expected, raw)
+	}
+}
@@ -0,0 +1,147 @@
+package wiremocktest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// UpdateGoldenEnv is the environment variable which makes golden assertions rewrite the golden files, e.g.
+//
+//	WIREMOCK_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "WIREMOCK_UPDATE_GOLDEN"
+
+// AssertGoldenStub fails the test when the canonical JSON of the stub differs from the golden file at path,
+// reporting the line diff. The canonical JSON is indented with sorted keys and without the stub id,
+// which is random unless set explicitly.
+func AssertGoldenStub(t testing.TB, path string, stub *wiremock.StubRule) {
+	t.Helper()
+
+	assertGolden(t, path, func() (interface{}, error) {
+		return canonicalStub(stub)
+	})
+}
+
+// AssertGoldenStubs is AssertGoldenStub for the set of stubs, rendered in the WireMock mappings file format.
+func AssertGoldenStubs(t testing.TB, path string, stubs []*wiremock.StubRule) {
+	t.Helper()
+
+	assertGolden(t, path, func() (interface{}, error) {
+		mappings := make([]interface{}, len(stubs))
+		for i, stub := range stubs {
+			mapping, err := canonicalStub(stub)
+			if err != nil {
+				return nil, err
+			}
+			mappings[i] = mapping
+		}
+		return map[string]interface{}{"mappings": mappings}, nil
+	})
+}
+
+func assertGolden(t testing.TB, path string, canonical func() (interface{}, error)) {
+	t.Helper()
+
+	value, err := canonical()
+	if err != nil {
+		t.Fatalf("wiremock: golden %s: %s", path, err.Error())
+		return
+	}
+	actual, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("wiremock: golden %s: %s", path, err.Error())
+		return
+	}
+	actual = append(actual, '\n')
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("wiremock: golden %s: %s", path, err.Error())
+			return
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("wiremock: golden %s: %s", path, err.Error())
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("wiremock: golden %s does not exist, run with %s=1 to create it", path, UpdateGoldenEnv)
+		return
+	}
+	if err != nil {
+		t.Fatalf("wiremock: golden %s: %s", path, err.Error())
+		return
+	}
+
+	if string(expected) != string(actual) {
+		t.Fatalf("wiremock: stub JSON differs from golden %s (run with %s=1 to update):\n%s",
+			path, UpdateGoldenEnv, lineDiff(string(expected), string(actual)))
+	}
+}
+
+func canonicalStub(stub *wiremock.StubRule) (interface{}, error) {
+	raw, err := stub.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping map[string]interface{}
+	if err := json.Unmarshal(raw, &mapping); err != nil {
+		return nil, err
+	}
+	delete(mapping, "id")
+	delete(mapping, "uuid")
+
+	return mapping, nil
+}
+
+// lineDiff renders the longest common subsequence diff of lines, prefixing missing lines with "-" and extra with "+".
+func lineDiff(expected, actual string) string {
+	a := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&sb, "  %s\n", a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || common[i][j+1] >= common[i+1][j]):
+			fmt.Fprintf(&sb, "+ %s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(&sb, "- %s\n", a[i])
+			i++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package wiremocktest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+func TestAssertGoldenStub(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "orders.json")
+	stub := wiremock.Get(wiremock.URLPathEqualTo("/orders")).WillReturnJSON([]string{"a"}, nil, 200)
+
+	recorder := &recordingT{TB: t}
+	AssertGoldenStub(recorder, path, stub)
+	if !strings.Contains(recorder.failure, "does not exist") {
+		t.Fatalf("expected missing golden failure; got %q", recorder.failure)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	recorder = &recordingT{TB: t}
+	AssertGoldenStub(recorder, path, stub)
+	if recorder.failure != "" {
+		t.Fatalf("expected golden to be written; got %q", recorder.failure)
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if strings.Contains(string(golden), stub.UUID()) || !strings.Contains(string(golden), `"urlPath": "/orders"`) {
+		t.Errorf("unexpected golden content:\n%s", golden)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	recorder = &recordingT{TB: t}
+	AssertGoldenStub(recorder, path, wiremock.Get(wiremock.URLPathEqualTo("/orders")).WillReturnJSON([]string{"a"}, nil, 200))
+	if recorder.failure != "" {
+		t.Fatalf("expected stub with other id to match golden; got %q", recorder.failure)
+	}
+
+	recorder = &recordingT{TB: t}
+	AssertGoldenStub(recorder, path, wiremock.Get(wiremock.URLPathEqualTo("/orders")).WillReturnJSON([]string{"a"}, nil, 201))
+	for _, expected := range []string{`-     "status": 200`, `+     "status": 201`, `      "urlPath": "/orders"`} {
+		if !strings.Contains(recorder.failure, expected) {
+			t.Errorf("expected diff line %q; got %s", expected, recorder.failure)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package wiremocktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// callbacksBuffer is the number of callbacks the channel of WebhookReceiver keeps until they are read.
+const callbacksBuffer = 64
+
+// A Callback is the webhook request received by WebhookReceiver.
+type Callback struct {
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+	ReceivedAt time.Time
+}
+
+// BodyAsJSON decodes the JSON body of the callback into v.
+func (c *Callback) BodyAsJSON(v interface{}) error {
+	if err := json.Unmarshal(c.Body, v); err != nil {
+		return fmt.Errorf("decode callback body: %s", err.Error())
+	}
+
+	return nil
+}
+
+// A WebhookReceiverOption sets the option of WebhookReceiver.
+type WebhookReceiverOption func(r *webhookReceiverOptions)
+
+type webhookReceiverOptions struct {
+	host string
+}
+
+// WithAdvertisedHost makes the receiver listen on all interfaces and give its urls with host,
+// for WireMock running elsewhere, e.g. WithAdvertisedHost("host.docker.internal") for WireMock in Docker.
+func WithAdvertisedHost(host string) WebhookReceiverOption {
+	return func(r *webhookReceiverOptions) {
+		r.host = host
+	}
+}
+
+// A WebhookReceiver is the local http server receiving webhooks of the stubs, closed when the test finishes:
+//
+//	receiver := wiremocktest.NewWebhookReceiver(t)
+//	err := client.StubFor(wiremock.Post(wiremock.URLPathEqualTo("/payments")).
+//		WithWebhook(receiver.Webhook(http.MethodPost, "/payments/callback").WithBody(`{"status":"PAID"}`)))
+//	...
+//	callback := receiver.AwaitCallback(t, 5*time.Second)
+//
+// It answers every request with 200 OK.
+type WebhookReceiver struct {
+	server    *httptest.Server
+	baseURL   string
+	callbacks chan Callback
+
+	mu       sync.Mutex
+	received []Callback
+	// awaited is the number of callbacks given by AwaitCallback
+	awaited int
+	// changed is closed and replaced when a callback is received
+	changed chan struct{}
+}
+
+// NewWebhookReceiver starts WebhookReceiver listening on the loopback interface.
+func NewWebhookReceiver(t testing.TB, options ...WebhookReceiverOption) *WebhookReceiver {
+	t.Helper()
+
+	var receiverOptions webhookReceiverOptions
+	for _, option := range options {
+		option(&receiverOptions)
+	}
+
+	r := &WebhookReceiver{
+		callbacks: make(chan Callback, callbacksBuffer),
+		changed:   make(chan struct{}),
+	}
+	r.server = httptest.NewUnstartedServer(http.HandlerFunc(r.serveHTTP))
+	if receiverOptions.host != "" {
+		listener, err := net.Listen("tcp", ":0")
+		if err != nil {
+			t.Fatalf("wiremock: webhook receiver: %s", err.Error())
+			return nil
+		}
+		_ = r.server.Listener.Close()
+		r.server.Listener = listener
+	}
+	r.server.Start()
+	t.Cleanup(r.server.Close)
+
+	r.baseURL = r.server.URL
+	if receiverOptions.host != "" {
+		_, port, _ := net.SplitHostPort(r.server.Listener.Addr().String())
+		r.baseURL = "http://" + net.JoinHostPort(receiverOptions.host, port)
+	}
+
+	return r
+}
+
+func (r *WebhookReceiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	callback := Callback{
+		Method:     req.Method,
+		URL:        req.URL.RequestURI(),
+		Header:     req.Header.Clone(),
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+
+	r.mu.Lock()
+	r.received = append(r.received, callback)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.mu.Unlock()
+
+	select {
+	case r.callbacks <- callback:
+	default:
+	}
+}
+
+// URL gives the url of path on the receiver.
+func (r *WebhookReceiver) URL(path string) string {
+	return r.baseURL + path
+}
+
+// Webhook returns *wiremock.Webhook sending request with method to path on the receiver.
+func (r *WebhookReceiver) Webhook(method, path string) *wiremock.Webhook {
+	return wiremock.NewWebhook(method, r.URL(path))
+}
+
+// Route points the webhooks of the stub to the receiver, keeping their paths and queries, and returns the stub,
+// so stubs written for the real callback urls can be tested locally.
+func (r *WebhookReceiver) Route(stub *wiremock.StubRule) *wiremock.StubRule {
+	for _, webhook := range stub.Webhooks() {
+		path := webhook.URL()
+		if parsed, err := url.Parse(path); err == nil && parsed.Host != "" {
+			path = parsed.RequestURI()
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		webhook.WithURL(r.URL(path))
+	}
+
+	return stub
+}
+
+// Callbacks gives the channel of the received callbacks. It keeps the buffer of callbacks not read yet,
+// others are skipped by the channel, but not by Received and AwaitCallback.
+func (r *WebhookReceiver) Callbacks() <-chan Callback {
+	return r.callbacks
+}
+
+// Received gives the callbacks received so far, in the order they were received.
+func (r *WebhookReceiver) Received() []Callback {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Callback(nil), r.received...)
+}
+
+// AwaitCallback gives the next callback not given by AwaitCallback before, waiting for it until timeout,
+// and fails the test when it is not received.
+func (r *WebhookReceiver) AwaitCallback(t testing.TB, timeout time.Duration) Callback {
+	t.Helper()
+
+	callbacks := r.await(r.awaitedCount()+1, timeout)
+	if callbacks == nil {
+		t.Fatalf("wiremock: webhook receiver: no callback received in %s, %d received before", timeout, r.awaitedCount())
+		return Callback{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	callback := r.received[r.awaited]
+	r.awaited++
+	return callback
+}
+
+// AwaitCallbacks waits until count callbacks are received in total or timeout, gives them
+// and fails the test when fewer are received.
+func (r *WebhookReceiver) AwaitCallbacks(t testing.TB, count int, timeout time.Duration) []Callback {
+	t.Helper()
+
+	callbacks := r.await(count, timeout)
+	if callbacks == nil {
+		t.Fatalf("wiremock: webhook receiver: expected %d callback(s) in %s, received %d", count, timeout, len(r.Received()))
+		return nil
+	}
+
+	return callbacks
+}
+
+// AssertNoCallbacks fails the test when any callback is received, waiting for one for the duration.
+func (r *WebhookReceiver) AssertNoCallbacks(t testing.TB, within time.Duration) {
+	t.Helper()
+
+	if callbacks := r.await(1, within); callbacks != nil {
+		t.Fatalf("wiremock: webhook receiver: expected no callbacks, received %s %s", callbacks[0].Method, callbacks[0].URL)
+	}
+}
+
+// await waits until count callbacks are received or timeout and gives the received ones, nil when fewer are received.
+func (r *WebhookReceiver) await(count int, timeout time.Duration) []Callback {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		r.mu.Lock()
+		received, changed := r.received, r.changed
+		r.mu.Unlock()
+
+		if len(received) >= count {
+			return append([]Callback(nil), received...)
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return nil
+		}
+	}
+}
+
+func (r *WebhookReceiver) awaitedCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.awaited
+}
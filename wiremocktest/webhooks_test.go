@@ -0,0 +1,58 @@
+package wiremocktest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+func TestWebhookReceiver(t *testing.T) {
+	receiver := NewWebhookReceiver(t)
+
+	stub := receiver.Route(wiremock.Post(wiremock.URLPathEqualTo("/payments")).
+		WithWebhook(wiremock.NewWebhook(http.MethodPost, "https://shop.example/callbacks/payments?v=2")))
+	if url := stub.Webhooks()[0].URL(); url != receiver.URL("/callbacks/payments?v=2") {
+		t.Fatalf("expected webhook routed to the receiver; got %s", url)
+	}
+
+	recorder := &recordingT{TB: t}
+	receiver.AssertNoCallbacks(recorder, 10*time.Millisecond)
+	if recorder.failure != "" {
+		t.Errorf("expected no callbacks; got %s", recorder.failure)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		for _, status := range []string{"PENDING", "PAID"} {
+			res, err := http.Post(stub.Webhooks()[0].URL(), wiremock.ContentTypeJSON, strings.NewReader(`{"status":"`+status+`"}`))
+			if err == nil {
+				_ = res.Body.Close()
+			}
+		}
+	}()
+
+	callback := receiver.AwaitCallback(t, time.Second)
+	var payment struct {
+		Status string `json:"status"`
+	}
+	if err := callback.BodyAsJSON(&payment); err != nil || payment.Status != "PENDING" || callback.URL != "/callbacks/payments?v=2" {
+		t.Errorf("unexpected first callback %+v, %v", callback, err)
+	}
+	if callback = receiver.AwaitCallback(t, time.Second); !strings.Contains(string(callback.Body), "PAID") {
+		t.Errorf("expected second callback; got %s", callback.Body)
+	}
+	if received := <-receiver.Callbacks(); received.Header.Get("Content-Type") != wiremock.ContentTypeJSON {
+		t.Errorf("unexpected callback of the channel %+v", received)
+	}
+
+	receiver.AwaitCallback(recorder, 10*time.Millisecond)
+	if !strings.Contains(recorder.failure, "no callback received") {
+		t.Errorf("expected await failure; got %q", recorder.failure)
+	}
+	if callbacks := receiver.AwaitCallbacks(t, 2, time.Second); len(callbacks) != 2 {
+		t.Errorf("expected 2 callbacks; got %d", len(callbacks))
+	}
+}
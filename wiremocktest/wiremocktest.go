@@ -0,0 +1,65 @@
+// Package wiremocktest provides helpers for tests using WireMock through the wiremock client.
+package wiremocktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// RequireNoUnmatchedRequests fails the test when WireMock received requests not matched by any stub.
+// Every unmatched request is reported with the diff against its closest stubs.
+// The test fails as well when the server runs without the request journal, as unmatched requests cannot be checked then.
+// It is meant to run at the end of the test:
+//
+//	t.Cleanup(func() { wiremocktest.RequireNoUnmatchedRequests(t, client) })
+func RequireNoUnmatchedRequests(t testing.TB, client *wiremock.Client) {
+	t.Helper()
+
+	unmatched, err := client.FindUnmatchedRequests()
+	if err != nil {
+		t.Fatalf("wiremock: %s", err.Error())
+		return
+	}
+	if len(unmatched) == 0 {
+		return
+	}
+
+	nearMisses, err := client.FindNearMissesForUnmatched()
+	if err != nil {
+		t.Fatalf("wiremock: %s", err.Error())
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "wiremock: %d unmatched request(s)", len(unmatched))
+	for _, request := range unmatched {
+		b.WriteString("\n\n")
+
+		rendered := false
+		for i := range nearMisses {
+			if sameRequest(nearMisses[i].Request, request) {
+				if rendered {
+					b.WriteString("\n")
+				}
+				b.WriteString(strings.TrimSuffix(nearMisses[i].Diff(), "\n"))
+				rendered = true
+			}
+		}
+		if !rendered {
+			fmt.Fprintf(&b, "%s %s\nno stub is close to this request", request.Method, request.URL)
+		}
+	}
+
+	t.Fatal(b.String())
+}
+
+func sameRequest(a, b wiremock.LoggedRequest) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+
+	return a.Method == b.Method && a.URL == b.URL && a.LoggedDate.Equal(b.LoggedDate)
+}
@@ -0,0 +1,77 @@
+package wiremocktest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/walkerus/go-wiremock"
+)
+
+// recordingT records fatal failures instead of stopping the test.
+type recordingT struct {
+	testing.TB
+	failure string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Fatal(args ...interface{}) {
+	t.failure = fmt.Sprint(args...)
+}
+
+func (t *recordingT) Fatalf(format string, args ...interface{}) {
+	t.failure = fmt.Sprintf(format, args...)
+}
+
+func TestRequireNoUnmatchedRequests(t *testing.T) {
+	unmatched := `{"requests": []}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/__admin/requests/unmatched":
+			_, _ = w.Write([]byte(unmatched))
+		case "/__admin/requests/unmatched/near-misses":
+			_, _ = w.Write([]byte(`{"nearMisses": [{
+				"request": {"id": "r1", "method": "GET", "url": "/orders?page=2", "headers": {"Accept": "text/html"}},
+				"stubMapping": {"id": "s1", "request": {"method": "GET", "urlPath": "/orders",
+					"headers": {"Accept": {"equalTo": "application/json"}},
+					"queryParameters": {"page": {"matches": "[0-9]+"}}}},
+				"matchResult": {"distance": 0.25}
+			}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := wiremock.NewClient(server.URL)
+
+	recorder := &recordingT{TB: t}
+	RequireNoUnmatchedRequests(recorder, client)
+	if recorder.failure != "" {
+		t.Fatalf("expected no failure; got %s", recorder.failure)
+	}
+
+	unmatched = `{"requests": [{"id": "r1", "method": "GET", "url": "/orders?page=2"}, {"id": "r2", "method": "POST", "url": "/unknown"}]}`
+	RequireNoUnmatchedRequests(recorder, client)
+	for _, expected := range []string{
+		"2 unmatched request(s)",
+		"closest stub s1 (distance 0.25)",
+		"  urlPath: /orders",
+		"- header Accept equalTo: application/json\n+ text/html",
+		"  query page matches: [0-9]+",
+		"POST /unknown\nno stub is close to this request",
+	} {
+		if !strings.Contains(recorder.failure, expected) {
+			t.Errorf("expected failure to contain %q; got\n%s", expected, recorder.failure)
+		}
+	}
+
+	unmatched = `{"requests": [], "requestJournalDisabled": true}`
+	recorder.failure = ""
+	RequireNoUnmatchedRequests(recorder, client)
+	if !strings.Contains(recorder.failure, wiremock.ErrRequestJournalDisabled.Error()) {
+		t.Errorf("expected failure of the disabled journal; got %q", recorder.failure)
+	}
+}